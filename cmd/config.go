@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	FlagConfig  = "config"
+	FlagProfile = "profile"
+)
+
+const (
+	// configDirName is the directory holding the default config file, in the user config directory.
+	configDirName = "axone-mcp"
+	// profilesKey is the key of the config file holding the named profiles.
+	profilesKey = "profiles"
+)
+
+func init() {
+	rootCmd.PersistentFlags().String(FlagConfig, "",
+		"Path to a YAML or TOML config file, whose keys are the flag names (default $XDG_CONFIG_HOME/axone-mcp/config.yaml "+
+			"if it exists)")
+	_ = viper.BindPFlag(FlagConfig, rootCmd.PersistentFlags().Lookup(FlagConfig))
+
+	rootCmd.PersistentFlags().String(FlagProfile, "",
+		"Named profile of the config file to apply, overriding the top-level settings of the file")
+	_ = viper.BindPFlag(FlagProfile, rootCmd.PersistentFlags().Lookup(FlagProfile))
+}
+
+// LoadConfigRunE reads the config file, if any, and applies the selected profile. The flags and environment
+// variables still take precedence over the settings of the file.
+func LoadConfigRunE(_ *cobra.Command, _ []string) error {
+	return loadConfig(viper.GetViper(), viper.GetString(FlagConfig), viper.GetString(FlagProfile))
+}
+
+// loadConfig reads into v the given config file, or the one at the default location if it exists, then applies the
+// given profile, or the one selected by the file itself.
+func loadConfig(v *viper.Viper, file, profile string) error {
+	if file != "" {
+		v.SetConfigFile(file)
+	} else {
+		v.SetConfigName("config")
+		if dir, err := os.UserConfigDir(); err == nil {
+			v.AddConfigPath(filepath.Join(dir, configDirName))
+		}
+	}
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if file != "" || !errors.As(err, &notFound) {
+			return fmt.Errorf("read config file: %w", err)
+		}
+	}
+
+	profile = cmp.Or(profile, v.GetString(FlagProfile))
+	if profile == "" {
+		return nil
+	}
+	settings := v.Sub(profilesKey + "." + profile)
+	if settings == nil {
+		available := slices.Sorted(maps.Keys(v.GetStringMap(profilesKey)))
+		return fmt.Errorf("unknown profile %q, available: %s", profile, cmp.Or(strings.Join(available, ", "), "none"))
+	}
+
+	return v.MergeConfigMap(settings.AllSettings())
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/viper"
+)
+
+const testConfig = `
+profile: dentrite
+grpc-timeout: 5s
+read-only: true
+
+profiles:
+  dentrite:
+    node-grpc:
+      - grpc.dentrite.axone.xyz:443
+    dataverse-addr: axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w
+  local:
+    node-grpc:
+      - 127.0.0.1:9090
+    grpc-no-tls: true
+    grpc-timeout: 2s
+`
+
+func TestLoadConfig(t *testing.T) {
+	Convey("Given a config file defining profiles", t, func() {
+		file := filepath.Join(t.TempDir(), "config.yaml")
+		So(os.WriteFile(file, []byte(testConfig), 0o600), ShouldBeNil)
+		v := viper.New()
+
+		Convey("When loading it without selecting a profile", func() {
+			err := loadConfig(v, file, "")
+
+			Convey("Then the profile selected by the file should be applied over the top-level settings", func() {
+				So(err, ShouldBeNil)
+				So(v.GetStringSlice(FlagNodeGrpc), ShouldResemble, []string{"grpc.dentrite.axone.xyz:443"})
+				So(v.GetString(FlagDataverseAddr), ShouldEqual,
+					"axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w")
+				So(v.GetDuration(FlagGrpcTimeout), ShouldEqual, 5*time.Second)
+				So(v.GetBool(FlagReadOnly), ShouldBeTrue)
+			})
+		})
+
+		Convey("When loading it with the local profile", func() {
+			err := loadConfig(v, file, "local")
+
+			Convey("Then the local profile should be applied", func() {
+				So(err, ShouldBeNil)
+				So(v.GetStringSlice(FlagNodeGrpc), ShouldResemble, []string{"127.0.0.1:9090"})
+				So(v.GetBool(FlagGrpcNoTLS), ShouldBeTrue)
+				So(v.GetDuration(FlagGrpcTimeout), ShouldEqual, 2*time.Second)
+				So(v.GetString(FlagDataverseAddr), ShouldBeEmpty)
+			})
+		})
+
+		Convey("When a flag overrides a setting of the profile", func() {
+			v.Set(FlagGrpcTimeout, "1s")
+			err := loadConfig(v, file, "local")
+
+			Convey("Then the flag should take precedence", func() {
+				So(err, ShouldBeNil)
+				So(v.GetDuration(FlagGrpcTimeout), ShouldEqual, time.Second)
+			})
+		})
+
+		Convey("When loading it with an unknown profile", func() {
+			err := loadConfig(v, file, "mainnet")
+
+			Convey("Then it should fail, listing the available profiles", func() {
+				So(err, ShouldBeError, `unknown profile "mainnet", available: dentrite, local`)
+			})
+		})
+	})
+
+	Convey("Given a TOML config file", t, func() {
+		file := filepath.Join(t.TempDir(), "config.toml")
+		So(os.WriteFile(file, []byte("[profiles.local]\nnode-grpc = [\"127.0.0.1:9090\"]\n"), 0o600), ShouldBeNil)
+		v := viper.New()
+
+		Convey("When loading it with a profile", func() {
+			err := loadConfig(v, file, "local")
+
+			Convey("Then the profile should be applied", func() {
+				So(err, ShouldBeNil)
+				So(v.GetStringSlice(FlagNodeGrpc), ShouldResemble, []string{"127.0.0.1:9090"})
+			})
+		})
+	})
+
+	Convey("Given a config file that does not exist", t, func() {
+		v := viper.New()
+
+		Convey("When it is given explicitly", func() {
+			err := loadConfig(v, filepath.Join(t.TempDir(), "missing.yaml"), "")
+
+			Convey("Then loading it should fail", func() {
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("When it is looked up at the default location", func() {
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			err := loadConfig(v, "", "")
+
+			Convey("Then loading should succeed with no setting", func() {
+				So(err, ShouldBeNil)
+				So(v.ConfigFileUsed(), ShouldBeEmpty)
+			})
+		})
+	})
+}
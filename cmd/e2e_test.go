@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	goctx "context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
+	"github.com/axone-protocol/axone-mcp/internal/fakenode"
+	"github.com/axone-protocol/axone-mcp/internal/version"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	testLawStone = "axone1wlp9d7cqehpnq35wenwz7nq0yp4wpg5jgf4fcyqd7uqfpnagtvysjtfzkn"
+	testResource = "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"
+)
+
+func TestEndToEnd(t *testing.T) {
+	Convey("Given a fake axone node running a dataverse", t, func() {
+		node, err := fakenode.Start(
+			fakenode.WithContract(testDataverse,
+				fakenode.Dataverse{Name: "my-dataverse", TriplestoreAddress: testTriplestore}),
+			fakenode.WithContract(testTriplestore, fakenode.NewCognitarium(
+				fakenode.GovernanceCredential("https://ex.org/cred1", testResource, testLawStone)...)),
+			fakenode.WithContract(testLawStone, fakenode.LawStone{Program: "permitted(read)."}),
+			fakenode.WithHeight(42),
+		)
+		So(err, ShouldBeNil)
+		Reset(node.Stop)
+
+		undeployed, err := bech32.ConvertAndEncode(address.Prefix, make([]byte, 32))
+		So(err, ShouldBeNil)
+
+		for _, tt := range []struct {
+			transport string
+			serve     func(node string) *client.Client
+		}{
+			{transport: "stdio", serve: serveStdioE2E},
+			{transport: "sse", serve: serveSseE2E},
+		} {
+			Convey(fmt.Sprintf("And a server connected to it over %s, with a client connected to the server", tt.transport), func() {
+				c := tt.serve(node.Addr())
+
+				Convey("When getting the dataverse info", func() {
+					result, err := callToolE2E(c, "get_dataverse_info", map[string]any{"dataverse": testDataverse})
+
+					Convey("Then the dataverse should be answered at the height of the node", func() {
+						So(err, ShouldBeNil)
+						So(result.IsError, ShouldBeFalse)
+						So(textE2E(result), ShouldEqual,
+							fmt.Sprintf(`{"name":"my-dataverse","triplestore_address":%q}`, testTriplestore))
+						So(result.Meta["height"], ShouldEqual, 42)
+					})
+				})
+
+				Convey("When getting the governance code of a resource", func() {
+					result, err := callToolE2E(c, "get_resource_governance_code",
+						map[string]any{"dataverse": testDataverse, "resource": testResource})
+
+					Convey("Then the program of the law-stone governing it should be answered", func() {
+						So(err, ShouldBeNil)
+						So(result.IsError, ShouldBeFalse)
+						So(textE2E(result), ShouldEqual, "permitted(read).")
+					})
+				})
+
+				Convey("When getting the info of a dataverse not deployed", func() {
+					result, err := callToolE2E(c, "get_dataverse_info", map[string]any{"dataverse": undeployed})
+
+					Convey("Then the contract should not be found", func() {
+						So(err, ShouldBeNil)
+						So(result.IsError, ShouldBeTrue)
+						So(result.Meta["error"].(map[string]any)["code"], ShouldEqual, "CONTRACT_NOT_FOUND")
+					})
+				})
+
+				Convey("When getting the dataverse info at a height beyond the latest block", func() {
+					result, err := callToolE2E(c, "get_dataverse_info", map[string]any{"dataverse": testDataverse, "height": 43})
+
+					Convey("Then the height should be reported as not available", func() {
+						So(err, ShouldBeNil)
+						So(result.IsError, ShouldBeTrue)
+						So(result.Meta["error"].(map[string]any)["code"], ShouldEqual, "HEIGHT_UNAVAILABLE")
+					})
+				})
+			})
+		}
+	})
+}
+
+// serveStdioE2E runs serve stdio against the node at the given address until the end of the test, and returns a
+// client connected to it.
+func serveStdioE2E(addr string) *client.Client {
+	stdinR, stdinW := io.Pipe()
+	stdoutR, stdoutW := io.Pipe()
+	origStdin, origStdout, origStderr := MCPStdin, MCPStdout, MCPStderr
+	MCPStdin, MCPStdout, MCPStderr = stdinR, stdoutW, io.Discard
+	Reset(func() {
+		MCPStdin, MCPStdout, MCPStderr = origStdin, origStdout, origStderr
+	})
+
+	runE2E(goctx.Background(), serveStdioCmd, addr, "serve", "stdio")
+
+	c := client.NewClient(transport.NewIO(stdoutR, stdinW, io.NopCloser(strings.NewReader(""))))
+	startE2E(c)
+	Reset(func() {
+		_ = stdinW.Close()
+		_ = stdoutW.Close()
+	})
+
+	return c
+}
+
+// serveSseE2E runs serve sse against the node at the given address until the end of the test, and returns a client
+// connected to it.
+func serveSseE2E(addr string) *client.Client {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	So(err, ShouldBeNil)
+
+	runE2E(WithListener(goctx.Background(), listener), serveSseCmd, addr, "serve", "sse")
+
+	c, err := client.NewSSEMCPClient("http://" + listener.Addr().String() + "/sse")
+	So(err, ShouldBeNil)
+	startE2E(c)
+
+	return c
+}
+
+// runE2E executes the given command with the given context and arguments, connected without TLS to the node at the
+// given address, until the end of the test.
+func runE2E(ctx goctx.Context, cmd *cobra.Command, node string, args ...string) {
+	origArgs := os.Args
+	os.Args = append(append([]string{version.Name}, args...), "--"+FlagGrpcNoTLS)
+	Reset(func() { os.Args = origArgs })
+	// replaced rather than parsed, as a slice flag parsed again appends to its former values.
+	nodeGrpc := rootCmd.PersistentFlags().Lookup(FlagNodeGrpc)
+	So(nodeGrpc.Value.(pflag.SliceValue).Replace([]string{node}), ShouldBeNil)
+	nodeGrpc.Changed = true
+
+	executeSubcommand(ctx, cmd)
+}
+
+// startE2E starts and initializes the given client, waiting for the server to be up.
+func startE2E(c *client.Client) {
+	ctx, cancel := goctx.WithTimeout(goctx.Background(), testTimeout)
+	defer cancel()
+	Reset(func() { _ = c.Close() })
+
+	var err error
+	for {
+		// started without deadline, the client streaming the responses of the server as long as it lives.
+		if err = c.Start(goctx.Background()); err == nil {
+			_, err = c.Initialize(ctx, mcp.InitializeRequest{})
+		}
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	So(err, ShouldBeNil)
+}
+
+// callToolE2E calls the tool of the given name with the given arguments.
+func callToolE2E(c *client.Client, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	ctx, cancel := goctx.WithTimeout(goctx.Background(), testTimeout)
+	defer cancel()
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+
+	return c.CallTool(ctx, request)
+}
+
+// textE2E returns the text of the first content of the given tool result.
+func textE2E(result *mcp.CallToolResult) string {
+	if len(result.Content) == 0 {
+		return ""
+	}
+	text, _ := result.Content[0].(mcp.TextContent)
+
+	return text.Text
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/inspect"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FlagTransport   = "transport"
+	FlagHeader      = "header"
+	FlagCalls       = "calls"
+	FlagInteractive = "interactive"
+	FlagServerLogs  = "server-logs"
+)
+
+var inspectCmd = &cobra.Command{
+	Use:   "inspect [flags] <url | command [args...]>",
+	Short: "Inspect a running MCP server: list its features and call its tools",
+	Long: `Connect to an MCP server, over SSE or streamable HTTP given its URL, or over stdio given the command running it,
+then list its tools, resources and prompts with their schemas, or call its tools from a calls file or interactively.
+
+The flags must precede the server, whose command arguments are passed as is, e.g.:
+  axone-mcp inspect https://mcp.example.com/sse
+  axone-mcp inspect --calls calls.json axone-mcp serve stdio --node-grpc grpc.dentrite.axone.xyz:443`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		target, err := buildInspectTarget(cmd, args)
+		if err != nil {
+			return err
+		}
+		c, err := inspect.Connect(cmd.Context(), target)
+		if err != nil {
+			return err
+		}
+		output, _ := cmd.Flags().GetString(flagOutput)
+		inspector, err := inspect.New(cmd.Context(), c, cmd.OutOrStdout(), output)
+		if err != nil {
+			_ = c.Close()
+			return err
+		}
+		defer inspector.Close()
+
+		if interactive, _ := cmd.Flags().GetBool(FlagInteractive); interactive {
+			return inspector.Interactive(cmd.Context(), cmd.InOrStdin())
+		}
+		if file, _ := cmd.Flags().GetString(FlagCalls); file != "" {
+			calls, err := readCallsFile(cmd, file)
+			if err != nil {
+				return err
+			}
+			return inspector.RunCalls(cmd.Context(), calls)
+		}
+
+		return inspector.List(cmd.Context())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inspectCmd)
+	inspectCmd.Flags().SetInterspersed(false)
+
+	inspectCmd.Flags().String(FlagTransport, inspect.TransportAuto,
+		`Transport reaching the server: "sse", "http" (streamable HTTP), "stdio", or "auto" to infer it from the server `+
+			`(an URL ending with /sse is reached over SSE, any other URL over HTTP, anything else is a command)`)
+	inspectCmd.Flags().StringArray(FlagHeader, nil,
+		`HTTP header sent to the server, as "<name>: <value>" (e.g. "Authorization: Bearer ..."), repeatable`)
+	inspectCmd.Flags().String(FlagCalls, "",
+		`Path to a JSON file of tool calls to make in order, as [{"name": "<tool>", "arguments": {...}}] ("-" for stdin)`)
+	inspectCmd.Flags().BoolP(FlagInteractive, "i", false,
+		"Read commands from stdin to list the features, call the tools and read the resources of the server")
+	inspectCmd.Flags().Bool(FlagServerLogs, false,
+		"Print the logs of the server run over stdio to stderr")
+	inspectCmd.Flags().StringP(flagOutput, "o", inspect.FormatText, "Output format (text|json|yaml)")
+
+	inspectCmd.MarkFlagsMutuallyExclusive(FlagCalls, FlagInteractive)
+}
+
+// buildInspectTarget returns the server to inspect described by the given arguments and flags.
+func buildInspectTarget(cmd *cobra.Command, args []string) (inspect.Target, error) {
+	target := inspect.Target{Address: args[0], Args: args[1:], Headers: map[string]string{}}
+	target.Transport, _ = cmd.Flags().GetString(FlagTransport)
+	if logs, _ := cmd.Flags().GetBool(FlagServerLogs); logs {
+		target.Stderr = cmd.ErrOrStderr()
+	}
+
+	headers, _ := cmd.Flags().GetStringArray(FlagHeader)
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return target, fmt.Errorf("--%s: invalid header %q, expected <name>: <value>", FlagHeader, header)
+		}
+		target.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+
+	return target, nil
+}
+
+// readCallsFile reads the tool calls of the given file, or of stdin if "-".
+func readCallsFile(cmd *cobra.Command, file string) ([]inspect.Call, error) {
+	var r io.Reader = cmd.InOrStdin()
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	return inspect.ReadCalls(r)
+}
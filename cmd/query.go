@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/mcp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+)
+
+const FlagHeight = "height"
+
+// queryCmd represents the base query command.
+var queryCmd = &cobra.Command{
+	Use:   "query",
+	Short: "Query the Axone chain as the MCP tools do",
+	Long: `Call the handler of an MCP tool querying the Axone chain, without any MCP client, and print its result.
+The dataverse argument defaults to --dataverse-addr when omitted.`,
+}
+
+var queryDataverseCmd = &cobra.Command{
+	Use:   "dataverse [dataverse]",
+	Short: "Get information about a dataverse (get_dataverse_info tool)",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arguments, err := dataverseArgument(args, 1)
+		if err != nil {
+			return err
+		}
+
+		return runQuery(cmd, "get_dataverse_info", arguments)
+	},
+}
+
+var queryGovernanceCmd = &cobra.Command{
+	Use:   "governance [dataverse] <resource>",
+	Short: "Get the governance code attached to a resource (get_resource_governance_code tool)",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		arguments, err := dataverseArgument(args, 2)
+		if err != nil {
+			return err
+		}
+		arguments["resource"] = args[len(args)-1]
+
+		return runQuery(cmd, "get_resource_governance_code", arguments)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+	queryCmd.AddCommand(queryDataverseCmd, queryGovernanceCmd)
+
+	queryCmd.PersistentFlags().Int(FlagHeight, 0,
+		"Block height to query the chain state at (defaults to the latest height)")
+
+	queryCmd.PersistentFlags().StringP(flagOutput, "o", "text", "Output format (text|json|yaml)")
+}
+
+// dataverseArgument returns the tool arguments holding the dataverse, given as first of the expected positional
+// arguments, or configured by --dataverse-addr when omitted.
+func dataverseArgument(args []string, expected int) (map[string]any, error) {
+	if len(args) == expected {
+		return map[string]any{"dataverse": args[0]}, nil
+	}
+
+	dataverse := viper.GetString(FlagDataverseAddr)
+	if dataverse == "" {
+		return nil, fmt.Errorf("no dataverse given, and no --%s configured", FlagDataverseAddr)
+	}
+
+	return map[string]any{"dataverse": dataverse}, nil
+}
+
+// runQuery calls the given tool with the given arguments through the gRPC client connection from the context, or a
+// new one, and prints its result in the requested format.
+func runQuery(cmd *cobra.Command, tool string, arguments map[string]any) error {
+	cmd.SilenceUsage = true
+
+	if h, _ := cmd.Flags().GetInt(FlagHeight); h != 0 {
+		arguments[FlagHeight] = h
+	}
+
+	ctx := cmd.Context()
+	client, ok := ctx.Value(grpcClientConn).(grpc.ClientConnInterface)
+	if !ok {
+		var err error
+		if client, err = buildDataverseClient(ctx); err != nil {
+			return err
+		}
+	}
+
+	result, err := mcp.CallTool(ctx, client, tool, arguments)
+	if toolErr := (*mcp.ToolError)(nil); errors.As(err, &toolErr) {
+		return fmt.Errorf("%s: %w\n%s", toolErr.Code, toolErr, toolErr.Hint)
+	}
+	if err != nil {
+		return err
+	}
+
+	output, _ := cmd.Flags().GetString(flagOutput)
+	bz, err := formatResult(mcp.ResultText(result), output)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+	return err
+}
+
+// formatResult formats the text of a tool result, JSON or plain text, in the given output format.
+func formatResult(text, output string) ([]byte, error) {
+	isJSON := json.Valid([]byte(text))
+
+	switch strings.ToLower(output) {
+	case "json":
+		if isJSON {
+			return []byte(text), nil
+		}
+		return json.Marshal(text)
+	case "yaml":
+		var value any = text
+		if isJSON {
+			if err := yaml.Unmarshal([]byte(text), &value); err != nil {
+				return nil, err
+			}
+		}
+		return yaml.Marshal(value)
+	default:
+		return []byte(text), nil
+	}
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"bytes"
+	goctx "context"
+	"fmt"
+	"testing"
+
+	"github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+)
+
+const (
+	testDataverse   = "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"
+	testTriplestore = "axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"
+)
+
+func TestQueryCommand(t *testing.T) {
+	Convey("Testing query command", t, func() {
+		tests := []struct {
+			name     string
+			args     []string
+			fixture  func(cc *mocks.MockClientConnInterface)
+			expected string
+			err      string
+		}{
+			{
+				name: "dataverse as text",
+				args: []string{"query", "dataverse", testDataverse},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectSmartContractState(cc, testDataverse, `{"dataverse":{}}`, `{"name":"my-dataverse"}`)
+				},
+				expected: `{"name":"my-dataverse","triplestore_address":""}` + "\n",
+			},
+			{
+				name: "dataverse as yaml",
+				args: []string{"query", "dataverse", testDataverse, "-o", "yaml"},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectSmartContractState(cc, testDataverse, `{"dataverse":{}}`, `{"name":"my-dataverse"}`)
+				},
+				expected: "name: my-dataverse\ntriplestore_address: \"\"\n\n",
+			},
+			{
+				name: "dataverse as json, on the configured dataverse",
+				args: []string{"query", "dataverse", "--dataverse-addr", testDataverse, "-o", "json"},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectSmartContractState(cc, testDataverse, `{"dataverse":{}}`,
+						fmt.Sprintf(`{"name":"my-dataverse","triplestore_address":%q}`, testTriplestore))
+				},
+				expected: fmt.Sprintf(`{"name":"my-dataverse","triplestore_address":%q}`, testTriplestore) + "\n",
+			},
+			{
+				name: "governance as json, with an invalid resource",
+				args: []string{"query", "governance", testDataverse, "did:key:foo", "-o", "json"},
+				err: "INVALID_DID: invalid DID \"did:key:foo\": key is not base58btc multibase encoded\n" +
+					"Provide a valid DID, such as did:key:z... or did:web:example.com.",
+			},
+			{
+				name: "dataverse, without any dataverse",
+				args: []string{"query", "dataverse"},
+				err:  "no dataverse given, and no --dataverse-addr configured",
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Given the %s query command", tt.name), func() {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+
+				cc := mocks.NewMockClientConnInterface(ctrl)
+				if tt.fixture != nil {
+					tt.fixture(cc)
+				}
+
+				Convey("When executing it", func() {
+					var stdout bytes.Buffer
+					rootCmd.SetArgs(tt.args)
+					rootCmd.SetOut(&stdout)
+					Reset(func() {
+						rootCmd.SetArgs(nil)
+						rootCmd.SetOut(nil)
+						// cobra only passes its context to the subcommands without one.
+						for _, c := range append(queryCmd.Commands(), queryCmd) {
+							c.SetContext(nil) //nolint:staticcheck
+						}
+						for _, flag := range []string{FlagDataverseAddr, flagOutput} {
+							if f := queryDataverseCmd.Flags().Lookup(flag); f != nil {
+								_ = f.Value.Set(f.DefValue)
+								f.Changed = false
+							}
+						}
+					})
+					err := rootCmd.ExecuteContext(WithGrpcClientConn(goctx.Background(), cc))
+
+					Convey("Then the result should be printed", func() {
+						if tt.err != "" {
+							So(err, ShouldBeError, tt.err)
+						} else {
+							So(err, ShouldBeNil)
+							So(stdout.String(), ShouldEqual, tt.expected)
+						}
+					})
+				})
+			})
+		}
+	})
+}
+
+func expectSmartContractState(cc *mocks.MockClientConnInterface, address, queryData, respData string) {
+	cc.EXPECT().
+		Invoke(gomock.Any(), "/cosmwasm.wasm.v1.Query/SmartContractState",
+			&types.QuerySmartContractStateRequest{Address: address, QueryData: []byte(queryData)},
+			&types.QuerySmartContractStateResponse{},
+			gomock.Any()).
+		DoAndReturn(func(_ goctx.Context, _ string, _, reply any, _ ...grpc.CallOption) error {
+			reply.(*types.QuerySmartContractStateResponse).Data = []byte(respData)
+			return nil
+		}).Times(1)
+}
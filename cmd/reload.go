@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/cache"
+	"github.com/axone-protocol/axone-mcp/internal/grpcpool"
+	"github.com/axone-protocol/axone-mcp/internal/mcp"
+	"github.com/axone-protocol/axone-mcp/internal/metrics"
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc"
+)
+
+// reloadGracePeriod is the time left to the calls in flight on a replaced connection before it is closed.
+const reloadGracePeriod = 30 * time.Second
+
+// watchConfig makes viper watch the config file, which it cannot stop doing, so only once per process.
+var watchConfig sync.Once
+
+// reloader reloads the settings of a server, along with its connection to the node if it dialed it.
+type reloader struct {
+	server *mcp.Server
+	// conn is the connection to the node, nil if given by the caller and not to be replaced.
+	conn *grpcpool.Swappable
+	// cache is the cache of the query responses, if any, purged when the connection is replaced.
+	cache *cache.ClientConn
+	// metrics are the metrics of the server, if any, tracking the calls to the configured dataverse.
+	metrics *metrics.Metrics
+	// dialed are the settings the connection to the node was last dialed with.
+	dialed dialSettings
+
+	mu sync.Mutex
+	// retired are the replaced connections left to the calls in flight, by the timer closing them.
+	retired map[*time.Timer]io.Closer
+}
+
+// newReloader creates the reloader of the given server, its connection to the node dialed with the current settings.
+func newReloader(s *mcp.Server, conn *grpcpool.Swappable, c *cache.ClientConn, m *metrics.Metrics) *reloader {
+	return &reloader{
+		server:  s,
+		conn:    conn,
+		cache:   c,
+		metrics: m,
+		dialed:  buildDialSettings(),
+		retired: make(map[*time.Timer]io.Closer),
+	}
+}
+
+// watch reloads the server whenever the config file changes or the process receives SIGHUP, until the context is done,
+// the replaced connections being then closed. A reload failing leaves the server untouched.
+func (r *reloader) watch(ctx context.Context) {
+	defer r.closeRetired()
+
+	changed := make(chan struct{}, 1)
+	if viper.ConfigFileUsed() != "" {
+		viper.OnConfigChange(func(fsnotify.Event) {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+		watchConfig.Do(viper.WatchConfig)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+		case <-changed:
+		}
+
+		if err := r.reload(ctx); err != nil {
+			log.Logger.Error().Err(err).Msg("failed to reload the configuration, keeping the current one")
+			continue
+		}
+		log.Logger.Info().Str("file", viper.ConfigFileUsed()).Msg("configuration reloaded")
+	}
+}
+
+// reload reads the configuration again, then replaces the connection to the node if its settings changed, and the
+// settings of the server.
+func (r *reloader) reload(ctx context.Context) error {
+	if err := LoadConfigRunE(nil, nil); err != nil {
+		return err
+	}
+	settings, err := buildSettings()
+	if err != nil {
+		return err
+	}
+
+	if dialed := buildDialSettings(); r.conn != nil && !dialed.equal(r.dialed) {
+		client, err := dialDataverseClient(ctx, dialed)
+		if err != nil {
+			return err
+		}
+		r.dialed = dialed
+		r.retire(r.conn.Swap(client))
+		if r.cache != nil {
+			r.cache.Purge()
+		}
+	}
+	if r.metrics != nil && settings.Dataverse != "" {
+		r.metrics.TrackDataverse(settings.Dataverse)
+	}
+	r.server.Reload(settings)
+
+	return nil
+}
+
+// retire closes the given connection once the calls in flight had the grace period to complete.
+func (r *reloader) retire(conn grpc.ClientConnInterface) {
+	closer, ok := conn.(io.Closer)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var timer *time.Timer
+	timer = time.AfterFunc(reloadGracePeriod, func() {
+		r.mu.Lock()
+		delete(r.retired, timer)
+		r.mu.Unlock()
+		_ = closer.Close()
+	})
+	r.retired[timer] = closer
+}
+
+// closeRetired closes the replaced connections still left to the calls in flight.
+func (r *reloader) closeRetired() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for timer, closer := range r.retired {
+		if timer.Stop() {
+			_ = closer.Close()
+		}
+		delete(r.retired, timer)
+	}
+}
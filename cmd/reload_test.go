@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	goctx "context"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/grpcpool"
+	"github.com/axone-protocol/axone-mcp/internal/mcp"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+)
+
+type closingConn struct {
+	grpc.ClientConnInterface
+	closed atomic.Bool
+}
+
+func (c *closingConn) Close() error {
+	c.closed.Store(true)
+	return nil
+}
+
+func TestReload(t *testing.T) {
+	Convey("Given the reloader of a server connected to the node", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		dialed := &closingConn{ClientConnInterface: mocks.NewMockClientConnInterface(ctrl)}
+		s, err := mcp.NewServer(dialed, mcp.ReadWrite)
+		So(err, ShouldBeNil)
+		r := newReloader(s, grpcpool.NewSwappable(dialed), nil, nil)
+		Reset(r.closeRetired)
+
+		Convey("When reloaded with the same connection settings", func() {
+			So(r.reload(goctx.Background()), ShouldBeNil)
+
+			Convey("Then the connection should be kept", func() {
+				So(r.conn.Swap(dialed), ShouldEqual, dialed)
+				So(r.retired, ShouldBeEmpty)
+			})
+		})
+
+		Convey("When reloaded after a connection setting changed", func() {
+			retries := rootCmd.PersistentFlags().Lookup(FlagGrpcRetries)
+			So(retries.Value.Set("5"), ShouldBeNil)
+			retries.Changed = true
+			Reset(func() { resetFlag(retries) })
+
+			So(r.reload(goctx.Background()), ShouldBeNil)
+			replacing := r.conn.Swap(dialed)
+			Reset(func() { _ = replacing.(io.Closer).Close() })
+
+			Convey("Then the connection should be replaced, the replaced one being left to the calls in flight", func() {
+				So(replacing, ShouldHaveSameTypeAs, &grpcpool.Pool{})
+				So(r.retired, ShouldHaveLength, 1)
+				So(dialed.closed.Load(), ShouldBeFalse)
+			})
+
+			Convey("Then the replaced connection should be closed once the reloader stops", func() {
+				r.closeRetired()
+				So(r.retired, ShouldBeEmpty)
+				So(dialed.closed.Load(), ShouldBeTrue)
+			})
+		})
+	})
+}
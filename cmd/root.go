@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/axone-protocol/axone-mcp/internal/version"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"resenje.org/casbab"
@@ -13,10 +14,22 @@ import (
 
 // rootCmd represents the base command when called without any subcommands.
 var rootCmd = &cobra.Command{
-	Use:               "axone-mcp",
-	Short:             "Axone’s MCP server",
-	Long:              "Gateway to the dataverse for AI-powered tools.",
-	PersistentPreRunE: InstallLogRunE,
+	Use:   "axone-mcp",
+	Short: "Axone’s MCP server",
+	Long:  "Gateway to the dataverse for AI-powered tools.",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if err := LoadConfigRunE(cmd, args); err != nil {
+			return err
+		}
+		if err := InstallLogRunE(cmd, args); err != nil {
+			return err
+		}
+		if file := viper.ConfigFileUsed(); file != "" {
+			log.Logger.Info().Str("file", file).Str("profile", viper.GetString(FlagProfile)).Msg("config loaded")
+		}
+
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -3,12 +3,18 @@ package cmd
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/axone-protocol/axone-mcp/internal/certwatch"
+	"github.com/axone-protocol/axone-mcp/internal/mcp"
+	"github.com/axone-protocol/axone-mcp/internal/metrics"
+	"github.com/axone-protocol/axone-mcp/internal/tracing"
 	"github.com/justinas/alice"
 	"github.com/spf13/viper"
 
@@ -20,8 +26,11 @@ import (
 )
 
 const (
-	FlagBaseURL    = "base-url"
-	FlagListenAddr = "listen-addr"
+	FlagBaseURL     = "base-url"
+	FlagListenAddr  = "listen-addr"
+	FlagTLSCert     = "tls-cert"
+	FlagTLSKey      = "tls-key"
+	FlagTLSClientCA = "tls-client-ca"
 )
 
 const (
@@ -33,50 +42,149 @@ var (
 	listenAddr string
 )
 
+const sseListener contextKey = "sseListener"
+
+// WithListener returns a new context with the provided listener, which serve sse then accepts the connections on
+// instead of listening on its listen address.
+func WithListener(ctx context.Context, l net.Listener) context.Context {
+	return context.WithValue(ctx, sseListener, l)
+}
+
 var serveSseCmd = &cobra.Command{
 	Use:   "sse",
 	Short: "Serve the MCP over SSE (server-sent events)",
 	Long: `Start the MCP server using Server-Sent Events (SSE) to enable streaming over HTTP.
 Typically used for browser-based or reactive clients.`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		s, err := buildMCPServer(cmd.Context())
+		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		m := metrics.New()
+		metricsAddr := viper.GetString(FlagMetricsAddr)
+		if metricsAddr != "" {
+			serveMetrics(ctx, metricsAddr, m)
+		}
+
+		s, r, err := buildMCPServer(ctx, m)
 		if err != nil {
 			return err
 		}
+		go r.watch(ctx)
+		defer func() {
+			if err := s.Close(); err != nil {
+				log.Logger.Warn().Err(err).Msg("failed to release the server resources")
+			}
+		}()
 
-		sseServer := server.NewSSEServer(s)
-		chain := loggerChain().Then(sseServer)
 		httpSrv := &http.Server{
 			Addr:              listenAddr,
 			ReadHeaderTimeout: ReadHeaderTimeout,
-			Handler:           chain,
+		}
+		// given the HTTP server, the SSE server ends the streams of its sessions before shutting it down.
+		sseServer := server.NewSSEServer(s.MCPServer, server.WithHTTPServer(httpSrv))
+		var handler http.Handler = sseServer
+		if metricsAddr == "" {
+			// served on the SSE listener, the metrics are behind its TLS and client certificates.
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", m.Handler())
+			mux.Handle("/", sseServer)
+			handler = mux
+		}
+		httpSrv.Handler = loggerChain().Append(tracing.HTTPHandler, principalHandler).Then(handler)
+
+		certWatcher, err := buildCertWatcher()
+		if err != nil {
+			return err
+		}
+		if certWatcher != nil {
+			httpSrv.TLSConfig = certWatcher.TLSConfig()
+			go func() {
+				if err := certWatcher.Watch(ctx); err != nil {
+					log.Error().Err(err).Msg("failed to watch TLS certificates")
+				}
+			}()
 		}
 
+		listener, _ := ctx.Value(sseListener).(net.Listener)
+		addr := listenAddr
+		if listener != nil {
+			addr = listener.Addr().String()
+		}
+		serveErr := make(chan error, 1)
 		go func() {
 			log.Logger.Info().
 				Str("transport", "sse").
 				Str("base_url", baseURL).
-				Str("addr", listenAddr).
+				Str("addr", addr).
+				Bool("tls", certWatcher != nil).
 				Str("message_path", sseServer.CompleteMessagePath()).
 				Str("sse_path", sseServer.CompleteSsePath()).
 				Msg("ready")
-			if err := httpSrv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-				log.Fatal().Err(err).Msg("failed to start server")
+			if err := listenAndServe(httpSrv, listener); !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- err
 			}
 		}()
 
-		ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
-		defer stop()
-
-		<-ctx.Done()
+		select {
+		case err := <-serveErr:
+			return fmt.Errorf("failed to start server: %w", err)
+		case <-ctx.Done():
+		}
 		log.Info().Msg("shutdown signal received")
 
-		shutdownCtx, cancel := context.WithTimeout(cmd.Context(), 10*time.Second)
+		// the signal received, the shutdown is given some time on its own.
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
 		defer cancel()
 		return sseServer.Shutdown(shutdownCtx)
 	},
 }
 
+// buildCertWatcher loads the TLS certificates given by flags, if any, and returns a watcher serving them.
+func buildCertWatcher() (*certwatch.Watcher, error) {
+	certFile := viper.GetString(FlagTLSCert)
+	keyFile := viper.GetString(FlagTLSKey)
+	clientCAFile := viper.GetString(FlagTLSClientCA)
+
+	switch {
+	case certFile == "" && keyFile == "" && clientCAFile == "":
+		return nil, nil //nolint:nilnil
+	case certFile == "" || keyFile == "":
+		return nil, fmt.Errorf("both --%s and --%s are required to serve over TLS", FlagTLSCert, FlagTLSKey)
+	}
+
+	return certwatch.New(certFile, keyFile, clientCAFile)
+}
+
+// listenAndServe serves on the given listener, or else on the address of the server.
+func listenAndServe(srv *http.Server, l net.Listener) error {
+	switch {
+	case l != nil && srv.TLSConfig != nil:
+		return srv.ServeTLS(l, "", "")
+	case l != nil:
+		return srv.Serve(l)
+	case srv.TLSConfig != nil:
+		return srv.ListenAndServeTLS("", "")
+	default:
+		return srv.ListenAndServe()
+	}
+}
+
+// principalHandler exposes the subject of the client certificate, if any, as the principal of the request.
+func principalHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal := certwatch.PeerPrincipal(r.TLS)
+		if principal == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		zerolog.Ctx(r.Context()).UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str("principal", principal)
+		})
+		next.ServeHTTP(w, r.WithContext(mcp.WithPrincipal(r.Context(), principal)))
+	})
+}
+
 func loggerChain() alice.Chain {
 	return alice.New(hlog.NewHandler(log.Logger),
 		hlog.AccessHandler(func(r *http.Request, status, size int, duration time.Duration) {
@@ -103,5 +211,17 @@ func init() {
 		"The server's listen address")
 	_ = viper.BindPFlag(FlagListenAddr, serveSseCmd.PersistentFlags().Lookup(FlagListenAddr))
 
+	serveSseCmd.PersistentFlags().String(FlagTLSCert, "",
+		"Path to the PEM encoded certificate to serve HTTPS with (reloaded on change)")
+	_ = viper.BindPFlag(FlagTLSCert, serveSseCmd.PersistentFlags().Lookup(FlagTLSCert))
+
+	serveSseCmd.PersistentFlags().String(FlagTLSKey, "",
+		"Path to the PEM encoded private key of the TLS certificate (reloaded on change)")
+	_ = viper.BindPFlag(FlagTLSKey, serveSseCmd.PersistentFlags().Lookup(FlagTLSKey))
+
+	serveSseCmd.PersistentFlags().String(FlagTLSClientCA, "",
+		"Path to the PEM encoded CA certificates clients must present a certificate from (enables mTLS)")
+	_ = viper.BindPFlag(FlagTLSClientCA, serveSseCmd.PersistentFlags().Lookup(FlagTLSClientCA))
+
 	serveCmd.AddCommand(serveSseCmd)
 }
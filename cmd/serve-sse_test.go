@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	goctx "context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/fakenode"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/mark3labs/mcp-go/client"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestServeSseCommandListenFailure(t *testing.T) {
+	Convey("Given an address already listened on", t, func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		Reset(func() { _ = listener.Close() })
+
+		Convey("When serving sse on it", withCommandArguments(
+			[]string{"serve", "sse", "--listen-addr", listener.Addr().String()}, func(_ C) {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+				Reset(func() {
+					serveSseCmd.SetContext(nil) //nolint:staticcheck // resets the context given to ExecuteContext
+					serveSseCmd.Flags().VisitAll(resetFlag)
+				})
+
+				ctx := WithGrpcClientConn(goctx.Background(), mocks.NewMockClientConnInterface(ctrl))
+				err := serveSseCmd.ExecuteContext(ctx)
+
+				Convey("Then the command should return the error, rather than exiting", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldStartWith, "failed to start server: listen tcp "+listener.Addr().String())
+				})
+			}))
+	})
+}
+
+func TestServeSseCommandMetrics(t *testing.T) {
+	Convey("Given a fake axone node running a dataverse, and a server connected to it over sse", t, func() {
+		node, err := fakenode.Start(
+			fakenode.WithContract(testDataverse,
+				fakenode.Dataverse{Name: "my-dataverse", TriplestoreAddress: testTriplestore}),
+		)
+		So(err, ShouldBeNil)
+		Reset(node.Stop)
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		So(err, ShouldBeNil)
+		runE2E(WithListener(goctx.Background(), listener), serveSseCmd, node.Addr(), "serve", "sse")
+
+		c, err := client.NewSSEMCPClient("http://" + listener.Addr().String() + "/sse")
+		So(err, ShouldBeNil)
+		startE2E(c)
+
+		Convey("When a dataverse is resolved, then the metrics are scraped on the listen address", func() {
+			_, err := callToolE2E(c, "get_dataverse_info", map[string]any{"dataverse": testDataverse})
+			So(err, ShouldBeNil)
+
+			res, err := http.Get("http://" + listener.Addr().String() + "/metrics") //nolint:noctx
+			So(err, ShouldBeNil)
+			defer res.Body.Close()
+			body, err := io.ReadAll(res.Body)
+			So(err, ShouldBeNil)
+
+			Convey("Then the calls to the dataverse should be exposed by its address", func() {
+				So(res.StatusCode, ShouldEqual, http.StatusOK)
+				So(string(body), ShouldContainSubstring, fmt.Sprintf(`contract="%s"`, testDataverse))
+			})
+		})
+	})
+}
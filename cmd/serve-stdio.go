@@ -10,10 +10,12 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/axone-protocol/axone-mcp/internal/metrics"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/rs/zerolog"
 	zlog "github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var (
@@ -28,16 +30,28 @@ var serveStdioCmd = &cobra.Command{
 	Long: `Start the MCP server using standard input and output streams.
 This mode is typically used for local integrations and command-line tools that communicate via stdio.`,
 	RunE: func(cmd *cobra.Command, _ []string) error {
-		s, err := buildMCPServer(cmd.Context())
+		var m *metrics.Metrics
+		if addr := viper.GetString(FlagMetricsAddr); addr != "" {
+			m = metrics.New()
+			serveMetrics(cmd.Context(), addr, m)
+		}
+
+		s, r, err := buildMCPServer(cmd.Context(), m)
 		if err != nil {
 			return err
 		}
+		go r.watch(cmd.Context())
+		defer func() {
+			if err := s.Close(); err != nil {
+				zlog.Logger.Warn().Err(err).Msg("failed to release the server resources")
+			}
+		}()
 
 		zlog.Logger.Info().
 			Str("transport", "stdio").
 			Msg("ready")
 
-		err = serveStdio(cmd.Context(), s, MCPStdin, MCPStdout, MCPStderr, WithZerolog())
+		err = serveStdio(cmd.Context(), s.MCPServer, MCPStdin, MCPStdout, MCPStderr, WithZerolog())
 		if err != nil && !errors.Is(err, context.Canceled) {
 			return err
 		}
@@ -8,12 +8,16 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/axone-protocol/axone-mcp/internal/mocks"
 	"github.com/axone-protocol/axone-mcp/internal/version"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"go.uber.org/mock/gomock"
 
 	. "github.com/smartystreets/goconvey/convey"
@@ -102,6 +106,89 @@ func TestInvalidServeStdioCommand(t *testing.T) {
 			})))
 }
 
+func TestServeStdioReplayCommand(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	err := os.WriteFile(fixture, []byte(fmt.Sprintf(`{"interactions": [
+		{"address": %q, "query": {"dataverse": {}}, "response": {"name": "my-dataverse"}}
+	]}`, testDataverse)), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Convey("Testing Serve Stdio command replaying a fixture file", t,
+		withCommandArguments([]string{"serve", "stdio", "--replay", fixture},
+			withPipedIOStreams(func(c C, stdinW io.Writer, stdoutR io.Reader, _ io.Reader) {
+				executeSubcommand(goctx.Background(), serveStdioCmd)
+
+				Convey("When calling a tool querying the recorded contract", func(c C) {
+					go func() {
+						_, err := fmt.Fprintf(stdinW, `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": `+
+							`{"name": "get_dataverse_info", "arguments": {"dataverse": %q}}}`+"\n", testDataverse)
+						c.So(err, ShouldBeNil)
+					}()
+
+					Convey("Then the recorded response should be answered, without any node", func(c C) {
+						var got string
+						done := make(chan struct{})
+						go func() {
+							scanner := bufio.NewScanner(stdoutR)
+							scanner.Scan()
+							c.So(scanner.Err(), ShouldBeNil)
+							got = scanner.Text()
+							close(done)
+						}()
+
+						select {
+						case <-done:
+						case <-time.After(testTimeout):
+							t.Fatalf("timeout")
+						}
+
+						So(got, ShouldContainSubstring, `{\"name\":\"my-dataverse\",\"triplestore_address\":\"\"}`)
+						So(got, ShouldNotContainSubstring, `"isError":true`)
+					})
+				})
+			})))
+}
+
+// executeSubcommand executes the given subcommand with the given context until the end of the test, then resets the
+// flags changed. It is executed on the subcommand, not to inherit the connection given to the root command by a former
+// test.
+func executeSubcommand(ctx goctx.Context, cmd *cobra.Command) {
+	ctx, cancel := goctx.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = cmd.ExecuteContext(ctx)
+	}()
+
+	Reset(func() {
+		cancel()
+		select {
+		case <-done:
+			// cleared once done, for the command to inherit the context of the root command again.
+			cmd.SetContext(nil) //nolint:staticcheck // resets the context given to ExecuteContext
+		case <-time.After(testTimeout):
+		}
+		for _, flags := range []*pflag.FlagSet{rootCmd.PersistentFlags(), serveCmd.PersistentFlags(), serveSseCmd.Flags()} {
+			flags.VisitAll(resetFlag)
+		}
+	})
+}
+
+// resetFlag resets the given flag to its default value, if changed.
+func resetFlag(f *pflag.Flag) {
+	if !f.Changed {
+		return
+	}
+	if s, ok := f.Value.(pflag.SliceValue); ok {
+		_ = s.Replace(strings.Split(strings.Trim(f.DefValue, "[]"), ","))
+	} else {
+		_ = f.Value.Set(f.DefValue)
+	}
+	f.Changed = false
+}
+
 func withCommandArguments(args []string, f func(c C)) func(c C) {
 	return func(c C) {
 		origArgs := os.Args
@@ -3,11 +3,31 @@ package cmd
 import (
 	"context"
 	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/axone-protocol/axone-mcp/internal/audit"
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
+	"github.com/axone-protocol/axone-mcp/internal/cache"
+	"github.com/axone-protocol/axone-mcp/internal/credential"
+	"github.com/axone-protocol/axone-mcp/internal/embedding"
+	"github.com/axone-protocol/axone-mcp/internal/grpcpool"
 	"github.com/axone-protocol/axone-mcp/internal/mcp"
-	"github.com/mark3labs/mcp-go/server"
+	"github.com/axone-protocol/axone-mcp/internal/metrics"
+	"github.com/axone-protocol/axone-mcp/internal/policy"
+	"github.com/axone-protocol/axone-mcp/internal/ratelimit"
+	"github.com/axone-protocol/axone-mcp/internal/recording"
+	"github.com/axone-protocol/axone-mcp/internal/search"
+	"github.com/axone-protocol/axone-mcp/internal/semantic"
+	"github.com/axone-protocol/axone-mcp/internal/tracing"
+	"github.com/axone-protocol/axone-mcp/internal/version"
 	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
@@ -16,11 +36,43 @@ import (
 )
 
 const (
-	FlagNodeGrpc          = "node-grpc"
-	FlagGrpcNoTLS         = "grpc-no-tls"
-	FlagGrpcTLSSkipVerify = "grpc-tls-skip-verify"
-	FlagGrpcTimeout       = "grpc-timeout"
-	FlagReadOnly          = "read-only"
+	FlagNodeGrpc            = "node-grpc"
+	FlagDataverseAddr       = "dataverse-addr"
+	FlagGrpcNoTLS           = "grpc-no-tls"
+	FlagGrpcTLSSkipVerify   = "grpc-tls-skip-verify"
+	FlagGrpcTimeout         = "grpc-timeout"
+	FlagGrpcHealthInterval  = "grpc-health-interval"
+	FlagGrpcRetries         = "grpc-retries"
+	FlagGrpcRetryBackoff    = "grpc-retry-backoff"
+	FlagReadOnly            = "read-only"
+	FlagPolicy              = "policy"
+	FlagRateLimitSession    = "rate-limit-session"
+	FlagRateLimitPrincipal  = "rate-limit-principal"
+	FlagRateLimitTool       = "rate-limit-tool"
+	FlagQuotaDaily          = "quota-daily"
+	FlagQuotaStore          = "quota-store"
+	FlagCacheEnabled        = "cache-enabled"
+	FlagCacheTTL            = "cache-ttl"
+	FlagCacheSize           = "cache-size"
+	FlagCacheHeightPoll     = "cache-height-poll"
+	FlagSigningKey          = "signing-key"
+	FlagSearchStore         = "search-store"
+	FlagSearchRefresh       = "search-refresh"
+	FlagSearchMaxDataverses = "search-max-dataverses"
+	FlagEmbedder            = "embedder"
+	FlagEmbedderURL         = "embedder-url"
+	FlagEmbedderModel       = "embedder-model"
+	FlagEmbedderAPIKey      = "embedder-api-key"
+	FlagMetricsAddr         = "metrics-addr"
+	FlagTracingExporter     = "tracing-exporter"
+	FlagTracingEndpoint     = "tracing-endpoint"
+	FlagTracingInsecure     = "tracing-insecure"
+	FlagAuditLog            = "audit-log"
+	FlagAuditMaxSize        = "audit-max-size"
+	FlagAuditMaxBackups     = "audit-max-backups"
+	FlagAuditRedact         = "audit-redact"
+	FlagRecord              = "record"
+	FlagReplay              = "replay"
 )
 
 // serveCmd represents the base serve command.
@@ -29,38 +81,193 @@ var serveCmd = &cobra.Command{
 	Short: "Serve the MCP using a specific transport",
 	Long: `Start the Axone MCP server using the chosen transport:
 SSE for web clients, stdio for command-line and local integrations.`,
-	PersistentPreRun: func(_ *cobra.Command, _ []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
 		log.Logger.Info().Msg("starting server...")
+
+		var err error
+		shutdownTracing, err = tracing.Setup(cmd.Context(), tracing.Config{
+			Exporter:       viper.GetString(FlagTracingExporter),
+			Endpoint:       viper.GetString(FlagTracingEndpoint),
+			Insecure:       viper.GetBool(FlagTracingInsecure),
+			ServiceName:    version.Name,
+			ServiceVersion: version.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("--%s: %w", FlagTracingExporter, err)
+		}
+
+		return nil
 	},
-	PersistentPostRun: func(_ *cobra.Command, _ []string) {
+	PersistentPostRun: func(cmd *cobra.Command, _ []string) {
+		if err := shutdownTracing(context.WithoutCancel(cmd.Context())); err != nil {
+			log.Logger.Warn().Err(err).Msg("failed to flush traces")
+		}
 		log.Logger.Info().Msg("server stopped")
 	},
 }
 
+// shutdownTracing flushes the pending spans and releases the exporter, once the server is stopped.
+var shutdownTracing = func(context.Context) error { return nil }
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
 
-	serveCmd.PersistentFlags().String(FlagNodeGrpc, "127.0.0.1:9090",
-		"Address <host>:<port> of the gRPC endpoint exposed by the axone node")
-	_ = viper.BindPFlag(FlagNodeGrpc, serveCmd.PersistentFlags().Lookup(FlagNodeGrpc))
+	// The connection to the axone node is shared by the commands querying it: serve and query.
+	rootCmd.PersistentFlags().StringSlice(FlagNodeGrpc, []string{"127.0.0.1:9090"},
+		"Addresses <host>:<port> of the gRPC endpoints exposed by axone nodes, comma separated (failover between them)")
+	_ = viper.BindPFlag(FlagNodeGrpc, rootCmd.PersistentFlags().Lookup(FlagNodeGrpc))
+
+	rootCmd.PersistentFlags().String(FlagDataverseAddr, "",
+		"Address of the dataverse contract targeted by the tools when not given, indexed for search as soon as the server "+
+			"starts")
+	_ = viper.BindPFlag(FlagDataverseAddr, rootCmd.PersistentFlags().Lookup(FlagDataverseAddr))
 
-	serveCmd.PersistentFlags().Bool(FlagGrpcNoTLS, false,
+	rootCmd.PersistentFlags().Bool(FlagGrpcNoTLS, false,
 		"Disable TLS when connecting to the gRPC endpoint")
-	_ = viper.BindPFlag(FlagGrpcNoTLS, serveCmd.PersistentFlags().Lookup(FlagGrpcNoTLS))
+	_ = viper.BindPFlag(FlagGrpcNoTLS, rootCmd.PersistentFlags().Lookup(FlagGrpcNoTLS))
 
-	serveCmd.PersistentFlags().Bool(FlagGrpcTLSSkipVerify, false,
+	rootCmd.PersistentFlags().Bool(FlagGrpcTLSSkipVerify, false,
 		"Use TLS but skip certificate verification (insecure)")
-	_ = viper.BindPFlag(FlagGrpcTLSSkipVerify, serveCmd.PersistentFlags().Lookup(FlagGrpcTLSSkipVerify))
+	_ = viper.BindPFlag(FlagGrpcTLSSkipVerify, rootCmd.PersistentFlags().Lookup(FlagGrpcTLSSkipVerify))
 
-	serveCmd.PersistentFlags().Duration(FlagGrpcTimeout, 5*time.Second,
+	rootCmd.PersistentFlags().Duration(FlagGrpcTimeout, 5*time.Second,
 		"Timeout for establishing the gRPC connection to the axone node (e.g. 5s, 2m)")
-	_ = viper.BindPFlag(FlagGrpcTimeout, serveCmd.PersistentFlags().Lookup(FlagGrpcTimeout))
+	_ = viper.BindPFlag(FlagGrpcTimeout, rootCmd.PersistentFlags().Lookup(FlagGrpcTimeout))
+
+	rootCmd.PersistentFlags().Duration(FlagGrpcHealthInterval, 10*time.Second,
+		"Interval between two health checks of the gRPC endpoints, when several are given")
+	_ = viper.BindPFlag(FlagGrpcHealthInterval, rootCmd.PersistentFlags().Lookup(FlagGrpcHealthInterval))
+
+	rootCmd.PersistentFlags().Int(FlagGrpcRetries, 2,
+		"Number of retries, on the next best endpoint, of a gRPC call failing because the node is unavailable")
+	_ = viper.BindPFlag(FlagGrpcRetries, rootCmd.PersistentFlags().Lookup(FlagGrpcRetries))
+
+	rootCmd.PersistentFlags().Duration(FlagGrpcRetryBackoff, 100*time.Millisecond,
+		"Delay before the first retry of an unavailable gRPC call, doubled at each subsequent retry")
+	_ = viper.BindPFlag(FlagGrpcRetryBackoff, rootCmd.PersistentFlags().Lookup(FlagGrpcRetryBackoff))
 
 	serveCmd.PersistentFlags().Bool(FlagReadOnly, false,
 		"Restrict the server to read-only operations")
 	_ = viper.BindPFlag(FlagReadOnly, serveCmd.PersistentFlags().Lookup(FlagReadOnly))
 
-	serveCmd.MarkFlagsMutuallyExclusive(FlagGrpcNoTLS, FlagGrpcTLSSkipVerify)
+	serveCmd.PersistentFlags().String(FlagPolicy, "",
+		"Path to a YAML policy file restricting the tools and arguments allowed per principal or session")
+	_ = viper.BindPFlag(FlagPolicy, serveCmd.PersistentFlags().Lookup(FlagPolicy))
+
+	serveCmd.PersistentFlags().String(FlagRateLimitSession, "",
+		`Maximum rate of tool calls per session, as "<events>/<s|m|h>[:<burst>]" (e.g. "10/s", "100/m:20")`)
+	_ = viper.BindPFlag(FlagRateLimitSession, serveCmd.PersistentFlags().Lookup(FlagRateLimitSession))
+
+	serveCmd.PersistentFlags().String(FlagRateLimitPrincipal, "",
+		`Maximum rate of tool calls per principal, as "<events>/<s|m|h>[:<burst>]"`)
+	_ = viper.BindPFlag(FlagRateLimitPrincipal, serveCmd.PersistentFlags().Lookup(FlagRateLimitPrincipal))
+
+	serveCmd.PersistentFlags().String(FlagRateLimitTool, "",
+		`Maximum rate of calls per tool, all callers included, as "<events>/<s|m|h>[:<burst>]"`)
+	_ = viper.BindPFlag(FlagRateLimitTool, serveCmd.PersistentFlags().Lookup(FlagRateLimitTool))
+
+	serveCmd.PersistentFlags().Int(FlagQuotaDaily, 0,
+		"Maximum number of tool calls per principal (or session if anonymous) per day, 0 for unlimited")
+	_ = viper.BindPFlag(FlagQuotaDaily, serveCmd.PersistentFlags().Lookup(FlagQuotaDaily))
+
+	serveCmd.PersistentFlags().String(FlagQuotaStore, "",
+		"Path to a bbolt file persisting the daily quotas (kept in memory if empty)")
+	_ = viper.BindPFlag(FlagQuotaStore, serveCmd.PersistentFlags().Lookup(FlagQuotaStore))
+
+	serveCmd.PersistentFlags().Bool(FlagCacheEnabled, false,
+		"Cache the responses of the smart contract queries made to the axone node")
+	_ = viper.BindPFlag(FlagCacheEnabled, serveCmd.PersistentFlags().Lookup(FlagCacheEnabled))
+
+	serveCmd.PersistentFlags().Duration(FlagCacheTTL, time.Minute,
+		"Maximum time a cached query response is served (e.g. 30s, 5m)")
+	_ = viper.BindPFlag(FlagCacheTTL, serveCmd.PersistentFlags().Lookup(FlagCacheTTL))
+
+	serveCmd.PersistentFlags().Int(FlagCacheSize, 1024,
+		"Maximum number of cached query responses, the least recently used being evicted first")
+	_ = viper.BindPFlag(FlagCacheSize, serveCmd.PersistentFlags().Lookup(FlagCacheSize))
+
+	serveCmd.PersistentFlags().Duration(FlagCacheHeightPoll, 0,
+		"Interval at which the latest block height is polled to invalidate the cache when it advances (0 to disable)")
+	_ = viper.BindPFlag(FlagCacheHeightPoll, serveCmd.PersistentFlags().Lookup(FlagCacheHeightPoll))
+
+	serveCmd.PersistentFlags().String(FlagSigningKey, "",
+		"Path to a PEM encoded Ed25519 private key signing the credentials built by the server, as its did:key, for the "+
+			"callers granted so by the --policy")
+	_ = viper.BindPFlag(FlagSigningKey, serveCmd.PersistentFlags().Lookup(FlagSigningKey))
+
+	serveCmd.PersistentFlags().String(FlagSearchStore, "",
+		"Path to a bbolt file persisting the full-text index of the dataverses (kept in memory if empty)")
+	_ = viper.BindPFlag(FlagSearchStore, serveCmd.PersistentFlags().Lookup(FlagSearchStore))
+
+	serveCmd.PersistentFlags().Duration(FlagSearchRefresh, 10*time.Minute,
+		"Interval at which the full-text index of the searched dataverses is refreshed (0 to disable)")
+	_ = viper.BindPFlag(FlagSearchRefresh, serveCmd.PersistentFlags().Lookup(FlagSearchRefresh))
+
+	serveCmd.PersistentFlags().Int(FlagSearchMaxDataverses, search.DefaultMaxDataverses,
+		"Maximum number of dataverses held by the full-text and semantic indexes, the searches of other ones being refused")
+	_ = viper.BindPFlag(FlagSearchMaxDataverses, serveCmd.PersistentFlags().Lookup(FlagSearchMaxDataverses))
+
+	serveCmd.PersistentFlags().String(FlagEmbedder, "",
+		`Embedding backend enabling the semantic_search tool: "hash" (local, lexical only) or "openai" (any OpenAI `+
+			`compatible API); disabled if empty`)
+	_ = viper.BindPFlag(FlagEmbedder, serveCmd.PersistentFlags().Lookup(FlagEmbedder))
+
+	serveCmd.PersistentFlags().String(FlagEmbedderURL, embedding.DefaultOpenAIURL,
+		"Base URL of the OpenAI compatible API computing the embeddings")
+	_ = viper.BindPFlag(FlagEmbedderURL, serveCmd.PersistentFlags().Lookup(FlagEmbedderURL))
+
+	serveCmd.PersistentFlags().String(FlagEmbedderModel, embedding.DefaultOpenAIModel,
+		"Model computing the embeddings through the OpenAI compatible API")
+	_ = viper.BindPFlag(FlagEmbedderModel, serveCmd.PersistentFlags().Lookup(FlagEmbedderModel))
+
+	serveCmd.PersistentFlags().String(FlagEmbedderAPIKey, "",
+		"API key of the OpenAI compatible API, preferably given through the AXONE_MCP_EMBEDDER_API_KEY variable")
+	_ = viper.BindPFlag(FlagEmbedderAPIKey, serveCmd.PersistentFlags().Lookup(FlagEmbedderAPIKey))
+
+	serveCmd.PersistentFlags().String(FlagMetricsAddr, "",
+		"Address <host>:<port> to serve the Prometheus metrics on at /metrics, apart from the MCP endpoints (by "+
+			"default, on the listen address for SSE, and nowhere for stdio)")
+	_ = viper.BindPFlag(FlagMetricsAddr, serveCmd.PersistentFlags().Lookup(FlagMetricsAddr))
+
+	serveCmd.PersistentFlags().String(FlagTracingExporter, tracing.ExporterNone,
+		`Exporter of the OpenTelemetry traces: "otlp" (gRPC), "stdout" (written to stderr) or "none"`)
+	_ = viper.BindPFlag(FlagTracingExporter, serveCmd.PersistentFlags().Lookup(FlagTracingExporter))
+
+	serveCmd.PersistentFlags().String(FlagTracingEndpoint, "",
+		"Address <host>:<port> of the OTLP collector (defaults to OTEL_EXPORTER_OTLP_ENDPOINT, or localhost:4317)")
+	_ = viper.BindPFlag(FlagTracingEndpoint, serveCmd.PersistentFlags().Lookup(FlagTracingEndpoint))
+
+	serveCmd.PersistentFlags().Bool(FlagTracingInsecure, false,
+		"Disable TLS when connecting to the OTLP collector")
+	_ = viper.BindPFlag(FlagTracingInsecure, serveCmd.PersistentFlags().Lookup(FlagTracingInsecure))
+
+	serveCmd.PersistentFlags().String(FlagAuditLog, "",
+		"Path to a JSON Lines file recording every tool invocation (disabled if empty)")
+	_ = viper.BindPFlag(FlagAuditLog, serveCmd.PersistentFlags().Lookup(FlagAuditLog))
+
+	serveCmd.PersistentFlags().Int(FlagAuditMaxSize, 100,
+		"Size, in megabytes, beyond which the audit log is rotated")
+	_ = viper.BindPFlag(FlagAuditMaxSize, serveCmd.PersistentFlags().Lookup(FlagAuditMaxSize))
+
+	serveCmd.PersistentFlags().Int(FlagAuditMaxBackups, 10,
+		"Number of rotated audit log files kept, 0 to keep them all")
+	_ = viper.BindPFlag(FlagAuditMaxBackups, serveCmd.PersistentFlags().Lookup(FlagAuditMaxBackups))
+
+	serveCmd.PersistentFlags().StringSlice(FlagAuditRedact, nil,
+		`Arguments whose value is not recorded in the audit log, as "[<tool>:]<argument>" shell patterns, comma `+
+			`separated (e.g. "api_key,build_credential:claims")`)
+	_ = viper.BindPFlag(FlagAuditRedact, serveCmd.PersistentFlags().Lookup(FlagAuditRedact))
+
+	serveCmd.PersistentFlags().String(FlagRecord, "",
+		"Path to a JSON fixture file recording the smart contract queries made to the axone node, and their outcome")
+	_ = viper.BindPFlag(FlagRecord, serveCmd.PersistentFlags().Lookup(FlagRecord))
+
+	serveCmd.PersistentFlags().String(FlagReplay, "",
+		"Path to a JSON fixture file, written with --record, answering the smart contract queries instead of any node")
+	_ = viper.BindPFlag(FlagReplay, serveCmd.PersistentFlags().Lookup(FlagReplay))
+
+	rootCmd.MarkFlagsMutuallyExclusive(FlagGrpcNoTLS, FlagGrpcTLSSkipVerify)
+	serveCmd.MarkFlagsMutuallyExclusive(FlagRecord, FlagReplay)
 }
 
 type contextKey string
@@ -73,45 +280,389 @@ func WithGrpcClientConn(ctx context.Context, cc grpc.ClientConnInterface) contex
 }
 
 // buildMCPServer creates a new MCP server using the gRPC client connection from the context or builds a new one.
-func buildMCPServer(ctx context.Context) (*server.MCPServer, error) {
+// The server records its activity in the given metrics, if any. It is returned along with its reloader, left to the
+// serve commands to start.
+func buildMCPServer(ctx context.Context, m *metrics.Metrics) (_ *mcp.Server, _ *reloader, err error) {
+	settings, err := buildSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the resources opened are released along with the server, or as soon as a later step fails.
+	var closers []io.Closer
+	defer func() {
+		if err != nil {
+			for _, c := range closers {
+				_ = c.Close()
+			}
+		}
+	}()
+
+	client, conn, err := buildClient(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts := []mcp.Option{mcp.WithPolicy(settings.Policy), mcp.WithDefaultDataverse(settings.Dataverse)}
+	if conn != nil {
+		closers = append(closers, conn)
+		opts = append(opts, mcp.WithCloser(conn))
+	}
+	if recorder, ok := client.(*recording.Recorder); ok {
+		// the queries recorded are written once the server is closed.
+		opts = append(opts, mcp.WithCloser(recorder))
+	}
+	client, cached := wrapClient(ctx, client, m)
+
+	if m != nil {
+		opts = append(opts, mcp.WithMetrics(m))
+		if settings.Dataverse != "" {
+			m.TrackDataverse(settings.Dataverse)
+		}
+	}
+
+	if keyFile := viper.GetString(FlagSigningKey); keyFile != "" {
+		// the server vouching for the claims it signs, the callers allowed to have them signed must be granted so.
+		if settings.Policy == nil {
+			return nil, nil, fmt.Errorf("--%s requires a --%s granting the signing of credentials", FlagSigningKey, FlagPolicy)
+		}
+		signer, err := credential.LoadSigner(keyFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		log.Logger.Info().Str("did", signer.DID()).Msg("credentials will be signed")
+		opts = append(opts, mcp.WithCredentialSigner(signer))
+	}
+
+	limiter, err := buildRateLimiter()
+	if err != nil {
+		return nil, nil, err
+	}
+	if limiter != nil {
+		closers = append(closers, limiter)
+		opts = append(opts, mcp.WithRateLimiter(limiter))
+	}
+
+	auditLog, err := buildAuditLog()
+	if err != nil {
+		return nil, nil, err
+	}
+	if auditLog != nil {
+		closers = append(closers, auditLog)
+		opts = append(opts, mcp.WithAuditLog(auditLog))
+	}
+	index, searchOpts, err := buildSearchOptions(ctx, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	closers = append(closers, index)
+
+	s, err := mcp.NewServer(client, settings.Mode, slices.Concat(opts, searchOpts)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return s, newReloader(s, conn, cached, m), nil
+}
+
+// buildClient returns the gRPC client connection from the context, or the one replaying a fixture file if configured
+// so, or else a new one to the axone node, also returned as swappable on reload. The connection records the queries
+// made through it if configured so.
+func buildClient(ctx context.Context) (grpc.ClientConnInterface, *grpcpool.Swappable, error) {
+	var conn *grpcpool.Swappable
 	client, ok := ctx.Value(grpcClientConn).(grpc.ClientConnInterface)
-	if !ok {
-		var err error
-		client, err = buildDataverseClient()
+	switch path := viper.GetString(FlagReplay); {
+	case ok:
+	case path != "":
+		fixture, err := recording.Load(path)
 		if err != nil {
-			return nil, err
+			return nil, nil, fmt.Errorf("--%s: %w", FlagReplay, err)
 		}
+		log.Logger.Info().Str("file", path).Int("queries", len(fixture.Interactions)).Msg("replaying recorded queries")
+		client = recording.NewReplayer(fixture)
+	default:
+		dialed, err := buildDataverseClient(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		conn = grpcpool.NewSwappable(dialed)
+		client = conn
 	}
 
-	mode := mcp.ReadWrite
+	if path := viper.GetString(FlagRecord); path != "" {
+		recorder, err := recording.NewRecorder(client, path)
+		if err != nil {
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return nil, nil, fmt.Errorf("--%s: %w", FlagRecord, err)
+		}
+		log.Logger.Info().Str("file", path).Msg("recording queries")
+		client = recorder
+	}
+
+	return client, conn, nil
+}
+
+// buildSettings returns the settings of the server configured by flags, the ones which can be reloaded.
+func buildSettings() (mcp.Settings, error) {
+	settings := mcp.Settings{Mode: mcp.ReadWrite, Dataverse: viper.GetString(FlagDataverseAddr)}
 	if viper.GetBool(FlagReadOnly) {
-		mode = mcp.ReadOnly
+		settings.Mode = mcp.ReadOnly
+	}
+
+	if settings.Dataverse != "" {
+		if err := address.ValidateContract(settings.Dataverse); err != nil {
+			return settings, fmt.Errorf("--%s: %w", FlagDataverseAddr, err)
+		}
+	}
+
+	if policyFile := viper.GetString(FlagPolicy); policyFile != "" {
+		p, err := policy.Load(policyFile)
+		if err != nil {
+			return settings, err
+		}
+		settings.Policy = p
+	}
+
+	return settings, nil
+}
+
+// buildAuditLog opens the audit log, if configured.
+func buildAuditLog() (*audit.Log, error) {
+	path := viper.GetString(FlagAuditLog)
+	if path == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	l, err := audit.Open(path, audit.Config{
+		MaxSize:    int64(viper.GetInt(FlagAuditMaxSize)) << 20,
+		MaxBackups: viper.GetInt(FlagAuditMaxBackups),
+		Redact:     viper.GetStringSlice(FlagAuditRedact),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+
+	return l, nil
+}
+
+// wrapClient instruments the client with the given metrics, if any, then caches its responses if configured so, and
+// traces the calls, cached or not. The cache, if any, is returned along with the wrapped client.
+func wrapClient(
+	ctx context.Context, client grpc.ClientConnInterface, m *metrics.Metrics,
+) (grpc.ClientConnInterface, *cache.ClientConn) {
+	if m != nil {
+		client = m.InstrumentClientConn(client)
+	}
+	if !viper.GetBool(FlagCacheEnabled) {
+		return tracing.InstrumentClientConn(client), nil
+	}
+
+	cached := cache.New(client, cache.Config{
+		TTL:                viper.GetDuration(FlagCacheTTL),
+		Size:               viper.GetInt(FlagCacheSize),
+		HeightPollInterval: viper.GetDuration(FlagCacheHeightPoll),
+	})
+	go cached.WatchHeight(ctx)
+	if m != nil {
+		m.RegisterCache(cached)
+	}
+
+	return tracing.InstrumentClientConn(cached), cached
+}
+
+// serveMetrics serves the metrics at /metrics on the given address, in the background until the context is done.
+func serveMetrics(ctx context.Context, addr string, m *metrics.Metrics) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	srv := &http.Server{Addr: addr, ReadHeaderTimeout: ReadHeaderTimeout, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	go func() {
+		log.Logger.Info().Str("addr", addr).Msg("serving metrics")
+		if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+			log.Logger.Error().Err(err).Msg("failed to serve metrics")
+		}
+	}()
+}
+
+// buildSearchOptions creates the full-text index of the dataverses, and their vector index if an embedder is
+// configured, both refreshed in the background if configured so. The full-text index, holding its store, is returned
+// along with the options.
+func buildSearchOptions(ctx context.Context, client grpc.ClientConnInterface) (*search.Index, []mcp.Option, error) {
+	var embedder embedding.Embedder
+	switch name := viper.GetString(FlagEmbedder); name {
+	case "":
+	case "hash":
+		embedder = embedding.NewHashEmbedder()
+	case "openai":
+		embedder = embedding.NewOpenAIEmbedder(
+			viper.GetString(FlagEmbedderURL), viper.GetString(FlagEmbedderModel), viper.GetString(FlagEmbedderAPIKey))
+	default:
+		return nil, nil, fmt.Errorf("--%s: unknown embedder %q", FlagEmbedder, name)
+	}
+
+	var store search.Store = search.NewMemoryStore()
+	if path := viper.GetString(FlagSearchStore); path != "" {
+		var err error
+		if store, err = search.OpenBoltStore(path); err != nil {
+			return nil, nil, fmt.Errorf("open search store: %w", err)
+		}
 	}
-	return mcp.NewServer(client, mode)
+	index := search.New(client, store, search.WithMaxDataverses(viper.GetInt(FlagSearchMaxDataverses)))
+	opts := []mcp.Option{mcp.WithSearchIndex(index)}
+
+	var vectors *semantic.Index
+	if embedder != nil {
+		vectors = semantic.New(client, embedder, semantic.WithMaxDataverses(viper.GetInt(FlagSearchMaxDataverses)))
+		opts = append(opts, mcp.WithSemanticIndex(vectors))
+	}
+
+	if addr := viper.GetString(FlagDataverseAddr); addr != "" {
+		go func() {
+			if _, err := index.Refresh(ctx, addr); err != nil {
+				log.Logger.Warn().Str("dataverse", addr).Err(err).Msg("failed to index the dataverse")
+			}
+		}()
+	}
+	if interval := viper.GetDuration(FlagSearchRefresh); interval > 0 {
+		go index.Run(ctx, interval)
+		if vectors != nil {
+			go vectors.Run(ctx, interval)
+		}
+	}
+
+	return index, opts, nil
 }
 
-// buildDataverseClient fetches a new gRPC client connection to the axone node.
-func buildDataverseClient() (grpc.ClientConnInterface, error) {
-	address := viper.GetString(FlagNodeGrpc)
-	clientConn, err := grpc.NewClient(
-		address,
-		grpc.WithTransportCredentials(getTransportCredentials()),
-		grpc.WithConnectParams(grpc.ConnectParams{
-			MinConnectTimeout: viper.GetDuration(FlagGrpcTimeout),
-		}),
+// buildRateLimiter creates the rate limiter configured by flags, if any limit is set.
+func buildRateLimiter() (*ratelimit.Limiter, error) {
+	var (
+		config ratelimit.Config
+		err    error
 	)
+	for flag, limit := range map[string]*ratelimit.Limit{
+		FlagRateLimitSession:   &config.Session,
+		FlagRateLimitPrincipal: &config.Principal,
+		FlagRateLimitTool:      &config.Tool,
+	} {
+		if *limit, err = ratelimit.ParseLimit(viper.GetString(flag)); err != nil {
+			return nil, fmt.Errorf("--%s: %w", flag, err)
+		}
+	}
+	config.DailyQuota = viper.GetInt(FlagQuotaDaily)
+
+	if config == (ratelimit.Config{}) {
+		return nil, nil //nolint:nilnil
+	}
+
+	var store ratelimit.QuotaStore = ratelimit.NewMemoryStore()
+	if path := viper.GetString(FlagQuotaStore); path != "" {
+		if store, err = ratelimit.OpenBoltStore(path); err != nil {
+			return nil, fmt.Errorf("open quota store: %w", err)
+		}
+	}
+
+	return ratelimit.New(config, store), nil
+}
+
+// dialSettings are the settings the connection to the node is dialed with, which a reload replaces the connection on
+// a change of only.
+type dialSettings struct {
+	addresses []string
+	dialOptions
+}
+
+// dialOptions are the settings of the connection to the node, apart from its endpoints.
+type dialOptions struct {
+	noTLS          bool
+	tlsSkipVerify  bool
+	timeout        time.Duration
+	healthInterval time.Duration
+	retries        int
+	retryBackoff   time.Duration
+}
+
+// buildDialSettings returns the settings of the connection to the node configured by flags.
+func buildDialSettings() dialSettings {
+	return dialSettings{
+		addresses: lo.Compact(lo.FlatMap(viper.GetStringSlice(FlagNodeGrpc), func(address string, _ int) []string {
+			return lo.Map(strings.Split(address, ","), func(a string, _ int) string { return strings.TrimSpace(a) })
+		})),
+		dialOptions: dialOptions{
+			noTLS:          viper.GetBool(FlagGrpcNoTLS),
+			tlsSkipVerify:  viper.GetBool(FlagGrpcTLSSkipVerify),
+			timeout:        viper.GetDuration(FlagGrpcTimeout),
+			healthInterval: viper.GetDuration(FlagGrpcHealthInterval),
+			retries:        viper.GetInt(FlagGrpcRetries),
+			retryBackoff:   viper.GetDuration(FlagGrpcRetryBackoff),
+		},
+	}
+}
+
+// equal tells whether the given settings are the same as these ones.
+func (d dialSettings) equal(other dialSettings) bool {
+	return slices.Equal(d.addresses, other.addresses) && d.dialOptions == other.dialOptions
+}
+
+// buildDataverseClient fetches a new gRPC client connection to the axone node, spread over all the configured
+// endpoints with health checking and failover.
+func buildDataverseClient(ctx context.Context) (grpc.ClientConnInterface, error) {
+	return dialDataverseClient(ctx, buildDialSettings())
+}
+
+// dialDataverseClient returns a new gRPC client connection to the axone node with the given settings.
+func dialDataverseClient(ctx context.Context, settings dialSettings) (grpc.ClientConnInterface, error) {
+	endpoints := make([]*grpcpool.Endpoint, 0, len(settings.addresses))
+	for _, address := range settings.addresses {
+		clientConn, err := grpc.NewClient(
+			address,
+			grpc.WithTransportCredentials(getTransportCredentials(settings)),
+			grpc.WithConnectParams(grpc.ConnectParams{
+				MinConnectTimeout: settings.timeout,
+			}),
+		)
+		if err != nil {
+			closeEndpoints(endpoints)
+			return nil, err
+		}
+		endpoints = append(endpoints, &grpcpool.Endpoint{Address: address, Conn: clientConn})
+	}
+
+	config := grpcpool.DefaultConfig()
+	config.HealthCheckInterval = settings.healthInterval
+	config.MaxAttempts = settings.retries + 1
+	config.Backoff = settings.retryBackoff
+
+	pool, err := grpcpool.New(config, endpoints...)
 	if err != nil {
+		closeEndpoints(endpoints)
 		return nil, err
 	}
+	if len(endpoints) > 1 {
+		go pool.Run(ctx)
+	}
 
-	return clientConn, nil
+	return pool, nil
+}
+
+// closeEndpoints closes the connections to the given endpoints.
+func closeEndpoints(endpoints []*grpcpool.Endpoint) {
+	for _, e := range endpoints {
+		if closer, ok := e.Conn.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}
 }
 
-func getTransportCredentials() grpccreds.TransportCredentials {
+func getTransportCredentials(settings dialSettings) grpccreds.TransportCredentials {
 	switch {
-	case viper.GetBool(FlagGrpcNoTLS):
+	case settings.noTLS:
 		return insecure.NewCredentials()
-	case viper.GetBool(FlagGrpcTLSSkipVerify):
+	case settings.tlsSkipVerify:
 		return grpccreds.NewTLS(&tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS12}) //nolint:gosec
 	default:
 		return grpccreds.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	goctx "context"
+	"path/filepath"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/axone-protocol/axone-mcp/internal/ratelimit"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBuildMCPServerFailure(t *testing.T) {
+	Convey("Given a quota store, and an unknown embedder", t, func() {
+		quotaStore := filepath.Join(t.TempDir(), "quota.db")
+		for flag, value := range map[string]string{
+			FlagQuotaDaily: "10",
+			FlagQuotaStore: quotaStore,
+			FlagEmbedder:   "foo",
+		} {
+			f := serveCmd.PersistentFlags().Lookup(flag)
+			So(f.Value.Set(value), ShouldBeNil)
+			f.Changed = true
+			Reset(func() { resetFlag(f) })
+		}
+
+		Convey("When building the server", func() {
+			ctrl := gomock.NewController(t)
+			Reset(ctrl.Finish)
+
+			ctx := WithGrpcClientConn(goctx.Background(), mocks.NewMockClientConnInterface(ctrl))
+			_, _, err := buildMCPServer(ctx, nil)
+
+			Convey("Then it should fail, the quota store opened before being closed", func() {
+				So(err, ShouldBeError, `--embedder: unknown embedder "foo"`)
+				store, err := ratelimit.OpenBoltStore(quotaStore)
+				So(err, ShouldBeNil)
+				So(store.Close(), ShouldBeNil)
+			})
+		})
+	})
+}
@@ -0,0 +1,247 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted replaces the value of the redacted arguments.
+const Redacted = "[REDACTED]"
+
+// DefaultMaxSize is the size, in bytes, beyond which the log file is rotated when not configured.
+const DefaultMaxSize = 100 << 20
+
+// Entry records a tool invocation.
+type Entry struct {
+	Time         time.Time      `json:"time"`
+	SessionID    string         `json:"sessionId"`
+	Principal    string         `json:"principal,omitempty"`
+	Tool         string         `json:"tool"`
+	Arguments    map[string]any `json:"arguments,omitempty"`
+	ResultDigest string         `json:"resultDigest,omitempty"`
+	LatencyMs    float64        `json:"latencyMs"`
+	ErrorCode    string         `json:"errorCode,omitempty"`
+}
+
+// Config configures the audit log.
+type Config struct {
+	// MaxSize is the size, in bytes, beyond which the file is rotated, DefaultMaxSize if zero.
+	MaxSize int64
+	// MaxBackups is the number of rotated files kept, all of them if zero.
+	MaxBackups int
+	// Redact lists the arguments whose value is not recorded, as "[<tool>:]<argument>" shell patterns
+	// (e.g. "api_key", "build_credential:claims", "*:secret*"), the argument pattern also matching the members of the
+	// objects they hold.
+	Redact []string
+}
+
+// Log is an append-only JSON Lines file of tool invocations, rotated when it grows too large: the current file is
+// renamed with the suffix .1, the previous .1 becoming .2, and so on.
+type Log struct {
+	path   string
+	config Config
+	rules  []rule
+	now    func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+type rule struct {
+	tool     string
+	argument string
+}
+
+// Option configures a Log.
+type Option func(*Log)
+
+// WithClock sets the clock giving the time of the entries.
+func WithClock(now func() time.Time) Option {
+	return func(l *Log) {
+		l.now = now
+	}
+}
+
+// Open opens, or creates, the audit log at the given path, entries being appended to it.
+func Open(filename string, config Config, opts ...Option) (*Log, error) {
+	if config.MaxSize <= 0 {
+		config.MaxSize = DefaultMaxSize
+	}
+	l := &Log{path: filename, config: config, now: time.Now}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	for _, r := range config.Redact {
+		tool, argument, ok := strings.Cut(r, ":")
+		if !ok {
+			tool, argument = "*", r
+		}
+		for _, pattern := range []string{tool, argument} {
+			if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+				return nil, fmt.Errorf("invalid redaction rule %q", r)
+			}
+		}
+		l.rules = append(l.rules, rule{tool: tool, argument: argument})
+	}
+
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Record appends the entry to the log, stamped with the current time if it has none, and its redacted arguments
+// replaced.
+func (l *Log) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = l.now()
+	}
+	entry.Time = entry.Time.UTC()
+	entry.Arguments = l.redact(entry.Tool, entry.Arguments)
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.size > 0 && l.size+int64(len(line)) > l.config.MaxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+
+	return err
+}
+
+// Close releases the log file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// Digest returns the SHA-256 digest of the JSON encoding of v, as "sha256:<hex>".
+func Digest(v any) (string, error) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bz)
+
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// redact returns a copy of the arguments of the tool, the values of the ones matching a redaction rule replaced, at any
+// depth of the objects and arrays they hold.
+func (l *Log) redact(tool string, arguments map[string]any) map[string]any {
+	rules := slices.DeleteFunc(slices.Clone(l.rules), func(r rule) bool {
+		match, _ := path.Match(r.tool, tool)
+		return !match
+	})
+	if len(arguments) == 0 || len(rules) == 0 {
+		return arguments
+	}
+
+	return redactObject(rules, arguments)
+}
+
+// redactObject returns a copy of the object, the values of the members matching a rule replaced, and the other ones
+// redacted in turn.
+func redactObject(rules []rule, object map[string]any) map[string]any {
+	out := make(map[string]any, len(object))
+	for name, value := range object {
+		if slices.ContainsFunc(rules, func(r rule) bool {
+			match, _ := path.Match(r.argument, name)
+			return match
+		}) {
+			out[name] = Redacted
+			continue
+		}
+		out[name] = redactValue(rules, value)
+	}
+
+	return out
+}
+
+// redactValue returns a copy of the value, the members of the objects it holds redacted.
+func redactValue(rules []rule, value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return redactObject(rules, v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(rules, item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+func (l *Log) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+
+	return nil
+}
+
+// rotate shifts the rotated files, dropping the oldest beyond MaxBackups, and starts a new file.
+func (l *Log) rotate() error {
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+
+	n := 1
+	for ; ; n++ {
+		if _, err := os.Stat(backup(l.path, n)); err != nil {
+			break
+		}
+	}
+	for ; n > 1; n-- {
+		if l.config.MaxBackups > 0 && n > l.config.MaxBackups {
+			if err := os.Remove(backup(l.path, n-1)); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Rename(backup(l.path, n-1), backup(l.path, n)); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(l.path, backup(l.path, 1)); err != nil {
+		return err
+	}
+
+	return l.open()
+}
+
+func backup(filename string, n int) string {
+	return fmt.Sprintf("%s.%d", filename, n)
+}
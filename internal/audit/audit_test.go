@@ -0,0 +1,132 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func readEntries(filename string) []Entry {
+	bz, err := os.ReadFile(filename)
+	So(err, ShouldBeNil)
+
+	var entries []Entry
+	for _, line := range strings.Split(strings.TrimSpace(string(bz)), "\n") {
+		if line == "" {
+			continue
+		}
+		var e Entry
+		So(json.Unmarshal([]byte(line), &e), ShouldBeNil)
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestLog(t *testing.T) {
+	Convey("Given an audit log redacting sensitive arguments", t, func() {
+		filename := filepath.Join(t.TempDir(), "audit.jsonl")
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		l, err := Open(filename, Config{Redact: []string{"api_key", "build_credential:claims"}},
+			WithClock(func() time.Time { return now }))
+		So(err, ShouldBeNil)
+		Reset(func() { _ = l.Close() })
+
+		Convey("When tool invocations are recorded", func() {
+			So(l.Record(Entry{
+				SessionID: "1234",
+				Principal: "alice",
+				Tool:      "build_credential",
+				Arguments: map[string]any{"template": "dataset", "claims": map[string]any{"title": "secret"}},
+				LatencyMs: 1.5,
+			}), ShouldBeNil)
+			So(l.Record(Entry{
+				SessionID: "1234",
+				Tool:      "get_dataverse_info",
+				Arguments: map[string]any{
+					"dataverse": "axone1foo", "api_key": "s3cr3t", "claims": "kept",
+					"backends": []any{map[string]any{"url": "https://ex.org", "api_key": "n3st3d"}},
+				},
+				ErrorCode: "INVALID_ADDRESS",
+			}), ShouldBeNil)
+
+			Convey("Then they should be appended as JSON lines, redacted at any depth", func() {
+				entries := readEntries(filename)
+				So(entries, ShouldHaveLength, 2)
+				So(entries[0].Time, ShouldEqual, now)
+				So(entries[0].Principal, ShouldEqual, "alice")
+				So(entries[0].Arguments, ShouldResemble, map[string]any{"template": "dataset", "claims": Redacted})
+				So(entries[0].LatencyMs, ShouldEqual, 1.5)
+				So(entries[1].Arguments, ShouldResemble, map[string]any{
+					"dataverse": "axone1foo", "api_key": Redacted, "claims": "kept",
+					"backends": []any{map[string]any{"url": "https://ex.org", "api_key": Redacted}},
+				})
+				So(entries[1].ErrorCode, ShouldEqual, "INVALID_ADDRESS")
+			})
+
+			Convey("And the log is reopened", func() {
+				So(l.Close(), ShouldBeNil)
+				l, err = Open(filename, Config{})
+				So(err, ShouldBeNil)
+				So(l.Record(Entry{SessionID: "5678", Tool: "resolve_did"}), ShouldBeNil)
+
+				Convey("Then the new entries should be appended to the existing ones", func() {
+					entries := readEntries(filename)
+					So(entries, ShouldHaveLength, 3)
+					So(entries[2].SessionID, ShouldEqual, "5678")
+				})
+			})
+		})
+	})
+
+	Convey("Given an audit log rotated beyond a small size", t, func() {
+		filename := filepath.Join(t.TempDir(), "audit.jsonl")
+		l, err := Open(filename, Config{MaxSize: 200, MaxBackups: 2})
+		So(err, ShouldBeNil)
+		Reset(func() { _ = l.Close() })
+
+		Convey("When many entries are recorded", func() {
+			for i := 0; i < 10; i++ {
+				So(l.Record(Entry{SessionID: "1234", Tool: "get_dataverse_info",
+					Arguments: map[string]any{"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"}}),
+					ShouldBeNil)
+			}
+
+			Convey("Then the older files should be rotated, and only the configured number of them kept", func() {
+				So(readEntries(filename), ShouldHaveLength, 1)
+				So(readEntries(filename+".1"), ShouldHaveLength, 1)
+				So(readEntries(filename+".2"), ShouldHaveLength, 1)
+				_, err := os.Stat(filename + ".3")
+				So(os.IsNotExist(err), ShouldBeTrue)
+			})
+		})
+	})
+
+	Convey("Given an invalid redaction rule", t, func() {
+		_, err := Open(filepath.Join(t.TempDir(), "audit.jsonl"), Config{Redact: []string{"tool:[arg"}})
+
+		Convey("Then the log should not be opened", func() {
+			So(err, ShouldBeError, `invalid redaction rule "tool:[arg"`)
+		})
+	})
+}
+
+func TestDigest(t *testing.T) {
+	Convey("Given a value", t, func() {
+		v := map[string]any{"name": "dataverse-42"}
+
+		Convey("When computing its digest", func() {
+			digest, err := Digest(v)
+
+			Convey("Then it should be the SHA-256 of its JSON encoding", func() {
+				So(err, ShouldBeNil)
+				So(digest, ShouldEqual, "sha256:"+
+					"11e3b5aabe4dc28687e94fc746113d465cec443003f947bc71767c9a21e47e6d")
+			})
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+const (
+	// Prefix is the bech32 human readable part of the Axone addresses.
+	Prefix = "axone"
+	// AccountLength is the length in bytes of an account address.
+	AccountLength = 20
+	// ContractLength is the length in bytes of a contract address.
+	ContractLength = 32
+)
+
+const (
+	// AccountPattern is the JSON Schema pattern of an account address.
+	AccountPattern = `^axone1[02-9ac-hj-np-z]{38}$`
+	// ContractPattern is the JSON Schema pattern of a contract address.
+	ContractPattern = `^axone1[02-9ac-hj-np-z]{58}$`
+)
+
+var ErrInvalidAddress = errors.New("invalid address")
+
+var (
+	accountRegexp  = regexp.MustCompile(AccountPattern)
+	contractRegexp = regexp.MustCompile(ContractPattern)
+)
+
+// ValidateAccount checks the given string is a well-formed account address, with a valid checksum.
+func ValidateAccount(addr string) error {
+	return validate(addr, AccountLength, accountRegexp)
+}
+
+// ValidateContract checks the given string is a well-formed contract address, with a valid checksum.
+func ValidateContract(addr string) error {
+	return validate(addr, ContractLength, contractRegexp)
+}
+
+func validate(addr string, length int, pattern *regexp.Regexp) error {
+	hrp, bz, err := bech32.DecodeAndConvert(addr)
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidAddress, addr, err)
+	}
+	if hrp != Prefix {
+		return fmt.Errorf("%w %q: prefix is %q, expected %q", ErrInvalidAddress, addr, hrp, Prefix)
+	}
+	if len(bz) != length {
+		return fmt.Errorf("%w %q: %d bytes long, expected %d", ErrInvalidAddress, addr, len(bz), length)
+	}
+	if !pattern.MatchString(addr) {
+		return fmt.Errorf("%w %q: not in canonical lower case", ErrInvalidAddress, addr)
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidate(t *testing.T) {
+	Convey("Given addresses to validate", t, func() {
+		cases := []struct {
+			addr     string
+			validate func(string) error
+			err      string
+		}{
+			{
+				addr:     "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+				validate: ValidateContract,
+			},
+			{
+				addr:     "axone1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqs9vgnq",
+				validate: ValidateAccount,
+			},
+			{
+				addr:     "axone1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqs9vgnq",
+				validate: ValidateContract,
+				err:      `invalid address "axone1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqs9vgnq": 20 bytes long, expected 32`,
+			},
+			{
+				addr:     "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2x",
+				validate: ValidateContract,
+				err: `invalid address "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2x": ` +
+					`decoding bech32 failed: invalid checksum (expected cvlt2w got cvlt2x)`,
+			},
+			{
+				addr:     "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq0fr2sh",
+				validate: ValidateContract,
+				err: `invalid address "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqq0fr2sh": ` +
+					`prefix is "cosmos", expected "axone"`,
+			},
+			{
+				addr:     "AXONE1XT4AHZZ2X8HPKC0TK6EKTE9X6CRW4W6U0R67CYT3KZ9SYH24PD7SCVLT2W",
+				validate: ValidateContract,
+				err: `invalid address "AXONE1XT4AHZZ2X8HPKC0TK6EKTE9X6CRW4W6U0R67CYT3KZ9SYH24PD7SCVLT2W": ` +
+					`not in canonical lower case`,
+			},
+			{
+				addr:     "",
+				validate: ValidateContract,
+				err:      `invalid address "": decoding bech32 failed: invalid bech32 string length 0`,
+			},
+		}
+
+		for i, c := range cases {
+			Convey(fmt.Sprintf("When validating %q (case %d)", c.addr, i), func() {
+				err := c.validate(c.addr)
+
+				Convey("Then the result should be as expected", func() {
+					if c.err == "" {
+						So(err, ShouldBeNil)
+					} else {
+						So(errors.Is(err, ErrInvalidAddress), ShouldBeTrue)
+						So(err.Error(), ShouldEqual, c.err)
+					}
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package cognitarium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	schema "github.com/axone-protocol/axone-contract-schema/go/cognitarium-schema/v6"
+	"github.com/axone-protocol/axone-mcp/internal/axone/wasm"
+	"google.golang.org/grpc"
+)
+
+// DefaultPageSize is the default number of solutions asked per select query, the default query limit of the stores.
+const DefaultPageSize = 30
+
+// ErrTooManyClaims is returned when the claims of a single credential fill a whole page, so that they cannot be told
+// complete.
+var ErrTooManyClaims = errors.New("too many claims in a credential")
+
+// ClaimLiteral is a literal value claimed about a resource by a credential.
+type ClaimLiteral struct {
+	Credential string
+	Subject    string
+	Property   string
+	Value      string
+	Language   string
+}
+
+// pagedSelect is a select query whose solutions are restricted to the ones binding a variable to an IRI within a
+// window. It is encoded by hand, as the generated schema encodes the operands of the comparisons as an object
+// instead of the array the contract expects.
+type pagedSelect struct {
+	Query pagedQuery `json:"query"`
+}
+
+type pagedQuery struct {
+	Limit    int                 `json:"limit"`
+	Prefixes []schema.Prefix     `json:"prefixes"`
+	Select   []schema.SelectItem `json:"select"`
+	Where    pagedWhereClause    `json:"where"`
+}
+
+type pagedWhereClause struct {
+	Bgp    *schema.WhereClause_Bgp `json:"bgp,omitempty"`
+	Filter *pagedFilter            `json:"filter,omitempty"`
+}
+
+type pagedFilter struct {
+	Expr  pagedExpression    `json:"expr"`
+	Inner schema.WhereClause `json:"inner"`
+}
+
+// pagedExpression is a comparison of two schema.Expression, or a conjunction of comparisons, keyed by its operator.
+type pagedExpression map[string][]any
+
+var claimLiteralsSelect = wasm.NewQuery[*pagedSelect, schema.SelectResponse]("select")
+
+// claimLiteralsQuery returns the query of the literal claims of the credentials whose IRI is greater than after and
+// up to upTo, each bound being ignored if empty.
+func claimLiteralsQuery(after, upTo string, limit int) *pagedSelect {
+	variable := func(name string) *schema.VarOrNode_Variable { return ref(schema.VarOrNode_Variable(name)) }
+	bgp := &schema.WhereClause_Bgp{
+		Patterns: []schema.TriplePattern{
+			{
+				Subject:   schema.VarOrNode{Variable: variable("credential")},
+				Predicate: schema.VarOrNamedNode{NamedNode: &schema.VarOrNamedNode_NamedNode{Full: &VcBodySubject}},
+				Object:    schema.VarOrNodeOrLiteral{Variable: ref(schema.VarOrNodeOrLiteral_Variable("subject"))},
+			},
+			{
+				Subject:   schema.VarOrNode{Variable: variable("credential")},
+				Predicate: schema.VarOrNamedNode{NamedNode: &schema.VarOrNamedNode_NamedNode{Full: &VcBodyClaim}},
+				Object:    schema.VarOrNodeOrLiteral{Variable: ref(schema.VarOrNodeOrLiteral_Variable("claim"))},
+			},
+			{
+				Subject:   schema.VarOrNode{Variable: variable("claim")},
+				Predicate: schema.VarOrNamedNode{Variable: ref(schema.VarOrNamedNode_Variable("property"))},
+				Object:    schema.VarOrNodeOrLiteral{Variable: ref(schema.VarOrNodeOrLiteral_Variable("value"))},
+			},
+		},
+	}
+
+	query := &pagedSelect{Query: pagedQuery{
+		Limit:    limit,
+		Prefixes: []schema.Prefix{},
+		Select: []schema.SelectItem{
+			{Variable: ref(schema.SelectItem_Variable("credential"))},
+			{Variable: ref(schema.SelectItem_Variable("subject"))},
+			{Variable: ref(schema.SelectItem_Variable("property"))},
+			{Variable: ref(schema.SelectItem_Variable("value"))},
+		},
+	}}
+	compare := func(operator, iri string) pagedExpression {
+		return pagedExpression{operator: {
+			schema.Expression{Variable: ref(schema.Expression_Variable("credential"))},
+			schema.Expression{NamedNode: &schema.Expression_NamedNode{Full: ref(schema.IRI_Full(iri))}},
+		}}
+	}
+	var bounds []any
+	if after != "" {
+		bounds = append(bounds, compare("greater", after))
+	}
+	if upTo != "" {
+		bounds = append(bounds, compare("less_or_equal", upTo))
+	}
+	if len(bounds) == 0 {
+		query.Query.Where.Bgp = bgp
+	} else {
+		query.Query.Where.Filter = &pagedFilter{
+			Expr:  pagedExpression{"and": bounds},
+			Inner: schema.WhereClause{Bgp: bgp},
+		}
+	}
+
+	return query
+}
+
+// GetClaimLiterals queries all the literal claims of the credentials held by the cognitarium at the given address,
+// by pages of the given size.
+//
+// The stores answering the solutions in no particular order, the pages are windows over the IRIs of the credentials: a
+// window answered in full may have left some out, so it is narrowed until it is not, and the next one starts after
+// it. The claims of a credential filling a whole page on their own cannot be told complete, and fail with
+// ErrTooManyClaims.
+func GetClaimLiterals(
+	ctx context.Context, cc grpc.ClientConnInterface, address string, pageSize int, opts ...grpc.CallOption,
+) ([]ClaimLiteral, error) {
+	var (
+		literals    []ClaimLiteral
+		after, upTo string
+	)
+	for {
+		response, err := claimLiteralsSelect.Do(ctx, cc, address, claimLiteralsQuery(after, upTo, pageSize), opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		bindings := response.Results.Bindings
+		if len(bindings) < pageSize {
+			literals = append(literals, claimLiterals(bindings)...)
+			if upTo == "" {
+				return literals, nil
+			}
+			after, upTo = upTo, ""
+			continue
+		}
+
+		// Narrow the window down to the credentials before the last one of the page, or to this one if alone.
+		credentials := make([]string, 0, len(bindings))
+		for _, b := range bindings {
+			credentials = append(credentials, iriOf(b["credential"]))
+		}
+		credentials = slices.Compact(slices.Sorted(slices.Values(credentials)))
+		bound := credentials[max(len(credentials)-2, 0)]
+		if bound <= after || (upTo != "" && bound > upTo) {
+			return nil, fmt.Errorf("%w: credentials are answered out of the window", wasm.ErrDecode)
+		}
+		if bound == upTo {
+			return nil, fmt.Errorf("%w: the claims of %s fill a page of %d solutions", ErrTooManyClaims, bound, pageSize)
+		}
+		upTo = bound
+	}
+}
+
+// claimLiterals returns the literal claims of the given solutions, skipping the other ones.
+func claimLiterals(bindings []map[string]schema.Value) []ClaimLiteral {
+	literals := make([]ClaimLiteral, 0, len(bindings))
+	for _, b := range bindings {
+		value, ok := b["value"].ValueType.(schema.Value_Literal)
+		if !ok {
+			continue
+		}
+		l := ClaimLiteral{
+			Credential: iriOf(b["credential"]),
+			Subject:    iriOf(b["subject"]),
+			Property:   iriOf(b["property"]),
+			Value:      value.Value,
+		}
+		if value.Lang != nil {
+			l.Language = *value.Lang
+		}
+		literals = append(literals, l)
+	}
+
+	return literals
+}
+
+// iriOf returns the full IRI bound to a variable, empty if not bound to a full IRI.
+func iriOf(v schema.Value) string {
+	if uri, ok := v.ValueType.(schema.URI); ok && uri.Value.Full != nil {
+		return string(*uri.Value.Full)
+	}
+
+	return ""
+}
@@ -97,10 +97,10 @@ func GetResourceGovAddrQuery(resource string) schema.SelectQuery {
 
 // GetGovernanceAddressForResource queries the governance address for a given resource DID.
 func GetGovernanceAddressForResource(
-	ctx context.Context, cc grpc.ClientConnInterface, address string, resourceDID string,
+	ctx context.Context, cc grpc.ClientConnInterface, address string, resourceDID string, opts ...grpc.CallOption,
 ) (string, error) {
 	query := GetResourceGovAddrQuery(resourceDID)
-	response, err := Select(ctx, cc, address, &schema.QueryMsg_Select{Query: query})
+	response, err := Select(ctx, cc, address, &schema.QueryMsg_Select{Query: query}, opts...)
 	if err != nil {
 		return "", err
 	}
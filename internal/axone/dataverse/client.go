@@ -2,47 +2,33 @@ package dataverse
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"errors"
 
-	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
 	schema "github.com/axone-protocol/axone-contract-schema/go/dataverse-schema/v6"
+	"github.com/axone-protocol/axone-mcp/internal/axone/wasm"
 	"google.golang.org/grpc"
 )
 
+// ErrNoTriplestore is returned when the dataverse has no triplestore attached.
+var ErrNoTriplestore = errors.New("no triplestore address found")
+
+var dataverseQuery = wasm.NewQuery[*schema.QueryMsg_Dataverse, schema.DataverseResponse]("dataverse")
+
 func Dataverse(ctx context.Context, cc grpc.ClientConnInterface,
 	address string, req *schema.QueryMsg_Dataverse, opts ...grpc.CallOption,
 ) (*schema.DataverseResponse, error) {
-	rawQueryData, err := json.Marshal(map[string]any{"dataverse": req})
-	if err != nil {
-		return nil, fmt.Errorf("encode dataverse query (%s): %w", address, err)
-	}
-
-	rawResponseData, err := queryContract(ctx, cc, address, rawQueryData, opts...)
-	if err != nil {
-		return nil, err
-	}
-
-	var response schema.DataverseResponse
-	if err := json.Unmarshal(rawResponseData, &response); err != nil {
-		return nil, fmt.Errorf("decode dataverse response (%s): %w", address, err)
-	}
-
-	return &response, nil
+	return dataverseQuery.Do(ctx, cc, address, req, opts...)
 }
 
-func queryContract(ctx context.Context, cc grpc.ClientConnInterface,
-	address string, rawQueryData []byte, opts ...grpc.CallOption,
-) ([]byte, error) {
-	in := &wasmtypes.QuerySmartContractStateRequest{
-		Address:   address,
-		QueryData: rawQueryData,
+// Triplestore returns the address of the triplestore of the dataverse, failing with ErrNoTriplestore if it has none.
+func Triplestore(ctx context.Context, cc grpc.ClientConnInterface, address string, opts ...grpc.CallOption) (string, error) {
+	info, err := Dataverse(ctx, cc, address, &schema.QueryMsg_Dataverse{}, opts...)
+	if err != nil {
+		return "", err
 	}
-	out := &wasmtypes.QuerySmartContractStateResponse{}
-
-	if err := cc.Invoke(ctx, "/cosmwasm.wasm.v1.Query/SmartContractState", in, out, opts...); err != nil {
-		return nil, err
+	if info.TriplestoreAddress == "" {
+		return "", ErrNoTriplestore
 	}
 
-	return out.Data, nil
+	return string(info.TriplestoreAddress), nil
 }
@@ -0,0 +1,67 @@
+package height
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the gRPC metadata key carrying the block height a query targets, or was answered at.
+const MetadataKey = grpctypes.GRPCBlockHeightHeader
+
+var ErrPruned = errors.New("state not available at the requested height")
+
+// prunedMessages are fragments of the errors returned by nodes when the state at a height is no longer stored.
+var prunedMessages = []string{
+	"version does not exist",
+	"has been pruned",
+	"failed to load state at height",
+}
+
+// WithHeight returns a context whose queries target the chain state at the given block height.
+func WithHeight(ctx context.Context, height int64) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, strconv.FormatInt(height, 10))
+}
+
+// FromOutgoingContext returns the block height the queries made with the context target, if pinned.
+func FromOutgoingContext(ctx context.Context) (int64, bool) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return FromMetadata(md)
+}
+
+// FromMetadata returns the block height carried by the given metadata, typically the header of a query response.
+func FromMetadata(md metadata.MD) (int64, bool) {
+	values := md.Get(MetadataKey)
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	h, err := strconv.ParseInt(values[len(values)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return h, true
+}
+
+// WrapError wraps the error with ErrPruned if it reports the node does not hold the state at the requested height.
+func WrapError(err error, height int64) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.ToLower(status.Convert(err).Message())
+	for _, fragment := range prunedMessages {
+		if strings.Contains(msg, fragment) {
+			return fmt.Errorf("%w: height %d has been pruned by the node or is in the future (%v)", ErrPruned, height, err)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+package height
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestHeight(t *testing.T) {
+	Convey("Given a context pinned to a block height", t, func() {
+		ctx := WithHeight(context.Background(), 42)
+
+		Convey("Then the height should be carried by the outgoing metadata", func() {
+			h, ok := FromOutgoingContext(ctx)
+			So(ok, ShouldBeTrue)
+			So(h, ShouldEqual, 42)
+		})
+	})
+
+	Convey("Given a context not pinned to any block height", t, func() {
+		_, ok := FromOutgoingContext(context.Background())
+
+		Convey("Then no height should be found", func() {
+			So(ok, ShouldBeFalse)
+		})
+	})
+
+	Convey("Given a malformed height header", t, func() {
+		_, ok := FromMetadata(metadata.Pairs(MetadataKey, "foo"))
+
+		Convey("Then no height should be found", func() {
+			So(ok, ShouldBeFalse)
+		})
+	})
+
+	Convey("Given errors returned by a node", t, func() {
+		cases := []struct {
+			err    error
+			pruned bool
+		}{
+			{status.Error(codes.InvalidArgument, "failed to load state at height 12; version does not exist"), true},
+			{status.Error(codes.Unknown, "height 12 has been pruned"), true},
+			{status.Error(codes.NotFound, "contract not found"), false},
+			{errors.New("boom"), false},
+		}
+
+		for _, c := range cases {
+			Convey("When wrapping "+c.err.Error(), func() {
+				err := WrapError(c.err, 12)
+
+				Convey("Then it should be reported as pruned only if the state is missing", func() {
+					So(errors.Is(err, ErrPruned), ShouldEqual, c.pruned)
+				})
+			})
+		}
+	})
+}
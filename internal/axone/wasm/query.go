@@ -0,0 +1,165 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	SmartContractStateMethod = "/cosmwasm.wasm.v1.Query/SmartContractState"
+	RawContractStateMethod   = "/cosmwasm.wasm.v1.Query/RawContractState"
+)
+
+var (
+	ErrQueryTooLarge    = errors.New("query too large")
+	ErrResponseTooLarge = errors.New("response too large")
+	ErrOutOfGas         = errors.New("query ran out of gas")
+	ErrDecode           = errors.New("decode response")
+)
+
+// Limits bounds the size of the queries sent to, and the responses received from, the contracts. The gas consumed by a
+// query is bounded by the node itself: exceeding it is reported as ErrOutOfGas.
+type Limits struct {
+	// MaxQuerySize is the maximum size in bytes of an encoded query, zero meaning unlimited.
+	MaxQuerySize int
+	// MaxResponseSize is the maximum size in bytes of a response, zero meaning unlimited.
+	MaxResponseSize int
+}
+
+// responseOverhead is the room left for the protobuf framing of a response, on top of its data.
+const responseOverhead = 1024
+
+// DefaultLimits are the limits applied to the queries not overriding them.
+var DefaultLimits = Limits{
+	MaxQuerySize:    64 * 1024,
+	MaxResponseSize: 4 * 1024 * 1024,
+}
+
+// outOfGasMessages are fragments of the errors returned by nodes when a query exceeds the query gas limit.
+var outOfGasMessages = []string{
+	"out of gas",
+	"gas limit exceeded",
+}
+
+// Query is a typed smart query of a contract, sent as {"<name>": <Req>} and answered with a JSON encoded Resp.
+type Query[Req, Resp any] struct {
+	Name   string
+	Limits Limits
+}
+
+// NewQuery returns the smart query with the given name, bounded by DefaultLimits.
+func NewQuery[Req, Resp any](name string) Query[Req, Resp] {
+	return Query[Req, Resp]{Name: name, Limits: DefaultLimits}
+}
+
+// Do sends the query to the contract at the given address and decodes its response.
+func (q Query[Req, Resp]) Do(ctx context.Context, cc grpc.ClientConnInterface,
+	address string, req Req, opts ...grpc.CallOption,
+) (*Resp, error) {
+	rawQueryData, err := json.Marshal(map[string]any{q.Name: req})
+	if err != nil {
+		return nil, q.wrap(address, fmt.Errorf("encode query: %w", err))
+	}
+
+	rawResponseData, err := SmartContractState(ctx, cc, address, rawQueryData, q.Limits, opts...)
+	if err != nil {
+		return nil, q.wrap(address, err)
+	}
+
+	var response Resp
+	if err := json.Unmarshal(rawResponseData, &response); err != nil {
+		return nil, q.wrap(address, fmt.Errorf("%w: %w", ErrDecode, err))
+	}
+
+	return &response, nil
+}
+
+func (q Query[Req, Resp]) wrap(address string, err error) error {
+	return fmt.Errorf("query %s (%s): %w", q.Name, address, err)
+}
+
+// SmartContractState sends the raw smart query to the contract at the given address and returns its raw response.
+func SmartContractState(ctx context.Context, cc grpc.ClientConnInterface,
+	address string, rawQueryData []byte, limits Limits, opts ...grpc.CallOption,
+) ([]byte, error) {
+	if limits.MaxQuerySize > 0 && len(rawQueryData) > limits.MaxQuerySize {
+		return nil, fmt.Errorf("%w: %d bytes exceeds %d", ErrQueryTooLarge, len(rawQueryData), limits.MaxQuerySize)
+	}
+
+	in := &wasmtypes.QuerySmartContractStateRequest{
+		Address:   address,
+		QueryData: rawQueryData,
+	}
+	out := &wasmtypes.QuerySmartContractStateResponse{}
+
+	if err := invoke(ctx, cc, SmartContractStateMethod, in, out, limits, opts...); err != nil {
+		return nil, err
+	}
+	if err := checkResponseSize(out.Data, limits); err != nil {
+		return nil, err
+	}
+
+	return out.Data, nil
+}
+
+// RawContractState returns the raw value stored under the given key in the state of the contract at the given address.
+func RawContractState(ctx context.Context, cc grpc.ClientConnInterface,
+	address string, key []byte, opts ...grpc.CallOption,
+) ([]byte, error) {
+	in := &wasmtypes.QueryRawContractStateRequest{
+		Address:   address,
+		QueryData: key,
+	}
+	out := &wasmtypes.QueryRawContractStateResponse{}
+
+	if err := invoke(ctx, cc, RawContractStateMethod, in, out, DefaultLimits, opts...); err != nil {
+		return nil, fmt.Errorf("raw query %q (%s): %w", key, address, err)
+	}
+
+	if err := checkResponseSize(out.Data, DefaultLimits); err != nil {
+		return nil, fmt.Errorf("raw query %q (%s): %w", key, address, err)
+	}
+
+	return out.Data, nil
+}
+
+func invoke(ctx context.Context, cc grpc.ClientConnInterface,
+	method string, in, out any, limits Limits, opts ...grpc.CallOption,
+) error {
+	if limits.MaxResponseSize > 0 {
+		opts = append(opts, grpc.MaxCallRecvMsgSize(limits.MaxResponseSize+responseOverhead))
+	}
+
+	err := cc.Invoke(ctx, method, in, out, opts...)
+	if err == nil {
+		return nil
+	}
+
+	msg := strings.ToLower(status.Convert(err).Message())
+	for _, fragment := range outOfGasMessages {
+		if strings.Contains(msg, fragment) {
+			return fmt.Errorf("%w (%v)", ErrOutOfGas, err)
+		}
+	}
+	if h, ok := height.FromOutgoingContext(ctx); ok {
+		return height.WrapError(err, h)
+	}
+
+	return err
+}
+
+func checkResponseSize(data []byte, limits Limits) error {
+	if limits.MaxResponseSize > 0 && len(data) > limits.MaxResponseSize {
+		return fmt.Errorf("%w: %d bytes exceeds %d", ErrResponseTooLarge, len(data), limits.MaxResponseSize)
+	}
+
+	return nil
+}
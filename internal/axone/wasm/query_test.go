@@ -0,0 +1,126 @@
+package wasm
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fooRequest struct {
+	Bar string `json:"bar"`
+}
+
+type fooResponse struct {
+	Baz int `json:"baz"`
+}
+
+func TestQuery(t *testing.T) {
+	Convey("Given a typed smart query", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		cc := mocks.NewMockClientConnInterface(ctrl)
+		query := NewQuery[fooRequest, fooResponse]("foo")
+		query.Limits = Limits{MaxQuerySize: 32, MaxResponseSize: 16}
+
+		expect := func(queryData, respData string, err error) {
+			cc.EXPECT().
+				Invoke(gomock.Any(), SmartContractStateMethod,
+					&wasmtypes.QuerySmartContractStateRequest{Address: "axone1", QueryData: []byte(queryData)},
+					&wasmtypes.QuerySmartContractStateResponse{},
+					gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _, reply any, _ ...grpc.CallOption) error {
+					reply.(*wasmtypes.QuerySmartContractStateResponse).Data = []byte(respData)
+					return err
+				}).Times(1)
+		}
+
+		Convey("When the contract answers", func() {
+			expect(`{"foo":{"bar":"qux"}}`, `{"baz":42}`, nil)
+
+			got, err := query.Do(context.Background(), cc, "axone1", fooRequest{Bar: "qux"})
+
+			Convey("Then the response should be decoded", func() {
+				So(err, ShouldBeNil)
+				So(got, ShouldResemble, &fooResponse{Baz: 42})
+			})
+		})
+
+		Convey("When the contract answers with an unexpected response", func() {
+			expect(`{"foo":{"bar":"qux"}}`, `"nope"`, nil)
+
+			_, err := query.Do(context.Background(), cc, "axone1", fooRequest{Bar: "qux"})
+
+			Convey("Then the error should name the query and the contract", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "query foo (axone1): decode response: ")
+			})
+		})
+
+		Convey("When the query exceeds the size limit", func() {
+			_, err := query.Do(context.Background(), cc, "axone1", fooRequest{Bar: strings.Repeat("x", 32)})
+
+			Convey("Then it should not be sent", func() {
+				So(errors.Is(err, ErrQueryTooLarge), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the response exceeds the size limit", func() {
+			expect(`{"foo":{"bar":"qux"}}`, `{"baz":4242424242424242}`, nil)
+
+			_, err := query.Do(context.Background(), cc, "axone1", fooRequest{Bar: "qux"})
+
+			Convey("Then it should be rejected", func() {
+				So(errors.Is(err, ErrResponseTooLarge), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the query runs out of gas", func() {
+			expect(`{"foo":{"bar":"qux"}}`, "",
+				status.Error(codes.Unknown, "query wasm contract failed: out of gas in location: wasm contract"))
+
+			_, err := query.Do(context.Background(), cc, "axone1", fooRequest{Bar: "qux"})
+
+			Convey("Then it should be reported as such", func() {
+				So(errors.Is(err, ErrOutOfGas), ShouldBeTrue)
+				So(err.Error(), ShouldStartWith, "query foo (axone1): query ran out of gas")
+			})
+		})
+	})
+}
+
+func TestRawContractState(t *testing.T) {
+	Convey("Given a contract state", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		cc := mocks.NewMockClientConnInterface(ctrl)
+		cc.EXPECT().
+			Invoke(gomock.Any(), RawContractStateMethod,
+				&wasmtypes.QueryRawContractStateRequest{Address: "axone1", QueryData: []byte("config")},
+				&wasmtypes.QueryRawContractStateResponse{},
+				gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _, reply any, _ ...grpc.CallOption) error {
+				reply.(*wasmtypes.QueryRawContractStateResponse).Data = []byte(`{"owner":"axone2"}`)
+				return nil
+			}).Times(1)
+
+		Convey("When reading a key", func() {
+			got, err := RawContractState(context.Background(), cc, "axone1", []byte("config"))
+
+			Convey("Then the raw value should be returned", func() {
+				So(err, ShouldBeNil)
+				So(string(got), ShouldEqual, `{"owner":"axone2"}`)
+			})
+		})
+	})
+}
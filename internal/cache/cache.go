@@ -0,0 +1,258 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/axone-protocol/axone-mcp/internal/axone/wasm"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	SmartContractStateMethod = wasm.SmartContractStateMethod
+	GetLatestBlockMethod     = "/cosmos.base.tendermint.v1beta1.Service/GetLatestBlock"
+)
+
+// Config holds the cache settings.
+type Config struct {
+	// TTL is the maximum time a response is served from the cache.
+	TTL time.Duration
+	// Size is the maximum number of responses kept, the least recently used ones being evicted first.
+	Size int
+	// HeightPollInterval is the interval at which the latest block height is polled, the whole cache being invalidated
+	// each time it advances. Zero disables height-aware invalidation.
+	HeightPollInterval time.Duration
+}
+
+// Stats reports the cache activity.
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Invalidations uint64
+	Entries       int
+}
+
+type entry struct {
+	key       string
+	data      []byte
+	header    metadata.MD
+	expiresAt time.Time
+}
+
+// ClientConn is a grpc.ClientConnInterface caching the responses of smart contract queries, keyed on the contract
+// address, the query bytes and the block height targeted, if any. Any other call is passed through.
+type ClientConn struct {
+	next   grpc.ClientConnInterface
+	config Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	// generation is incremented by each purge, for the responses fetched before to be dropped rather than stored.
+	generation uint64
+
+	height        atomic.Int64
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	evictions     atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+var _ grpc.ClientConnInterface = (*ClientConn)(nil)
+
+// New wraps the given connection with a cache.
+func New(next grpc.ClientConnInterface, config Config) *ClientConn {
+	return &ClientConn{
+		next:    next,
+		config:  config,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *ClientConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	in, okIn := args.(*wasmtypes.QuerySmartContractStateRequest)
+	out, okOut := reply.(*wasmtypes.QuerySmartContractStateResponse)
+	if method != SmartContractStateMethod || !okIn || !okOut {
+		return c.next.Invoke(ctx, method, args, reply, opts...)
+	}
+
+	key := in.Address + "\x00" + string(in.QueryData)
+	if h, ok := height.FromOutgoingContext(ctx); ok {
+		key += "\x00" + strconv.FormatInt(h, 10)
+	}
+	if e, ok := c.get(key); ok {
+		c.hits.Add(1)
+		out.Data = e.data
+		setHeader(opts, e.header)
+		return nil
+	}
+
+	c.misses.Add(1)
+	generation := c.currentGeneration()
+	var header metadata.MD
+	if err := c.next.Invoke(ctx, method, args, reply, append(opts, grpc.Header(&header))...); err != nil {
+		return err
+	}
+	c.put(key, generation, out.Data, header)
+
+	return nil
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *ClientConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return c.next.NewStream(ctx, desc, method, opts...)
+}
+
+// Stats returns a snapshot of the cache activity.
+func (c *ClientConn) Stats() Stats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Evictions:     c.evictions.Load(),
+		Invalidations: c.invalidations.Load(),
+		Entries:       entries,
+	}
+}
+
+// Purge drops all the cached responses.
+func (c *ClientConn) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.entries)
+	c.lru.Init()
+	c.generation++
+	c.invalidations.Add(1)
+}
+
+// WatchHeight polls the latest block height and purges the cache each time it advances, until the context is done.
+// It returns immediately if height-aware invalidation is disabled.
+func (c *ClientConn) WatchHeight(ctx context.Context) {
+	if c.config.HeightPollInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(c.config.HeightPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.refreshHeight(ctx); err != nil {
+			log.Logger.Warn().Err(err).Msg("failed to fetch latest block height")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *ClientConn) refreshHeight(ctx context.Context) error {
+	out := &cmtservice.GetLatestBlockResponse{}
+	if err := c.next.Invoke(ctx, GetLatestBlockMethod, &cmtservice.GetLatestBlockRequest{}, out); err != nil {
+		return err
+	}
+
+	var height int64
+	switch {
+	case out.SdkBlock != nil:
+		height = out.SdkBlock.Header.Height
+	case out.Block != nil:
+		height = out.Block.Header.Height
+	}
+
+	if previous := c.height.Swap(height); previous != 0 && height > previous {
+		c.Purge()
+		log.Logger.Debug().Int64("height", height).Msg("cache invalidated by new block")
+	}
+
+	return nil
+}
+
+func (c *ClientConn) get(key string) (*entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*entry) //nolint:forcetypeassert
+	if c.config.TTL > 0 && !c.now().Before(e.expiresAt) {
+		c.remove(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+
+	return &entry{data: clone(e.data), header: e.header.Copy()}, true
+}
+
+func (c *ClientConn) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// put stores the response fetched in the given generation, unless the cache was purged since.
+func (c *ClientConn) put(key string, generation uint64, data []byte, header metadata.MD) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	e := &entry{key: key, data: clone(data), header: header.Copy(), expiresAt: c.now().Add(c.config.TTL)}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = e
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(e)
+	for c.config.Size > 0 && c.lru.Len() > c.config.Size {
+		c.remove(c.lru.Back())
+		c.evictions.Add(1)
+	}
+}
+
+func (c *ClientConn) remove(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key) //nolint:forcetypeassert
+}
+
+// setHeader fills the header requested through the call options with the one of the cached response.
+func setHeader(opts []grpc.CallOption, header metadata.MD) {
+	for _, opt := range opts {
+		if o, ok := opt.(grpc.HeaderCallOption); ok {
+			*o.HeaderAddr = header.Copy()
+		}
+	}
+}
+
+func clone(data []byte) []byte {
+	return append([]byte(nil), data...)
+}
@@ -0,0 +1,181 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	cmttypes "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestClientConn(t *testing.T) {
+	Convey("Given a cached client connection", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		next := mocks.NewMockClientConnInterface(ctrl)
+		now := time.Now()
+		cc := New(next, Config{TTL: time.Minute, Size: 2})
+		cc.now = func() time.Time { return now }
+
+		query := func(address, data string) string {
+			out := &wasmtypes.QuerySmartContractStateResponse{}
+			err := cc.Invoke(context.Background(), SmartContractStateMethod,
+				&wasmtypes.QuerySmartContractStateRequest{Address: address, QueryData: []byte(data)}, out)
+			So(err, ShouldBeNil)
+			return string(out.Data)
+		}
+
+		Convey("When querying the same contract twice", func() {
+			expectQuery(next, "addr1", `{"q":1}`, `"r1"`).Times(1)
+
+			first := query("addr1", `{"q":1}`)
+			second := query("addr1", `{"q":1}`)
+
+			Convey("Then the node should only be queried once", func() {
+				So(first, ShouldEqual, `"r1"`)
+				So(second, ShouldEqual, `"r1"`)
+				So(cc.Stats(), ShouldResemble, Stats{Hits: 1, Misses: 1, Entries: 1})
+			})
+		})
+
+		Convey("When querying the same contract at different heights", func() {
+			expectQuery(next, "addr1", `{"q":1}`, `"r1"`).Times(2)
+
+			queryAt := func(h int64) metadata.MD {
+				var header metadata.MD
+				out := &wasmtypes.QuerySmartContractStateResponse{}
+				err := cc.Invoke(height.WithHeight(context.Background(), h), SmartContractStateMethod,
+					&wasmtypes.QuerySmartContractStateRequest{Address: "addr1", QueryData: []byte(`{"q":1}`)}, out,
+					grpc.Header(&header))
+				So(err, ShouldBeNil)
+				return header
+			}
+
+			queryAt(10)
+			queryAt(20)
+			header := queryAt(10)
+
+			Convey("Then each height should be cached separately, with the header of the response", func() {
+				So(cc.Stats(), ShouldResemble, Stats{Hits: 1, Misses: 2, Entries: 2})
+				h, ok := height.FromMetadata(header)
+				So(ok, ShouldBeTrue)
+				So(h, ShouldEqual, 10)
+			})
+		})
+
+		Convey("When querying after the TTL expired", func() {
+			expectQuery(next, "addr1", `{"q":1}`, `"r1"`).Times(2)
+
+			query("addr1", `{"q":1}`)
+			now = now.Add(time.Minute)
+			query("addr1", `{"q":1}`)
+
+			Convey("Then the node should be queried again", func() {
+				So(cc.Stats().Misses, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When querying more distinct queries than the cache size", func() {
+			expectQuery(next, "addr1", `{"q":1}`, `"r1"`).Times(2)
+			expectQuery(next, "addr1", `{"q":2}`, `"r2"`).Times(1)
+			expectQuery(next, "addr2", `{"q":1}`, `"r3"`).Times(1)
+
+			query("addr1", `{"q":1}`)
+			query("addr1", `{"q":2}`)
+			query("addr1", `{"q":2}`)
+			query("addr2", `{"q":1}`)
+			query("addr1", `{"q":1}`)
+
+			Convey("Then the least recently used entries should be evicted", func() {
+				So(cc.Stats(), ShouldResemble, Stats{Hits: 1, Misses: 4, Evictions: 2, Entries: 2})
+			})
+		})
+
+		Convey("When calling another method", func() {
+			next.EXPECT().Invoke(gomock.Any(), "/foo", gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+			for range 2 {
+				So(cc.Invoke(context.Background(), "/foo", nil, nil), ShouldBeNil)
+			}
+
+			Convey("Then the call should not be cached", func() {
+				So(cc.Stats(), ShouldResemble, Stats{})
+			})
+		})
+
+		Convey("When the block height advances", func() {
+			expectQuery(next, "addr1", `{"q":1}`, `"r1"`).Times(2)
+			expectHeight(next, 41)
+			expectHeight(next, 42)
+
+			query("addr1", `{"q":1}`)
+			So(cc.refreshHeight(context.Background()), ShouldBeNil)
+			query("addr1", `{"q":1}`)
+			So(cc.refreshHeight(context.Background()), ShouldBeNil)
+			query("addr1", `{"q":1}`)
+
+			Convey("Then the cache should be invalidated", func() {
+				So(cc.Stats(), ShouldResemble, Stats{Hits: 1, Misses: 2, Invalidations: 1, Entries: 1})
+			})
+		})
+
+		Convey("When the cache is purged while a query is in flight", func() {
+			next.EXPECT().
+				Invoke(gomock.Any(), SmartContractStateMethod, gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _, reply any, _ ...grpc.CallOption) error {
+					cc.Purge()
+					reply.(*wasmtypes.QuerySmartContractStateResponse).Data = []byte(`"stale"`)
+					return nil
+				}).Times(1)
+			expectQuery(next, "addr1", `{"q":1}`, `"r1"`).Times(1)
+
+			first := query("addr1", `{"q":1}`)
+			second := query("addr1", `{"q":1}`)
+
+			Convey("Then the response fetched before the purge should not be cached", func() {
+				So(first, ShouldEqual, `"stale"`)
+				So(second, ShouldEqual, `"r1"`)
+				So(cc.Stats(), ShouldResemble, Stats{Misses: 2, Invalidations: 1, Entries: 1})
+			})
+		})
+	})
+}
+
+func expectQuery(next *mocks.MockClientConnInterface, address, query, response string) *gomock.Call {
+	return next.EXPECT().
+		Invoke(gomock.Any(), SmartContractStateMethod,
+			&wasmtypes.QuerySmartContractStateRequest{Address: address, QueryData: []byte(query)},
+			gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _, reply any, opts ...grpc.CallOption) error {
+			reply.(*wasmtypes.QuerySmartContractStateResponse).Data = []byte(response)
+			h, ok := height.FromOutgoingContext(ctx)
+			if !ok {
+				h = 100
+			}
+			for _, opt := range opts {
+				if o, ok := opt.(grpc.HeaderCallOption); ok {
+					*o.HeaderAddr = metadata.Pairs(height.MetadataKey, strconv.FormatInt(h, 10))
+				}
+			}
+			return nil
+		})
+}
+
+func expectHeight(next *mocks.MockClientConnInterface, height int64) {
+	next.EXPECT().
+		Invoke(gomock.Any(), GetLatestBlockMethod, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _, reply any, _ ...grpc.CallOption) error {
+			reply.(*cmtservice.GetLatestBlockResponse).Block = &cmttypes.Block{Header: cmttypes.Header{Height: height}}
+			return nil
+		}).Times(1)
+}
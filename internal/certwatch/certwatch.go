@@ -0,0 +1,139 @@
+package certwatch
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+var ErrNoClientCA = errors.New("no certificate found in client CA file")
+
+// Watcher serves a TLS server certificate, and optionally a client CA pool, loaded from files and reloaded whenever
+// these files change on disk.
+type Watcher struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	current atomic.Pointer[tls.Config]
+}
+
+// New creates a watcher for the given certificate and key files, requiring clients to present a certificate
+// signed by one of the authorities found in clientCAFile unless it is empty.
+// The files are loaded once before returning, any error being reported.
+func New(certFile, keyFile, clientCAFile string) (*Watcher, error) {
+	w := &Watcher{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
+
+// TLSConfig returns a TLS configuration always serving the last successfully loaded certificates.
+func (w *Watcher) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return w.current.Load(), nil
+		},
+	}
+}
+
+// Reload loads the certificates from disk, keeping the previous ones in case of error.
+func (w *Watcher) Reload() error {
+	cert, err := tls.LoadX509KeyPair(w.certFile, w.keyFile)
+	if err != nil {
+		return fmt.Errorf("load server certificate: %w", err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}
+
+	if w.clientCAFile != "" {
+		pem, err := os.ReadFile(w.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("load client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("load client CA %s: %w", w.clientCAFile, ErrNoClientCA)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	w.current.Store(config)
+	return nil
+}
+
+// Watch reloads the certificates each time a file changes in their directories, until the context is done.
+// The parent directories are watched rather than the files themselves so that atomic replacements
+// (e.g. Kubernetes secrets symlink swaps) are detected.
+func (w *Watcher) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for _, dir := range lo.Uniq(lo.Map(w.files(), func(file string, _ int) string { return filepath.Dir(file) })) {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("watch %s: %w", dir, err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) && !event.Has(fsnotify.Rename) {
+				continue
+			}
+			if err := w.Reload(); err != nil {
+				log.Logger.Warn().Err(err).Str("event", event.String()).Msg("failed to reload TLS certificates")
+				continue
+			}
+			log.Logger.Info().Str("event", event.String()).Msg("TLS certificates reloaded")
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Logger.Warn().Err(err).Msg("TLS certificates watcher error")
+		}
+	}
+}
+
+func (w *Watcher) files() []string {
+	return lo.Compact([]string{w.certFile, w.keyFile, w.clientCAFile})
+}
+
+// PeerPrincipal returns the subject of the verified client certificate of the given connection,
+// or an empty string if the client did not authenticate.
+func PeerPrincipal(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+
+	return state.VerifiedChains[0][0].Subject.String()
+}
@@ -0,0 +1,159 @@
+package certwatch
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWatcher(t *testing.T) {
+	Convey("Given certificate files on disk", t, func() {
+		dir := t.TempDir()
+		certFile := filepath.Join(dir, "tls.crt")
+		keyFile := filepath.Join(dir, "tls.key")
+		caFile := filepath.Join(dir, "ca.crt")
+
+		ca, caKey := newCertificate(t, "ca", nil, nil)
+		writePEM(t, caFile, "CERTIFICATE", ca.Raw)
+		writeKeyPair(t, certFile, keyFile, "server-1", ca, caKey)
+
+		Convey("When creating a watcher", func() {
+			w, err := New(certFile, keyFile, caFile)
+			So(err, ShouldBeNil)
+
+			Convey("Then the served configuration should require client certificates", func() {
+				config, err := w.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+				So(err, ShouldBeNil)
+				So(config.ClientAuth, ShouldEqual, tls.RequireAndVerifyClientCert)
+				So(config.ClientCAs, ShouldNotBeNil)
+				So(config.Certificates[0].Leaf.Subject.CommonName, ShouldEqual, "server-1")
+			})
+
+			Convey("And the certificate is replaced on disk while watching", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				var watchErr error
+				watched := make(chan struct{})
+				go func() {
+					watchErr = w.Watch(ctx)
+					close(watched)
+				}()
+				Reset(func() {
+					cancel()
+					<-watched
+				})
+				time.Sleep(100 * time.Millisecond)
+				select {
+				case <-watched:
+					So(watchErr, ShouldBeNil)
+				default:
+				}
+
+				writeKeyPair(t, certFile, keyFile, "server-2", ca, caKey)
+
+				Convey("Then the new certificate should be served", func() {
+					So(func() string {
+						for range 50 {
+							config, _ := w.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+							if cn := config.Certificates[0].Leaf.Subject.CommonName; cn == "server-2" {
+								return cn
+							}
+							time.Sleep(20 * time.Millisecond)
+						}
+						return ""
+					}(), ShouldEqual, "server-2")
+				})
+			})
+		})
+
+		Convey("When creating a watcher with an invalid client CA", func() {
+			So(os.WriteFile(caFile, []byte("garbage"), 0o600), ShouldBeNil)
+			_, err := New(certFile, keyFile, caFile)
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldWrap, ErrNoClientCA)
+			})
+		})
+	})
+}
+
+func TestPeerPrincipal(t *testing.T) {
+	Convey("Given a verified client certificate", t, func() {
+		cert, _ := newCertificate(t, "alice", nil, nil)
+
+		Convey("Then its subject should be the principal", func() {
+			So(PeerPrincipal(&tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}), ShouldEqual, "CN=alice")
+		})
+
+		Convey("Then no principal should be found without verified chain", func() {
+			So(PeerPrincipal(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}), ShouldBeEmpty)
+			So(PeerPrincipal(nil), ShouldBeEmpty)
+		})
+	})
+}
+
+func newCertificate(t *testing.T, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		DNSNames:              []string{"localhost"},
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+func writeKeyPair(t *testing.T, certFile, keyFile, cn string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) {
+	t.Helper()
+
+	cert, key := newCertificate(t, cn, ca, caKey)
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writePEM(t, keyFile, "EC PRIVATE KEY", der)
+	writePEM(t, certFile, "CERTIFICATE", cert.Raw)
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
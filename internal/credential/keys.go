@@ -0,0 +1,178 @@
+package credential
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/cosmos/btcutil/base58"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// verifier checks a signature over a message, the way a given signature algorithm defines it.
+type verifier func(message, signature []byte) error
+
+// publicKey decodes the public key held by the given verification method.
+//
+//nolint:cyclop // one case per key encoding.
+func publicKey(vm *did.VerificationMethod) (*did.Key, error) {
+	switch {
+	case vm.PublicKeyMultibase != "":
+		if key, err := (did.DID{Method: did.MethodKey, ID: vm.PublicKeyMultibase}).Key(); err == nil {
+			return key, nil
+		}
+		if !strings.HasPrefix(vm.PublicKeyMultibase, "z") {
+			return nil, fmt.Errorf("verification method %s: public key is not base58btc multibase encoded", vm.ID)
+		}
+		return rawKey(vm, base58.Decode(vm.PublicKeyMultibase[1:]))
+	case vm.PublicKeyBase58 != "":
+		return rawKey(vm, base58.Decode(vm.PublicKeyBase58))
+	case vm.PublicKeyJwk != nil:
+		return jwkKey(vm)
+	default:
+		return nil, fmt.Errorf("verification method %s holds no supported public key", vm.ID)
+	}
+}
+
+// rawKey returns the key with the given bytes, of the type implied by the type of the verification method.
+func rawKey(vm *did.VerificationMethod, bz []byte) (*did.Key, error) {
+	switch {
+	case strings.HasPrefix(vm.Type, "Ed25519") && len(bz) == ed25519.PublicKeySize:
+		return &did.Key{Type: did.KeyTypeEd25519, PublicKey: bz}, nil
+	case strings.HasPrefix(vm.Type, "EcdsaSecp256k1") && len(bz) == secp.PubKeyBytesLenCompressed:
+		return &did.Key{Type: did.KeyTypeSecp256k1, PublicKey: bz}, nil
+	default:
+		return nil, fmt.Errorf("verification method %s: unsupported %d bytes long %s key", vm.ID, len(bz), vm.Type)
+	}
+}
+
+func jwkKey(vm *did.VerificationMethod) (*did.Key, error) {
+	param := func(name string) ([]byte, error) {
+		s, _ := vm.PublicKeyJwk[name].(string)
+		bz, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil || len(bz) == 0 {
+			return nil, fmt.Errorf("verification method %s: malformed JWK parameter %q", vm.ID, name)
+		}
+		return bz, nil
+	}
+
+	kty, _ := vm.PublicKeyJwk["kty"].(string)
+	crv, _ := vm.PublicKeyJwk["crv"].(string)
+	switch {
+	case kty == "OKP" && crv == "Ed25519":
+		x, err := param("x")
+		if err != nil {
+			return nil, err
+		}
+		return &did.Key{Type: did.KeyTypeEd25519, PublicKey: x}, nil
+	case kty == "EC" && crv == "secp256k1":
+		x, err := param("x")
+		if err != nil {
+			return nil, err
+		}
+		y, err := param("y")
+		if err != nil {
+			return nil, err
+		}
+		var fx, fy secp.FieldVal
+		if fx.SetByteSlice(x) || fy.SetByteSlice(y) {
+			return nil, fmt.Errorf("verification method %s: JWK coordinates overflow", vm.ID)
+		}
+		return &did.Key{Type: did.KeyTypeSecp256k1, PublicKey: secp.NewPublicKey(&fx, &fy).SerializeCompressed()}, nil
+	default:
+		return nil, fmt.Errorf("verification method %s: unsupported JWK %s %s", vm.ID, kty, crv)
+	}
+}
+
+// ed25519Verifier verifies EdDSA signatures made with the given key.
+func ed25519Verifier(key *did.Key) (verifier, error) {
+	if key.Type != did.KeyTypeEd25519 || len(key.PublicKey) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected an Ed25519 key, got a %s one", key.Type)
+	}
+
+	return func(message, signature []byte) error {
+		if !ed25519.Verify(key.PublicKey, message, signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}, nil
+}
+
+// secp256k1Verifier verifies ES256K signatures, that is ECDSA signatures over the SHA-256 digest of the message in
+// their 64 bytes R || S form, made with the given key.
+func secp256k1Verifier(key *did.Key) (verifier, error) {
+	if key.Type != did.KeyTypeSecp256k1 {
+		return nil, fmt.Errorf("expected a Secp256k1 key, got a %s one", key.Type)
+	}
+	pub, err := secp.ParsePubKey(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("malformed Secp256k1 key: %w", err)
+	}
+
+	return func(message, signature []byte) error {
+		sig, err := parseSignature(signature)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256(message)
+		if !sig.Verify(digest[:], pub) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}, nil
+}
+
+// recoveryVerifier verifies ES256K signatures made by the given account, recovering the public key of the signer
+// from the signature, since the account only commits to the hash of its key.
+func recoveryVerifier(vm *did.VerificationMethod) (verifier, error) {
+	parts := strings.Split(vm.BlockchainAccountID, ":")
+	if len(parts) != 3 || parts[0] != "cosmos" {
+		return nil, fmt.Errorf("verification method %s: unsupported blockchain account %q", vm.ID, vm.BlockchainAccountID)
+	}
+	account := parts[2]
+	hrp, _, err := bech32.DecodeAndConvert(account)
+	if err != nil {
+		return nil, fmt.Errorf("verification method %s: %w", vm.ID, err)
+	}
+
+	return func(message, signature []byte) error {
+		if _, err := parseSignature(signature); err != nil {
+			return err
+		}
+		digest := sha256.Sum256(message)
+		for recovery := byte(0); recovery < 2; recovery++ {
+			compact := append([]byte{27 + 4 + recovery}, signature...)
+			pub, _, err := ecdsa.RecoverCompact(compact, digest[:])
+			if err != nil {
+				continue
+			}
+			addr, err := bech32.ConvertAndEncode(hrp, (&secp256k1.PubKey{Key: pub.SerializeCompressed()}).Address())
+			if err == nil && addr == account {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: not signed by %s", ErrInvalidSignature, account)
+	}, nil
+}
+
+func parseSignature(signature []byte) (*ecdsa.Signature, error) {
+	if len(signature) != 64 {
+		return nil, fmt.Errorf("%w: ES256K signature is %d bytes long, expected 64", ErrInvalidSignature, len(signature))
+	}
+
+	var r, s secp.ModNScalar
+	if r.SetByteSlice(signature[:32]) || s.SetByteSlice(signature[32:]) || r.IsZero() || s.IsZero() {
+		return nil, fmt.Errorf("%w: ES256K signature out of range", ErrInvalidSignature)
+	}
+
+	return ecdsa.NewSignature(&r, &s), nil
+}
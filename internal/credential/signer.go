@@ -0,0 +1,112 @@
+package credential
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/jsonld"
+	"github.com/cosmos/btcutil/base58"
+)
+
+// Signer signs credentials with Ed25519Signature2020 proofs, on behalf of the did:key of its Ed25519 key.
+type Signer struct {
+	key    ed25519.PrivateKey
+	did    string
+	loader jsonld.Loader
+	now    func() time.Time
+}
+
+// SignerOption configures a Signer.
+type SignerOption func(*Signer)
+
+// WithSignerLoader sets the loader of the JSON-LD contexts referenced by the credentials to sign.
+func WithSignerLoader(l jsonld.Loader) SignerOption {
+	return func(s *Signer) {
+		s.loader = l
+	}
+}
+
+// WithSignerClock sets the clock giving the creation date of the proofs.
+func WithSignerClock(now func() time.Time) SignerOption {
+	return func(s *Signer) {
+		s.now = now
+	}
+}
+
+// NewSigner creates a signer with the given key.
+func NewSigner(key ed25519.PrivateKey, opts ...SignerOption) *Signer {
+	pub, _ := key.Public().(ed25519.PublicKey)
+	s := &Signer{
+		key:    key,
+		did:    "did:key:z" + base58.Encode(append([]byte{0xed, 0x01}, pub...)),
+		loader: &jsonld.EmbeddedLoader{Fallback: jsonld.NewHTTPLoader()},
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// LoadSigner creates a signer with the Ed25519 key of the given PEM encoded PKCS #8 file, as generated by
+// `openssl genpkey -algorithm ed25519`.
+func LoadSigner(path string, opts ...SignerOption) (*Signer, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(bz)
+	if block == nil {
+		return nil, fmt.Errorf("signing key %s: no PEM block found", path)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("signing key %s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s: %T is not an Ed25519 key", path, key)
+	}
+
+	return NewSigner(edKey, opts...), nil
+}
+
+// DID returns the DID the signer issues credentials as.
+func (s *Signer) DID() string {
+	return s.did
+}
+
+// Sign adds to the given credential, issued by the signer, an Ed25519Signature2020 proof for the assertion purpose.
+func (s *Signer) Sign(ctx context.Context, credential map[string]any) error {
+	if issuer := idOf(credential["issuer"]); issuer != s.did {
+		return fmt.Errorf("%w: cannot sign a credential issued by %q as %s", ErrInvalidCredential, issuer, s.did)
+	}
+
+	contexts := asArray(credential["@context"])
+	if !slices.Contains(contexts, any(jsonld.ContextEd25519V1)) {
+		credential["@context"] = append(slices.Clone(contexts), jsonld.ContextEd25519V1)
+	}
+
+	proof := map[string]any{
+		"type":               SuiteEd25519Signature2020,
+		"created":            s.now().UTC().Format(time.RFC3339),
+		"verificationMethod": s.did + "#" + s.did[len("did:key:"):],
+		"proofPurpose":       PurposeAssertion,
+	}
+	data, err := verifyData(ctx, s.loader, credential, proof)
+	if err != nil {
+		return err
+	}
+	proof["proofValue"] = "z" + base58.Encode(ed25519.Sign(s.key, data))
+	credential["proof"] = proof
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package credential
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/cosmos/btcutil/base58"
+)
+
+const (
+	SuiteEd25519Signature2020        = "Ed25519Signature2020"
+	SuiteEd25519Signature2018        = "Ed25519Signature2018"
+	SuiteEcdsaSecp256k1Signature2019 = "EcdsaSecp256k1Signature2019"
+)
+
+var ErrUnsupportedSuite = errors.New("unsupported proof type")
+
+// suite returns the function verifying the proofs of the given type made with the given verification method.
+func suite(proofType string, vm *did.VerificationMethod) (func([]byte, map[string]any) error, error) {
+	var verify verifier
+	var err error
+	switch proofType {
+	case SuiteEd25519Signature2020, SuiteEd25519Signature2018:
+		var key *did.Key
+		if key, err = publicKey(vm); err == nil {
+			verify, err = ed25519Verifier(key)
+		}
+	case SuiteEcdsaSecp256k1Signature2019:
+		if vm.BlockchainAccountID != "" && vm.PublicKeyMultibase == "" && vm.PublicKeyJwk == nil {
+			verify, err = recoveryVerifier(vm)
+			break
+		}
+		var key *did.Key
+		if key, err = publicKey(vm); err == nil {
+			verify, err = secp256k1Verifier(key)
+		}
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedSuite, proofType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("verification method %s: %w", vm.ID, err)
+	}
+
+	if proofType == SuiteEd25519Signature2020 {
+		return func(data []byte, proof map[string]any) error {
+			return verifyProofValue(verify, data, proof)
+		}, nil
+	}
+
+	alg := "ES256K"
+	if proofType == SuiteEd25519Signature2018 {
+		alg = "EdDSA"
+	}
+	return func(data []byte, proof map[string]any) error {
+		return verifyDetachedJWS(verify, alg, data, proof)
+	}, nil
+}
+
+// verifyProofValue verifies a signature carried as a base58btc multibase proofValue.
+func verifyProofValue(verify verifier, data []byte, proof map[string]any) error {
+	value, _ := proof["proofValue"].(string)
+	if !strings.HasPrefix(value, "z") {
+		return fmt.Errorf("%w: proofValue is not base58btc multibase encoded", ErrInvalidSignature)
+	}
+
+	return verify(data, base58.Decode(value[1:]))
+}
+
+// verifyDetachedJWS verifies a signature carried as a JWS with a detached, unencoded payload, as defined by RFC 7797.
+func verifyDetachedJWS(verify verifier, alg string, data []byte, proof map[string]any) error {
+	jws, _ := proof["jws"].(string)
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 || parts[1] != "" {
+		return fmt.Errorf("%w: jws is not a detached JWS", ErrInvalidSignature)
+	}
+
+	bz, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return fmt.Errorf("%w: malformed JWS header", ErrInvalidSignature)
+	}
+	var header struct {
+		Alg  string   `json:"alg"`
+		B64  *bool    `json:"b64"`
+		Crit []string `json:"crit"`
+	}
+	if err := json.Unmarshal(bz, &header); err != nil {
+		return fmt.Errorf("%w: malformed JWS header", ErrInvalidSignature)
+	}
+	if header.Alg != alg {
+		return fmt.Errorf("%w: JWS algorithm is %q, expected %q", ErrInvalidSignature, header.Alg, alg)
+	}
+	if header.B64 == nil || *header.B64 || !slices.Contains(header.Crit, "b64") {
+		return fmt.Errorf("%w: JWS payload must be unencoded", ErrInvalidSignature)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: malformed JWS signature", ErrInvalidSignature)
+	}
+
+	return verify(append([]byte(parts[0]+"."), data...), signature)
+}
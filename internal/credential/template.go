@@ -0,0 +1,295 @@
+package credential
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/axone-protocol/axone-mcp/internal/jsonld"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUnknownTemplate = errors.New("unknown credential template")
+	ErrInvalidClaims   = errors.New("invalid claims")
+)
+
+// ClaimKind is the kind of value a claim holds.
+type ClaimKind string
+
+const (
+	// KindText is a non-empty string.
+	KindText ClaimKind = "text"
+	// KindTexts is a list of non-empty strings, possibly given as a single one.
+	KindTexts ClaimKind = "texts"
+	// KindIRI is an IRI, possibly given relative to the namespace of the claim.
+	KindIRI ClaimKind = "iri"
+	// KindContract is the address of a smart contract, turned into an IRI by the format of the claim.
+	KindContract ClaimKind = "contract"
+)
+
+// Claim is a claim about the subject of a credential, as shaped by the ontology.
+type Claim struct {
+	Name        string    `json:"name"`
+	Kind        ClaimKind `json:"kind"`
+	Required    bool      `json:"required,omitempty"`
+	Description string    `json:"description"`
+	// Namespace is the IRI prefix of the values of an IRI claim, allowing to give them by their local name.
+	Namespace string `json:"namespace,omitempty"`
+	// Format is the format turning the value of a contract claim into an IRI.
+	Format string `json:"-"`
+	// Path is the location of the claim in the credential subject, defaulting to its name.
+	Path []string `json:"-"`
+}
+
+// Template describes a type of credential of the Axone ontology.
+type Template struct {
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Context     string  `json:"context"`
+	Description string  `json:"description"`
+	Claims      []Claim `json:"claims"`
+	// Fixed are the values the credential subject always holds, merged with the claims.
+	Fixed map[string]any `json:"-"`
+}
+
+var (
+	thesaurus = cognitarium.W3IDPrefix + "/thesaurus/"
+	schema    = cognitarium.W3IDPrefix + "/schema/credential/"
+)
+
+var (
+	claimTitle       = Claim{Name: "hasTitle", Kind: KindText, Required: true, Description: "The title"}
+	claimDescription = Claim{Name: "hasDescription", Kind: KindText, Description: "A free text description"}
+	claimTag         = Claim{Name: "hasTag", Kind: KindTexts, Description: "Keywords"}
+	claimTopic       = Claim{
+		Name: "hasTopic", Kind: KindIRI, Namespace: thesaurus + "topic/", Description: "The topic, from the thesaurus",
+	}
+)
+
+// Templates are the templates of the credentials of the Axone ontology, by name.
+var Templates = map[string]*Template{
+	"dataset-description": {
+		Name:        "dataset-description",
+		Type:        "DatasetDescriptionCredential",
+		Context:     schema + "dataset/description/",
+		Description: "Describes a dataset referenced in the dataverse",
+		Claims: []Claim{
+			claimTitle,
+			claimDescription,
+			{Name: "hasFormat", Kind: KindIRI, Namespace: thesaurus + "media-type/", Description: "The media type"},
+			claimTag,
+			claimTopic,
+			{Name: "hasPublisher", Kind: KindText, Description: "The publisher"},
+			{Name: "hasCreator", Kind: KindText, Description: "The creator"},
+			{Name: "hasLicense", Kind: KindIRI, Namespace: thesaurus + "license/", Description: "The license"},
+		},
+	},
+	"governance-text": {
+		Name:        "governance-text",
+		Type:        "GovernanceTextCredential",
+		Context:     schema + "governance/text/",
+		Description: "Attaches a governance, written as a law-stone contract, to a resource",
+		Claims: []Claim{{
+			Name:        "fromGovernance",
+			Kind:        KindContract,
+			Required:    true,
+			Description: "The address of the law-stone contract holding the governance",
+			Format:      "cosmwasm:law-stone:%s",
+			Path:        []string{"isGovernedBy", "fromGovernance"},
+		}},
+		Fixed: map[string]any{"isGovernedBy": map[string]any{"type": "GovernanceText"}},
+	},
+	"digital-service-description": {
+		Name:        "digital-service-description",
+		Type:        "DigitalServiceDescriptionCredential",
+		Context:     schema + "digital-service/description/",
+		Description: "Describes a digital service referenced in the dataverse",
+		Claims: []Claim{
+			claimTitle,
+			claimDescription,
+			{
+				Name: "hasCategory", Kind: KindIRI, Namespace: thesaurus + "digital-service-category/",
+				Description: "The category of service",
+			},
+			claimTag,
+			{Name: "hasWebPage", Kind: KindIRI, Description: "The web page of the service"},
+		},
+	},
+	"zone-description": {
+		Name:        "zone-description",
+		Type:        "ZoneDescriptionCredential",
+		Context:     schema + "zone/description/",
+		Description: "Describes a zone, a space of collaboration sharing resources under a common governance",
+		Claims:      []Claim{claimTitle, claimDescription, claimTopic, claimTag},
+	},
+}
+
+// Request holds what a credential is built from.
+type Request struct {
+	Template string
+	// ID identifies the credential, a random URN if empty.
+	ID      string
+	Issuer  string
+	Subject string
+	Claims  map[string]any
+	// IssuanceDate is the date the credential is valid from.
+	IssuanceDate time.Time
+	// ExpirationDate is the date the credential expires on, if not zero.
+	ExpirationDate time.Time
+}
+
+// Build builds the unsigned credential described by the request, checking its claims against the shape of the
+// template.
+func Build(req Request) (map[string]any, error) {
+	tmpl, ok := Templates[req.Template]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, expected one of %s",
+			ErrUnknownTemplate, req.Template, strings.Join(slices.Sorted(maps.Keys(Templates)), ", "))
+	}
+
+	var violations []string
+	for _, id := range []struct{ name, value string }{{"issuer", req.Issuer}, {"subject", req.Subject}} {
+		if err := did.Validate(id.value); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s", id.name, err))
+		}
+	}
+
+	subject, err := tmpl.subject(req.Claims)
+	if err != nil {
+		violations = append(violations, err.Error())
+	}
+	if len(violations) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidClaims, strings.Join(violations, "; "))
+	}
+	subject["id"] = req.Subject
+
+	id := req.ID
+	if id == "" {
+		id = "urn:uuid:" + uuid.NewString()
+	}
+
+	credential := map[string]any{
+		"@context":          []any{jsonld.ContextCredentialsV1, tmpl.Context},
+		"id":                id,
+		"type":              []any{TypeCredential, tmpl.Type},
+		"issuer":            req.Issuer,
+		"issuanceDate":      req.IssuanceDate.UTC().Format(time.RFC3339),
+		"credentialSubject": subject,
+	}
+	if !req.ExpirationDate.IsZero() {
+		credential["expirationDate"] = req.ExpirationDate.UTC().Format(time.RFC3339)
+	}
+
+	return credential, nil
+}
+
+// subject returns the credential subject holding the given claims, reporting all the claims not matching the shape
+// of the template.
+func (t *Template) subject(claims map[string]any) (map[string]any, error) {
+	subject := deepCopy(t.Fixed)
+
+	var violations []string
+	for _, name := range slices.Sorted(maps.Keys(claims)) {
+		if !slices.ContainsFunc(t.Claims, func(c Claim) bool { return c.Name == name }) {
+			violations = append(violations, fmt.Sprintf("%s: not a claim of %s credentials", name, t.Type))
+		}
+	}
+
+	for _, c := range t.Claims {
+		raw, ok := claims[c.Name]
+		if !ok || raw == nil {
+			if c.Required {
+				violations = append(violations, fmt.Sprintf("%s: required", c.Name))
+			}
+			continue
+		}
+
+		value, err := c.value(raw)
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s", c.Name, err))
+			continue
+		}
+
+		path := c.Path
+		if len(path) == 0 {
+			path = []string{c.Name}
+		}
+		node := subject
+		for _, key := range path[:len(path)-1] {
+			child, ok := node[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				node[key] = child
+			}
+			node = child
+		}
+		node[path[len(path)-1]] = value
+	}
+
+	if len(violations) > 0 {
+		return nil, errors.New(strings.Join(violations, "; "))
+	}
+
+	return subject, nil
+}
+
+// value checks the given value matches the kind of the claim and returns it in its credential form.
+func (c *Claim) value(raw any) (any, error) {
+	switch c.Kind {
+	case KindText:
+		s, ok := raw.(string)
+		if !ok || strings.TrimSpace(s) == "" {
+			return nil, errors.New("expected a non-empty string")
+		}
+		return s, nil
+	case KindTexts:
+		var texts []any
+		for _, item := range asArray(raw) {
+			s, ok := item.(string)
+			if !ok || strings.TrimSpace(s) == "" {
+				return nil, errors.New("expected non-empty strings")
+			}
+			texts = append(texts, s)
+		}
+		return texts, nil
+	case KindIRI:
+		s, ok := raw.(string)
+		if !ok || s == "" {
+			return nil, errors.New("expected an IRI")
+		}
+		if c.Namespace != "" && !strings.Contains(s, ":") {
+			s = c.Namespace + s
+		}
+		if !strings.Contains(s, ":") || strings.ContainsAny(s, " <>\"{}|\\^`") {
+			return nil, fmt.Errorf("%q is not an absolute IRI", s)
+		}
+		return s, nil
+	case KindContract:
+		s, _ := raw.(string)
+		if err := address.ValidateContract(s); err != nil {
+			return nil, err
+		}
+		return fmt.Sprintf(c.Format, s), nil
+	default:
+		return nil, fmt.Errorf("unsupported claim kind %q", c.Kind)
+	}
+}
+
+func deepCopy(m map[string]any) map[string]any {
+	c := make(map[string]any, len(m))
+	for k, v := range m {
+		if child, ok := v.(map[string]any); ok {
+			v = deepCopy(child)
+		}
+		c[k] = v
+	}
+
+	return c
+}
@@ -0,0 +1,440 @@
+package credential
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/axone-protocol/axone-mcp/internal/jsonld"
+)
+
+const (
+	TypeCredential   = "VerifiableCredential"
+	TypePresentation = "VerifiablePresentation"
+)
+
+const (
+	PurposeAssertion      = "assertionMethod"
+	PurposeAuthentication = "authentication"
+)
+
+// Names of the checks reported by a verification.
+const (
+	CheckIssuer      = "issuer"
+	CheckHolder      = "holder"
+	CheckIssuance    = "issuanceDate"
+	CheckExpiration  = "expirationDate"
+	CheckProof       = "proof"
+	CheckCredentials = "credentials"
+)
+
+// MaxSize is the maximum size in bytes of a credential or presentation to verify.
+const MaxSize = 256 << 10
+
+var ErrInvalidCredential = errors.New("invalid verifiable credential")
+
+// Report is the detailed outcome of the verification of a credential or presentation.
+type Report struct {
+	Verified bool   `json:"verified"`
+	Type     string `json:"type"`
+	ID       string `json:"id,omitempty"`
+	Issuer   string `json:"issuer,omitempty"`
+	Holder   string `json:"holder,omitempty"`
+	// Checks are the checks performed, failed or not.
+	Checks []Check        `json:"checks"`
+	Proofs []*ProofReport `json:"proofs,omitempty"`
+	// Credentials are the reports of the credentials embedded in a presentation.
+	Credentials []*Report `json:"credentials,omitempty"`
+}
+
+// Check is the outcome of a single check.
+type Check struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Message string `json:"message,omitempty"`
+}
+
+// ProofReport is the outcome of the verification of a single proof.
+type ProofReport struct {
+	Verified           bool   `json:"verified"`
+	Type               string `json:"type"`
+	VerificationMethod string `json:"verificationMethod,omitempty"`
+	ProofPurpose       string `json:"proofPurpose,omitempty"`
+	Created            string `json:"created,omitempty"`
+	Error              string `json:"error,omitempty"`
+}
+
+func (r *Report) check(name string, err error) {
+	c := Check{Name: name, Passed: err == nil}
+	if err != nil {
+		c.Message = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+func (r *Report) passed() bool {
+	return !slices.ContainsFunc(r.Checks, func(c Check) bool { return !c.Passed })
+}
+
+// Verifier verifies Verifiable Credentials and Presentations secured by Linked Data Proofs.
+type Verifier struct {
+	resolver *did.Resolver
+	loader   jsonld.Loader
+	now      func() time.Time
+}
+
+// Option configures a Verifier.
+type Option func(*Verifier)
+
+// WithLoader sets the loader of the JSON-LD contexts referenced by the credentials.
+func WithLoader(l jsonld.Loader) Option {
+	return func(v *Verifier) {
+		v.loader = l
+	}
+}
+
+// WithClock sets the clock the validity periods are checked against.
+func WithClock(now func() time.Time) Option {
+	return func(v *Verifier) {
+		v.now = now
+	}
+}
+
+// NewVerifier creates a verifier resolving the keys of the issuers and holders with the given resolver. Unless
+// configured otherwise, the well-known contexts are served from the binary and the others fetched over HTTPS.
+func NewVerifier(resolver *did.Resolver, opts ...Option) *Verifier {
+	v := &Verifier{
+		resolver: resolver,
+		loader:   &jsonld.EmbeddedLoader{Fallback: jsonld.NewHTTPLoader()},
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Verify verifies the given JSON-LD credential or presentation. An error is only returned when the document is not a
+// credential nor a presentation; verification failures are detailed in the report.
+func (v *Verifier) Verify(ctx context.Context, document []byte) (*Report, error) {
+	if len(document) > MaxSize {
+		return nil, fmt.Errorf("%w: document exceeds %d bytes", ErrInvalidCredential, MaxSize)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+	}
+
+	types := stringsOf(doc["type"])
+	switch {
+	case slices.Contains(types, TypePresentation):
+		return v.verifyPresentation(ctx, doc)
+	case slices.Contains(types, TypeCredential):
+		return v.verifyCredential(ctx, doc), nil
+	default:
+		return nil, fmt.Errorf("%w: type is neither %s nor %s", ErrInvalidCredential, TypeCredential, TypePresentation)
+	}
+}
+
+func (v *Verifier) verifyCredential(ctx context.Context, doc map[string]any) *Report {
+	r := &Report{Type: TypeCredential, ID: idOf(doc["id"]), Issuer: idOf(doc["issuer"])}
+
+	if r.Issuer == "" {
+		r.check(CheckIssuer, errors.New("the credential has no issuer"))
+	} else {
+		r.check(CheckIssuer, nil)
+	}
+	r.check(CheckIssuance, v.checkIssuance(doc))
+	if _, ok := firstOf(doc, "expirationDate", "validUntil"); ok {
+		r.check(CheckExpiration, v.checkExpiration(doc))
+	}
+
+	r.Proofs = v.verifyProofs(ctx, r, doc, PurposeAssertion, r.Issuer)
+	r.Verified = r.passed()
+
+	return r
+}
+
+func (v *Verifier) verifyPresentation(ctx context.Context, doc map[string]any) (*Report, error) {
+	r := &Report{Type: TypePresentation, ID: idOf(doc["id"]), Holder: idOf(doc["holder"])}
+	if r.Holder == "" {
+		r.check(CheckHolder, errors.New("the presentation has no holder"))
+	} else {
+		r.check(CheckHolder, nil)
+	}
+
+	var failed []string
+	for i, item := range asArray(doc["verifiableCredential"]) {
+		credential, ok := item.(map[string]any)
+		if !ok || !slices.Contains(stringsOf(credential["type"]), TypeCredential) {
+			return nil, fmt.Errorf("%w: credential #%d of the presentation is not an embedded credential",
+				ErrInvalidCredential, i)
+		}
+		report := v.verifyCredential(ctx, credential)
+		if !report.Verified {
+			failed = append(failed, fmt.Sprintf("#%d", i))
+		}
+		r.Credentials = append(r.Credentials, report)
+	}
+	if len(failed) > 0 {
+		r.check(CheckCredentials, fmt.Errorf("credentials %s failed verification", strings.Join(failed, ", ")))
+	} else {
+		r.check(CheckCredentials, nil)
+	}
+
+	r.Proofs = v.verifyProofs(ctx, r, doc, PurposeAuthentication, r.Holder)
+	r.Verified = r.passed()
+
+	return r, nil
+}
+
+func (v *Verifier) checkIssuance(doc map[string]any) error {
+	value, ok := firstOf(doc, "issuanceDate", "validFrom")
+	if !ok {
+		return errors.New("the credential has no issuance date")
+	}
+	issued, err := parseDate(value)
+	if err != nil {
+		return err
+	}
+	if now := v.now(); issued.After(now) {
+		return fmt.Errorf("the credential is not valid before %s", issued.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+func (v *Verifier) checkExpiration(doc map[string]any) error {
+	value, _ := firstOf(doc, "expirationDate", "validUntil")
+	expires, err := parseDate(value)
+	if err != nil {
+		return err
+	}
+	if now := v.now(); !now.Before(expires) {
+		return fmt.Errorf("the credential expired on %s", expires.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// verifyProofs verifies the proofs of the given document, made for the given purpose by the given controller, if
+// any, recording the outcome in the report. A document without proof, or with an empty list of proofs, fails.
+func (v *Verifier) verifyProofs(
+	ctx context.Context, r *Report, doc map[string]any, purpose, controller string,
+) []*ProofReport {
+	proofs := asArray(doc["proof"])
+	if len(proofs) == 0 {
+		r.check(CheckProof, errors.New("the document has no proof"))
+		return nil
+	}
+
+	reports := make([]*ProofReport, 0, len(proofs))
+	var err error
+	for i, item := range proofs {
+		proof, ok := item.(map[string]any)
+		if !ok {
+			err = fmt.Errorf("proof #%d is not an object", i)
+			continue
+		}
+		report := v.verifyProof(ctx, doc, proof, purpose, controller)
+		if !report.Verified {
+			err = fmt.Errorf("proof #%d failed verification: %s", i, report.Error)
+		}
+		reports = append(reports, report)
+	}
+	r.check(CheckProof, err)
+
+	return reports
+}
+
+func (v *Verifier) verifyProof(
+	ctx context.Context, doc, proof map[string]any, purpose, controller string,
+) *ProofReport {
+	report := &ProofReport{
+		Type:               idOf(proof["type"]),
+		VerificationMethod: idOf(proof["verificationMethod"]),
+		ProofPurpose:       idOf(proof["proofPurpose"]),
+		Created:            idOf(proof["created"]),
+	}
+
+	if err := v.checkProof(ctx, doc, proof, report, purpose, controller); err != nil {
+		report.Error = err.Error()
+		return report
+	}
+	report.Verified = true
+
+	return report
+}
+
+//nolint:cyclop // one check per step of the verification.
+func (v *Verifier) checkProof(
+	ctx context.Context, doc, proof map[string]any, report *ProofReport, purpose, controller string,
+) error {
+	if report.ProofPurpose != purpose {
+		return fmt.Errorf("proof purpose is %q, expected %q", report.ProofPurpose, purpose)
+	}
+	if report.Created != "" {
+		created, err := parseDate(report.Created)
+		if err != nil {
+			return err
+		}
+		if created.After(v.now()) {
+			return fmt.Errorf("proof is created in the future, on %s", created.Format(time.RFC3339))
+		}
+	}
+	if expires, ok := proof["expires"]; ok {
+		t, err := parseDate(expires)
+		if err != nil {
+			return err
+		}
+		if !v.now().Before(t) {
+			return fmt.Errorf("proof expired on %s", t.Format(time.RFC3339))
+		}
+	}
+
+	verify, err := v.verificationMethod(ctx, report, purpose, controller)
+	if err != nil {
+		return err
+	}
+
+	data, err := verifyData(ctx, v.loader, doc, proof)
+	if err != nil {
+		return err
+	}
+
+	return verify(data, proof)
+}
+
+// verificationMethod resolves the verification method of the proof and returns the function verifying the proof
+// against the given data, according to the proof type.
+func (v *Verifier) verificationMethod(
+	ctx context.Context, report *ProofReport, purpose, controller string,
+) (func([]byte, map[string]any) error, error) {
+	owner, _, _ := strings.Cut(report.VerificationMethod, "#")
+	if owner == "" {
+		return nil, errors.New("proof has no verification method")
+	}
+	if controller != "" && owner != controller {
+		return nil, fmt.Errorf("verification method %s is not controlled by %s", report.VerificationMethod, controller)
+	}
+
+	doc, err := v.resolver.Resolve(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	vm, ok := doc.VerificationMethodByID(report.VerificationMethod)
+	if !ok {
+		return nil, fmt.Errorf("verification method %s not found in the DID document", report.VerificationMethod)
+	}
+	relationship := doc.AssertionMethod
+	if purpose == PurposeAuthentication {
+		relationship = doc.Authentication
+	}
+	if !doc.Authorizes(relationship, vm.ID) {
+		return nil, fmt.Errorf("verification method %s is not authorized for %s", vm.ID, purpose)
+	}
+
+	return suite(report.Type, vm)
+}
+
+// verifyData returns the data the proof signs: the SHA-256 digest of the canonical proof options, followed by the
+// one of the canonical document, as defined by Linked Data Proofs.
+func verifyData(ctx context.Context, loader jsonld.Loader, doc, proof map[string]any) ([]byte, error) {
+	unsigned := maps.Clone(doc)
+	delete(unsigned, "proof")
+
+	options := maps.Clone(proof)
+	for _, k := range []string{"proofValue", "jws", "signatureValue"} {
+		delete(options, k)
+	}
+	options["@context"] = doc["@context"]
+
+	optionsHash, err := canonicalHash(ctx, loader, options)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize proof: %w", err)
+	}
+	docHash, err := canonicalHash(ctx, loader, unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("canonicalize document: %w", err)
+	}
+
+	return append(optionsHash, docHash...), nil
+}
+
+func canonicalHash(ctx context.Context, loader jsonld.Loader, doc map[string]any) ([]byte, error) {
+	dataset, err := jsonld.ToRDF(ctx, doc, loader)
+	if err != nil {
+		return nil, err
+	}
+	nquads, err := jsonld.Canonicalize(dataset)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(nquads))
+
+	return sum[:], nil
+}
+
+func parseDate(value any) (time.Time, error) {
+	s, _ := value.(string)
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed date %v: expected an RFC 3339 date time", value)
+	}
+
+	return t, nil
+}
+
+func firstOf(doc map[string]any, keys ...string) (any, bool) {
+	for _, k := range keys {
+		if v, ok := doc[k]; ok {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// idOf returns the given value if it is a string, or its id if it is an object.
+func idOf(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]any:
+		id, _ := v["id"].(string)
+		return id
+	default:
+		return ""
+	}
+}
+
+func stringsOf(value any) []string {
+	var s []string
+	for _, v := range asArray(value) {
+		if str, ok := v.(string); ok {
+			s = append(s, str)
+		}
+	}
+
+	return s
+}
+
+func asArray(value any) []any {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []any:
+		return v
+	default:
+		return []any{v}
+	}
+}
@@ -0,0 +1,304 @@
+package credential
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/cosmos/btcutil/base58"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var now = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestVerifier() *Verifier {
+	return NewVerifier(did.NewResolver(), WithClock(func() time.Time { return now }))
+}
+
+func unsignedCredential(issuer string) map[string]any {
+	return map[string]any{
+		"@context": []any{
+			"https://www.w3.org/2018/credentials/v1",
+			"https://w3id.org/security/suites/ed25519-2020/v1",
+			map[string]any{"name": "https://schema.org/name"},
+		},
+		"id":                "urn:uuid:8c2f7a3e-5b1d-4f0e-9a6c-2d4b8e1f3a57",
+		"type":              []any{"VerifiableCredential"},
+		"issuer":            issuer,
+		"issuanceDate":      "2025-01-01T00:00:00Z",
+		"expirationDate":    "2026-01-01T00:00:00Z",
+		"credentialSubject": map[string]any{"id": "did:example:alice", "name": "Alice"},
+	}
+}
+
+// signer signs the verify data of a proof, filling in the signature of the proof.
+type signer func(data []byte, proof map[string]any)
+
+func sign(doc map[string]any, proof map[string]any, s signer) map[string]any {
+	data, err := verifyData(context.Background(), newTestVerifier().loader, doc, proof)
+	So(err, ShouldBeNil)
+	s(data, proof)
+	doc["proof"] = proof
+
+	return doc
+}
+
+func ed25519Signer(priv ed25519.PrivateKey) signer {
+	return func(data []byte, proof map[string]any) {
+		proof["proofValue"] = "z" + base58.Encode(ed25519.Sign(priv, data))
+	}
+}
+
+func es256kSigner(priv *secp.PrivateKey) signer {
+	return func(data []byte, proof map[string]any) {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256K","b64":false,"crit":["b64"]}`))
+		digest := sha256.Sum256(append([]byte(header+"."), data...))
+		sig := ecdsa.SignCompact(priv, digest[:], true)[1:]
+		proof["jws"] = header + ".." + base64.RawURLEncoding.EncodeToString(sig)
+	}
+}
+
+func ed25519DID() (string, ed25519.PrivateKey) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	return "did:key:z" + base58.Encode(append([]byte{0xed, 0x01}, pub...)), priv
+}
+
+func secp256k1DID() (string, *secp.PrivateKey) {
+	priv, _ := secp.GeneratePrivateKey()
+	return "did:key:z" + base58.Encode(append([]byte{0xe7, 0x01}, priv.PubKey().SerializeCompressed()...)), priv
+}
+
+func axoneDID() (string, *secp.PrivateKey) {
+	priv, _ := secp.GeneratePrivateKey()
+	addr, _ := bech32.ConvertAndEncode("axone", (&secp256k1.PubKey{Key: priv.PubKey().SerializeCompressed()}).Address())
+	return "did:axone:" + addr, priv
+}
+
+func ed25519Proof(issuer string) map[string]any {
+	return map[string]any{
+		"type":               SuiteEd25519Signature2020,
+		"created":            "2025-01-01T00:00:00Z",
+		"verificationMethod": issuer + "#" + issuer[len("did:key:"):],
+		"proofPurpose":       PurposeAssertion,
+	}
+}
+
+func verify(doc map[string]any) *Report {
+	bz, err := json.Marshal(doc)
+	So(err, ShouldBeNil)
+	report, err := newTestVerifier().Verify(context.Background(), bz)
+	So(err, ShouldBeNil)
+
+	return report
+}
+
+func failedCheck(r *Report) string {
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return c.Name + ": " + c.Message
+		}
+	}
+
+	return ""
+}
+
+func TestVerifyCredential(t *testing.T) {
+	Convey("Given a credential signed with Ed25519Signature2020", t, func() {
+		issuer, priv := ed25519DID()
+		doc := sign(unsignedCredential(issuer), ed25519Proof(issuer), ed25519Signer(priv))
+
+		Convey("When verifying it", func() {
+			report := verify(doc)
+
+			Convey("Then it should be verified", func() {
+				So(failedCheck(report), ShouldBeEmpty)
+				So(report.Verified, ShouldBeTrue)
+				So(report.Issuer, ShouldEqual, issuer)
+				So(report.Proofs, ShouldHaveLength, 1)
+				So(report.Proofs[0].Verified, ShouldBeTrue)
+				So(report.Checks, ShouldHaveLength, 4)
+			})
+		})
+
+		Convey("When its subject is tampered with", func() {
+			doc["credentialSubject"].(map[string]any)["name"] = "Mallory" //nolint:forcetypeassert
+			report := verify(doc)
+
+			Convey("Then the proof should not be verified", func() {
+				So(report.Verified, ShouldBeFalse)
+				So(report.Proofs[0].Verified, ShouldBeFalse)
+				So(report.Proofs[0].Error, ShouldEqual, "invalid signature")
+			})
+		})
+
+		Convey("When verifying it after its expiration", func() {
+			bz, _ := json.Marshal(doc)
+			later := NewVerifier(did.NewResolver(), WithClock(func() time.Time { return now.AddDate(1, 0, 0) }))
+			report, err := later.Verify(context.Background(), bz)
+
+			Convey("Then only the expiration check should fail", func() {
+				So(err, ShouldBeNil)
+				So(report.Verified, ShouldBeFalse)
+				So(failedCheck(report), ShouldEqual, "expirationDate: the credential expired on 2026-01-01T00:00:00Z")
+			})
+		})
+	})
+
+	Convey("Given a credential signed by another key than the issuer's", t, func() {
+		issuer, _ := ed25519DID()
+		other, priv := ed25519DID()
+		doc := sign(unsignedCredential(issuer), ed25519Proof(other), ed25519Signer(priv))
+
+		Convey("When verifying it", func() {
+			report := verify(doc)
+
+			Convey("Then the proof should be rejected", func() {
+				So(report.Verified, ShouldBeFalse)
+				So(report.Proofs[0].Error, ShouldContainSubstring, "is not controlled by "+issuer)
+			})
+		})
+	})
+
+	Convey("Given a credential signed with EcdsaSecp256k1Signature2019", t, func() {
+		cases := []struct {
+			name   string
+			newDID func() (string, *secp.PrivateKey)
+			vm     func(string) string
+		}{
+			{"a Secp256k1 did:key", secp256k1DID, func(id string) string { return id + "#" + id[len("did:key:"):] }},
+			{"a did:axone", axoneDID, func(id string) string { return id + "#account" }},
+		}
+
+		for _, tc := range cases {
+			Convey("When verifying it with "+tc.name, func() {
+				issuer, priv := tc.newDID()
+				doc := unsignedCredential(issuer)
+				doc["@context"] = "https://www.w3.org/2018/credentials/v1"
+				delete(doc, "credentialSubject")
+				doc["credentialSubject"] = map[string]any{"id": "did:example:alice"}
+				doc = sign(doc, map[string]any{
+					"type":               SuiteEcdsaSecp256k1Signature2019,
+					"created":            "2025-01-01T00:00:00Z",
+					"verificationMethod": tc.vm(issuer),
+					"proofPurpose":       PurposeAssertion,
+				}, es256kSigner(priv))
+				report := verify(doc)
+
+				Convey("Then it should be verified", func() {
+					So(failedCheck(report), ShouldBeEmpty)
+					So(report.Verified, ShouldBeTrue)
+				})
+			})
+		}
+	})
+
+	Convey("Given a credential not yet valid and without proof", t, func() {
+		issuer, _ := ed25519DID()
+		doc := unsignedCredential(issuer)
+		doc["issuanceDate"] = "2030-01-01T00:00:00Z"
+
+		Convey("When verifying it", func() {
+			report := verify(doc)
+
+			Convey("Then both failures should be reported", func() {
+				So(report.Verified, ShouldBeFalse)
+				So(report.Checks, ShouldContain,
+					Check{Name: CheckIssuance, Message: "the credential is not valid before 2030-01-01T00:00:00Z"})
+				So(report.Checks, ShouldContain, Check{Name: CheckProof, Message: "the document has no proof"})
+			})
+		})
+	})
+
+	Convey("Given a credential with an empty list of proofs", t, func() {
+		issuer, _ := ed25519DID()
+		doc := unsignedCredential(issuer)
+		doc["proof"] = []any{}
+
+		Convey("When verifying it", func() {
+			report := verify(doc)
+
+			Convey("Then it should not be verified", func() {
+				So(report.Verified, ShouldBeFalse)
+				So(report.Checks, ShouldContain, Check{Name: CheckProof, Message: "the document has no proof"})
+			})
+		})
+	})
+
+	Convey("Given documents that are not credentials", t, func() {
+		for _, doc := range []string{`[]`, `{"type": "Foo"}`} {
+			Convey("When verifying "+doc, func() {
+				_, err := newTestVerifier().Verify(context.Background(), []byte(doc))
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldWrap, ErrInvalidCredential)
+				})
+			})
+		}
+	})
+}
+
+func TestVerifyPresentation(t *testing.T) {
+	Convey("Given a presentation of a credential, signed by its holder", t, func() {
+		issuer, issuerKey := ed25519DID()
+		holder, holderKey := ed25519DID()
+		credential := sign(unsignedCredential(issuer), ed25519Proof(issuer), ed25519Signer(issuerKey))
+
+		presentation := map[string]any{
+			"@context": []any{
+				"https://www.w3.org/2018/credentials/v1",
+				"https://w3id.org/security/suites/ed25519-2020/v1",
+			},
+			"type":                 "VerifiablePresentation",
+			"holder":               holder,
+			"verifiableCredential": []any{credential},
+		}
+		proof := ed25519Proof(holder)
+		proof["proofPurpose"] = PurposeAuthentication
+		presentation = sign(presentation, proof, ed25519Signer(holderKey))
+
+		Convey("When verifying it", func() {
+			report := verify(presentation)
+
+			Convey("Then the presentation and its credential should be verified", func() {
+				So(failedCheck(report), ShouldBeEmpty)
+				So(report.Verified, ShouldBeTrue)
+				So(report.Holder, ShouldEqual, holder)
+				So(report.Credentials, ShouldHaveLength, 1)
+				So(report.Credentials[0].Verified, ShouldBeTrue)
+			})
+		})
+
+		Convey("When its proof is made for assertion", func() {
+			presentation["proof"].(map[string]any)["proofPurpose"] = PurposeAssertion //nolint:forcetypeassert
+			report := verify(presentation)
+
+			Convey("Then the proof should be rejected", func() {
+				So(report.Verified, ShouldBeFalse)
+				So(report.Proofs[0].Error, ShouldEqual, `proof purpose is "assertionMethod", expected "authentication"`)
+			})
+		})
+
+		Convey("When it is signed by someone without telling its holder", func() {
+			delete(presentation, "holder")
+			delete(presentation, "proof")
+			presentation = sign(presentation, proof, ed25519Signer(holderKey))
+			report := verify(presentation)
+
+			Convey("Then it should not be verified, whoever signed it", func() {
+				So(report.Verified, ShouldBeFalse)
+				So(report.Holder, ShouldBeEmpty)
+				So(report.Checks, ShouldContain, Check{Name: CheckHolder, Message: "the presentation has no holder"})
+			})
+		})
+	})
+}
@@ -0,0 +1,183 @@
+package did
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
+	"github.com/cosmos/btcutil/base58"
+	"github.com/samber/lo"
+)
+
+const (
+	MethodKey   = "key"
+	MethodWeb   = "web"
+	MethodAxone = "axone"
+)
+
+// Pattern is the JSON Schema pattern of a DID, as defined by the DID Core syntax.
+const Pattern = `^did:[a-z0-9]+:(?:[A-Za-z0-9._-]|%[0-9A-Fa-f]{2}|:)*(?:[A-Za-z0-9._-]|%[0-9A-Fa-f]{2})$`
+
+var ErrInvalidDID = errors.New("invalid DID")
+
+var didRegexp = regexp.MustCompile(Pattern)
+
+// KeyType is the type of the public key encoded in a did:key.
+type KeyType string
+
+const (
+	KeyTypeEd25519   KeyType = "Ed25519"
+	KeyTypeSecp256k1 KeyType = "Secp256k1"
+)
+
+// keyCodecs are the multicodec codes of the supported public keys, along with their expected length.
+var keyCodecs = map[uint64]struct {
+	keyType KeyType
+	length  int
+}{
+	0xed: {KeyTypeEd25519, 32},
+	0xe7: {KeyTypeSecp256k1, 33},
+}
+
+// DID is a decentralized identifier, split in its method and method specific identifier.
+type DID struct {
+	Method string
+	ID     string
+}
+
+func (d DID) String() string {
+	return "did:" + d.Method + ":" + d.ID
+}
+
+// Key is the public key carried by a did:key.
+type Key struct {
+	Type      KeyType
+	PublicKey []byte
+}
+
+// Web is the location of the DID document of a did:web.
+type Web struct {
+	// Host is the domain name, possibly with a port, hosting the DID document.
+	Host string
+	// Path is the path to the DID document on the host, empty for the well-known location.
+	Path []string
+}
+
+// Parse parses the given string as a DID, with no regard to its method.
+func Parse(s string) (DID, error) {
+	if !strings.HasPrefix(s, "did:") {
+		return DID{}, fmt.Errorf("%w %q: missing did: scheme", ErrInvalidDID, s)
+	}
+	if !didRegexp.MatchString(s) {
+		return DID{}, fmt.Errorf("%w %q: malformed method or identifier", ErrInvalidDID, s)
+	}
+
+	method, id, _ := strings.Cut(strings.TrimPrefix(s, "did:"), ":")
+	return DID{Method: method, ID: id}, nil
+}
+
+// Validate checks the given string is a well-formed DID, going further for the did:key, did:web and did:axone methods.
+func Validate(s string) error {
+	d, err := Parse(s)
+	if err != nil {
+		return err
+	}
+
+	switch d.Method {
+	case MethodKey:
+		_, err = d.Key()
+	case MethodWeb:
+		_, err = d.Web()
+	case MethodAxone:
+		_, err = d.Account()
+	}
+
+	return err
+}
+
+// Key decodes the public key of a did:key. Only base58btc multibase encoded Ed25519 and Secp256k1 keys are supported.
+func (d DID) Key() (*Key, error) {
+	if d.Method != MethodKey {
+		return nil, fmt.Errorf("%w %q: not a did:key", ErrInvalidDID, d)
+	}
+
+	if !strings.HasPrefix(d.ID, "z") {
+		return nil, fmt.Errorf("%w %q: key is not base58btc multibase encoded", ErrInvalidDID, d)
+	}
+	bz := base58.Decode(d.ID[1:])
+	if len(bz) == 0 {
+		return nil, fmt.Errorf("%w %q: key is not valid base58", ErrInvalidDID, d)
+	}
+
+	codec, n := binary.Uvarint(bz)
+	if n <= 0 {
+		return nil, fmt.Errorf("%w %q: key has no multicodec prefix", ErrInvalidDID, d)
+	}
+	spec, ok := keyCodecs[codec]
+	if !ok {
+		return nil, fmt.Errorf("%w %q: unsupported key type 0x%x", ErrInvalidDID, d, codec)
+	}
+	if len(bz[n:]) != spec.length {
+		return nil, fmt.Errorf("%w %q: %s key is %d bytes long, expected %d",
+			ErrInvalidDID, d, spec.keyType, len(bz[n:]), spec.length)
+	}
+
+	return &Key{Type: spec.keyType, PublicKey: bz[n:]}, nil
+}
+
+// Web decodes the location of the DID document of a did:web.
+func (d DID) Web() (*Web, error) {
+	if d.Method != MethodWeb {
+		return nil, fmt.Errorf("%w %q: not a did:web", ErrInvalidDID, d)
+	}
+
+	segments := strings.Split(d.ID, ":")
+	host, err := url.PathUnescape(segments[0])
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: malformed host: %w", ErrInvalidDID, d, err)
+	}
+	if u, err := url.Parse("https://" + host); err != nil || u.Host != host || u.Hostname() == "" {
+		return nil, fmt.Errorf("%w %q: malformed host %q", ErrInvalidDID, d, host)
+	}
+
+	path := make([]string, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		if segment == "" {
+			return nil, fmt.Errorf("%w %q: empty path segment", ErrInvalidDID, d)
+		}
+		p, err := url.PathUnescape(segment)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: malformed path: %w", ErrInvalidDID, d, err)
+		}
+		path = append(path, p)
+	}
+
+	return &Web{Host: host, Path: path}, nil
+}
+
+// URL returns the location of the DID document.
+func (w *Web) URL() string {
+	if len(w.Path) == 0 {
+		return "https://" + w.Host + "/.well-known/did.json"
+	}
+
+	return "https://" + w.Host + "/" + strings.Join(lo.Map(w.Path, func(p string, _ int) string {
+		return url.PathEscape(p)
+	}), "/") + "/did.json"
+}
+
+// Account returns the account address identified by a did:axone.
+func (d DID) Account() (string, error) {
+	if d.Method != MethodAxone {
+		return "", fmt.Errorf("%w %q: not a did:axone", ErrInvalidDID, d)
+	}
+	if err := address.ValidateAccount(d.ID); err != nil {
+		return "", fmt.Errorf("%w %q: %w", ErrInvalidDID, d, err)
+	}
+
+	return d.ID, nil
+}
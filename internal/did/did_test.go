@@ -0,0 +1,109 @@
+package did
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestValidate(t *testing.T) {
+	Convey("Given DIDs to validate", t, func() {
+		cases := []struct {
+			did string
+			err string
+		}{
+			{did: "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F"},
+			{did: "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP"},
+			{did: "did:web:example.com"},
+			{did: "did:web:example.com%3A3000:users:alice"},
+			{did: "did:example:123456789abcdefghi"},
+			{
+				did: "foo:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+				err: `invalid DID "foo:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP": missing did: scheme`,
+			},
+			{
+				did: "did:Key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+				err: `invalid DID "did:Key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP": malformed method or identifier`,
+			},
+			{
+				did: "did:key:",
+				err: `invalid DID "did:key:": malformed method or identifier`,
+			},
+			{
+				did: "did:key:Q3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F",
+				err: `invalid DID "did:key:Q3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F": ` +
+					`key is not base58btc multibase encoded`,
+			},
+			{
+				did: "did:key:z0OIl",
+				err: `invalid DID "did:key:z0OIl": key is not valid base58`,
+			},
+			{
+				did: "did:key:z2DQUyFHStG42FqbEhyM6LhkEqqV45NGGqKCwNxVWWu7Yzj",
+				err: `invalid DID "did:key:z2DQUyFHStG42FqbEhyM6LhkEqqV45NGGqKCwNxVWWu7Yzj": ` +
+					`Ed25519 key is 31 bytes long, expected 32`,
+			},
+			{
+				did: "did:key:zDnadoyLJe2pfjUYTdAKesX7NfKRF3p88mNX8wrJSZpavNn47",
+				err: `invalid DID "did:key:zDnadoyLJe2pfjUYTdAKesX7NfKRF3p88mNX8wrJSZpavNn47": unsupported key type 0x1200`,
+			},
+			{
+				did: "did:web:example.com::alice",
+				err: `invalid DID "did:web:example.com::alice": empty path segment`,
+			},
+			{
+				did: "did:web:exa%2Fmple.com",
+				err: `invalid DID "did:web:exa%2Fmple.com": malformed host "exa/mple.com"`,
+			},
+		}
+
+		for _, c := range cases {
+			Convey(fmt.Sprintf("When validating %q", c.did), func() {
+				err := Validate(c.did)
+
+				Convey("Then the result should be as expected", func() {
+					if c.err == "" {
+						So(err, ShouldBeNil)
+					} else {
+						So(errors.Is(err, ErrInvalidDID), ShouldBeTrue)
+						So(err.Error(), ShouldEqual, c.err)
+					}
+				})
+			})
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	Convey("Given a did:key", t, func() {
+		d, err := Parse("did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F")
+		So(err, ShouldBeNil)
+
+		Convey("When decoding its key", func() {
+			key, err := d.Key()
+
+			Convey("Then the public key should be returned along with its type", func() {
+				So(err, ShouldBeNil)
+				So(key.Type, ShouldEqual, KeyTypeSecp256k1)
+				So(key.PublicKey, ShouldHaveLength, 33)
+			})
+		})
+	})
+
+	Convey("Given a did:web", t, func() {
+		d, err := Parse("did:web:example.com%3A3000:users:alice")
+		So(err, ShouldBeNil)
+
+		Convey("When decoding its location", func() {
+			web, err := d.Web()
+
+			Convey("Then the host and path should be returned", func() {
+				So(err, ShouldBeNil)
+				So(web, ShouldResemble, &Web{Host: "example.com:3000", Path: []string{"users", "alice"}})
+				So(d.String(), ShouldEqual, "did:web:example.com%3A3000:users:alice")
+			})
+		})
+	})
+}
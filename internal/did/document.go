@@ -0,0 +1,125 @@
+package did
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+const (
+	ContextDIDv1 = "https://www.w3.org/ns/did/v1"
+)
+
+// Document is a W3C DID document.
+type Document struct {
+	Context              any                  `json:"@context"`
+	ID                   string               `json:"id"`
+	Controller           any                  `json:"controller,omitempty"`
+	AlsoKnownAs          []string             `json:"alsoKnownAs,omitempty"`
+	VerificationMethod   []VerificationMethod `json:"verificationMethod,omitempty"`
+	Authentication       []string             `json:"authentication,omitempty"`
+	AssertionMethod      []string             `json:"assertionMethod,omitempty"`
+	KeyAgreement         []string             `json:"keyAgreement,omitempty"`
+	CapabilityInvocation []string             `json:"capabilityInvocation,omitempty"`
+	CapabilityDelegation []string             `json:"capabilityDelegation,omitempty"`
+	Service              []Service            `json:"service,omitempty"`
+}
+
+// VerificationMethod is a public key, or any other material, allowing to verify proofs made by the DID subject.
+type VerificationMethod struct {
+	ID                  string         `json:"id"`
+	Type                string         `json:"type"`
+	Controller          string         `json:"controller"`
+	PublicKeyMultibase  string         `json:"publicKeyMultibase,omitempty"`
+	PublicKeyBase58     string         `json:"publicKeyBase58,omitempty"`
+	PublicKeyJwk        map[string]any `json:"publicKeyJwk,omitempty"`
+	BlockchainAccountID string         `json:"blockchainAccountId,omitempty"`
+}
+
+// Service is a mean of communicating or interacting with the DID subject.
+type Service struct {
+	ID              string `json:"id"`
+	Type            any    `json:"type"`
+	ServiceEndpoint any    `json:"serviceEndpoint"`
+}
+
+// VerificationMethodByID returns the verification method of the document with the given identifier, either absolute
+// or relative to the document.
+func (d *Document) VerificationMethodByID(id string) (*VerificationMethod, bool) {
+	for i, vm := range d.VerificationMethod {
+		if vm.ID == id || d.ID+vm.ID == id || vm.ID == d.ID+id {
+			return &d.VerificationMethod[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// Authorizes tells whether the verification method with the given identifier is listed in the given relationship.
+func (d *Document) Authorizes(relationship []string, id string) bool {
+	return slices.ContainsFunc(relationship, func(ref string) bool {
+		return ref == id || d.ID+ref == id || ref == d.ID+id
+	})
+}
+
+// UnmarshalJSON decodes a DID document, moving the verification methods embedded in the verification relationships
+// to the verification methods of the document, so that relationships only hold references.
+func (d *Document) UnmarshalJSON(bz []byte) error {
+	type plain Document
+	var raw struct {
+		plain
+		Authentication       []json.RawMessage `json:"authentication"`
+		AssertionMethod      []json.RawMessage `json:"assertionMethod"`
+		KeyAgreement         []json.RawMessage `json:"keyAgreement"`
+		CapabilityInvocation []json.RawMessage `json:"capabilityInvocation"`
+		CapabilityDelegation []json.RawMessage `json:"capabilityDelegation"`
+	}
+	if err := json.Unmarshal(bz, &raw); err != nil {
+		return err
+	}
+
+	*d = Document(raw.plain)
+	for _, r := range []struct {
+		from []json.RawMessage
+		to   *[]string
+	}{
+		{raw.Authentication, &d.Authentication},
+		{raw.AssertionMethod, &d.AssertionMethod},
+		{raw.KeyAgreement, &d.KeyAgreement},
+		{raw.CapabilityInvocation, &d.CapabilityInvocation},
+		{raw.CapabilityDelegation, &d.CapabilityDelegation},
+	} {
+		refs, err := d.references(r.from)
+		if err != nil {
+			return err
+		}
+		*r.to = refs
+	}
+
+	return nil
+}
+
+func (d *Document) references(entries []json.RawMessage) ([]string, error) {
+	if entries == nil {
+		return nil, nil
+	}
+
+	refs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		var ref string
+		if err := json.Unmarshal(entry, &ref); err == nil {
+			refs = append(refs, ref)
+			continue
+		}
+
+		var vm VerificationMethod
+		if err := json.Unmarshal(entry, &vm); err != nil {
+			return nil, err
+		}
+		if _, ok := d.VerificationMethodByID(vm.ID); !ok {
+			d.VerificationMethod = append(d.VerificationMethod, vm)
+		}
+		refs = append(refs, vm.ID)
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,216 @@
+package did
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/publichttp"
+)
+
+const (
+	contextEd25519   = "https://w3id.org/security/suites/ed25519-2020/v1"
+	contextSecp256k1 = "https://w3id.org/security/suites/secp256k1-2019/v1"
+	contextRecovery  = "https://w3id.org/security/suites/secp256k1recovery-2020/v2"
+)
+
+// DefaultChainID is the chain the did:axone accounts are resolved on, unless configured otherwise.
+const DefaultChainID = "axone-1"
+
+var (
+	ErrUnsupportedMethod = errors.New("unsupported DID method")
+	ErrNotFound          = errors.New("DID document not found")
+	ErrForbiddenAddress  = publichttp.ErrForbiddenAddress
+)
+
+// Fetcher retrieves the DID documents published on the web.
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) ([]byte, error)
+}
+
+// HTTPFetcher is a Fetcher performing HTTP GET requests.
+type HTTPFetcher struct {
+	Client *http.Client
+	// MaxSize is the maximum size in bytes of a fetched document.
+	MaxSize int64
+}
+
+// NewHTTPFetcher returns an HTTPFetcher with sensible timeout and size limits, which does not follow redirects and
+// only connects to public addresses, the host of a did:web being given by the caller.
+func NewHTTPFetcher() *HTTPFetcher {
+	return &HTTPFetcher{
+		Client:  publichttp.NewClient(),
+		MaxSize: 1 << 20,
+	}
+}
+
+// Fetch implements Fetcher. Only https URLs are fetched.
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	if u, err := url.Parse(rawURL); err != nil || u.Scheme != "https" {
+		return nil, fmt.Errorf("fetch %s: not an https URL", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/did+json, application/json")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%w at %s", ErrNotFound, rawURL)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("fetch %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(bz)) > f.MaxSize {
+		return nil, fmt.Errorf("fetch %s: document exceeds %d bytes", rawURL, f.MaxSize)
+	}
+
+	return bz, nil
+}
+
+// Resolver resolves DIDs into their DID document.
+type Resolver struct {
+	fetcher Fetcher
+	chainID string
+}
+
+// ResolverOption configures a Resolver.
+type ResolverOption func(*Resolver)
+
+// WithFetcher sets the fetcher used to retrieve the did:web documents.
+func WithFetcher(f Fetcher) ResolverOption {
+	return func(r *Resolver) {
+		r.fetcher = f
+	}
+}
+
+// WithChainID sets the chain the did:axone accounts are resolved on.
+func WithChainID(chainID string) ResolverOption {
+	return func(r *Resolver) {
+		r.chainID = chainID
+	}
+}
+
+// NewResolver creates a resolver supporting the did:key, did:web and did:axone methods.
+func NewResolver(opts ...ResolverOption) *Resolver {
+	r := &Resolver{fetcher: NewHTTPFetcher(), chainID: DefaultChainID}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Resolve returns the DID document of the given DID.
+func (r *Resolver) Resolve(ctx context.Context, s string) (*Document, error) {
+	d, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch d.Method {
+	case MethodKey:
+		return resolveKey(d)
+	case MethodWeb:
+		return r.resolveWeb(ctx, d)
+	case MethodAxone:
+		return r.resolveAxone(d)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, d.Method)
+	}
+}
+
+// resolveKey derives the DID document of a did:key from the key it carries, as defined by the did:key method.
+func resolveKey(d DID) (*Document, error) {
+	key, err := d.Key()
+	if err != nil {
+		return nil, err
+	}
+
+	vm := VerificationMethod{
+		ID:                 d.String() + "#" + d.ID,
+		Controller:         d.String(),
+		PublicKeyMultibase: d.ID,
+	}
+	var suite string
+	switch key.Type {
+	case KeyTypeEd25519:
+		vm.Type, suite = "Ed25519VerificationKey2020", contextEd25519
+	case KeyTypeSecp256k1:
+		vm.Type, suite = "EcdsaSecp256k1VerificationKey2019", contextSecp256k1
+	}
+
+	return &Document{
+		Context:              []string{ContextDIDv1, suite},
+		ID:                   d.String(),
+		VerificationMethod:   []VerificationMethod{vm},
+		Authentication:       []string{vm.ID},
+		AssertionMethod:      []string{vm.ID},
+		CapabilityInvocation: []string{vm.ID},
+		CapabilityDelegation: []string{vm.ID},
+	}, nil
+}
+
+// resolveWeb fetches the DID document of a did:web from the location it designates, as defined by the did:web method.
+func (r *Resolver) resolveWeb(ctx context.Context, d DID) (*Document, error) {
+	web, err := d.Web()
+	if err != nil {
+		return nil, err
+	}
+
+	bz, err := r.fetcher.Fetch(ctx, web.URL())
+	if err != nil {
+		return nil, fmt.Errorf("resolve %s: %w", d, err)
+	}
+
+	var doc Document
+	if err := json.Unmarshal(bz, &doc); err != nil {
+		return nil, fmt.Errorf("resolve %s: malformed DID document: %w", d, err)
+	}
+	if doc.ID != d.String() {
+		return nil, fmt.Errorf("resolve %s: DID document is about %q", d, doc.ID)
+	}
+
+	return &doc, nil
+}
+
+// resolveAxone derives the DID document of a did:axone, identifying an account of the Axone chain, verified by
+// recovering the signer of its transactions.
+func (r *Resolver) resolveAxone(d DID) (*Document, error) {
+	account, err := d.Account()
+	if err != nil {
+		return nil, err
+	}
+
+	vm := VerificationMethod{
+		ID:                  d.String() + "#account",
+		Type:                "EcdsaSecp256k1RecoveryMethod2020",
+		Controller:          d.String(),
+		BlockchainAccountID: strings.Join([]string{"cosmos", r.chainID, account}, ":"),
+	}
+
+	return &Document{
+		Context:            []string{ContextDIDv1, contextRecovery},
+		ID:                 d.String(),
+		VerificationMethod: []VerificationMethod{vm},
+		Authentication:     []string{vm.ID},
+		AssertionMethod:    []string{vm.ID},
+	}, nil
+}
@@ -0,0 +1,171 @@
+package did
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/publichttp"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestResolveKey(t *testing.T) {
+	Convey("Given a resolver", t, func() {
+		resolver := NewResolver()
+
+		Convey("When resolving an Ed25519 did:key", func() {
+			doc, err := resolver.Resolve(context.Background(), "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP")
+
+			Convey("Then the DID document should be derived from the key", func() {
+				So(err, ShouldBeNil)
+				vmID := "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP#z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP"
+				So(doc, ShouldResemble, &Document{
+					Context: []string{ContextDIDv1, "https://w3id.org/security/suites/ed25519-2020/v1"},
+					ID:      "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+					VerificationMethod: []VerificationMethod{{
+						ID:                 vmID,
+						Type:               "Ed25519VerificationKey2020",
+						Controller:         "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+						PublicKeyMultibase: "z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+					}},
+					Authentication:       []string{vmID},
+					AssertionMethod:      []string{vmID},
+					CapabilityInvocation: []string{vmID},
+					CapabilityDelegation: []string{vmID},
+				})
+			})
+		})
+
+		Convey("When resolving a Secp256k1 did:key", func() {
+			doc, err := resolver.Resolve(context.Background(), "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F")
+
+			Convey("Then the verification method should be a Secp256k1 key", func() {
+				So(err, ShouldBeNil)
+				So(doc.VerificationMethod, ShouldHaveLength, 1)
+				So(doc.VerificationMethod[0].Type, ShouldEqual, "EcdsaSecp256k1VerificationKey2019")
+			})
+		})
+
+		Convey("When resolving a did:axone", func() {
+			doc, err := resolver.Resolve(context.Background(), "did:axone:axone1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqs9vgnq")
+
+			Convey("Then the verification method should designate the account", func() {
+				So(err, ShouldBeNil)
+				So(doc.VerificationMethod, ShouldHaveLength, 1)
+				So(doc.VerificationMethod[0].BlockchainAccountID, ShouldEqual,
+					"cosmos:axone-1:axone1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqs9vgnq")
+			})
+		})
+
+		Convey("When resolving a DID of an unsupported method", func() {
+			_, err := resolver.Resolve(context.Background(), "did:example:123")
+
+			Convey("Then it should fail", func() {
+				So(errors.Is(err, ErrUnsupportedMethod), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestResolveWeb(t *testing.T) {
+	Convey("Given a web server publishing DID documents", t, func() {
+		var did string
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/.well-known/did.json":
+				_, _ = w.Write([]byte(`{
+					"@context": "https://www.w3.org/ns/did/v1",
+					"id": "` + did + `",
+					"verificationMethod": [{"id": "#key-1", "type": "JsonWebKey2020", "controller": "` + did + `",
+						"publicKeyJwk": {"kty": "OKP", "crv": "Ed25519", "x": "foo"}}],
+					"authentication": ["#key-1", {"id": "#key-2", "type": "Ed25519VerificationKey2020",
+						"controller": "` + did + `", "publicKeyMultibase": "z6Mk"}]
+				}`))
+			case "/users/carol/did.json":
+				http.Redirect(w, r, "/.well-known/did.json", http.StatusFound)
+			case "/users/dave/did.json":
+				_, _ = w.Write([]byte(`{"id": "` + strings.Repeat("x", 1<<10) + `"}`))
+			case "/users/alice/did.json":
+				_, _ = w.Write([]byte(`{"id": "did:web:evil.com"}`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		Reset(srv.Close)
+
+		host := strings.ReplaceAll(strings.TrimPrefix(srv.URL, "https://"), ":", "%3A")
+		did = "did:web:" + host
+		client := srv.Client()
+		client.CheckRedirect = publichttp.NoRedirect
+		resolver := NewResolver(WithFetcher(&HTTPFetcher{Client: client, MaxSize: 1 << 10}))
+
+		Convey("When resolving a did:web at the well-known location", func() {
+			doc, err := resolver.Resolve(context.Background(), did)
+
+			Convey("Then the published document should be returned, with embedded methods referenced", func() {
+				So(err, ShouldBeNil)
+				So(doc.ID, ShouldEqual, did)
+				So(doc.VerificationMethod, ShouldHaveLength, 2)
+				So(doc.Authentication, ShouldResemble, []string{"#key-1", "#key-2"})
+				vm, ok := doc.VerificationMethodByID(did + "#key-2")
+				So(ok, ShouldBeTrue)
+				So(vm.PublicKeyMultibase, ShouldEqual, "z6Mk")
+				So(doc.Authorizes(doc.Authentication, did+"#key-1"), ShouldBeTrue)
+			})
+		})
+
+		Convey("When resolving a did:web whose document is about another DID", func() {
+			_, err := resolver.Resolve(context.Background(), did+":users:alice")
+
+			Convey("Then it should be rejected", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEndWith, `DID document is about "did:web:evil.com"`)
+			})
+		})
+
+		Convey("When resolving a did:web without document", func() {
+			_, err := resolver.Resolve(context.Background(), did+":users:bob")
+
+			Convey("Then it should not be found", func() {
+				So(errors.Is(err, ErrNotFound), ShouldBeTrue)
+			})
+		})
+
+		Convey("When resolving a did:web whose document is redirected", func() {
+			_, err := resolver.Resolve(context.Background(), did+":users:carol")
+
+			Convey("Then the redirect should not be followed", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEndWith, "unexpected status 302 Found")
+			})
+		})
+
+		Convey("When resolving a did:web whose document is too large", func() {
+			_, err := resolver.Resolve(context.Background(), did+":users:dave")
+
+			Convey("Then it should be rejected", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEndWith, "document exceeds 1024 bytes")
+			})
+		})
+
+		Convey("When resolving a did:web on a loopback address with the default fetcher", func() {
+			_, err := NewResolver().Resolve(context.Background(), did)
+
+			Convey("Then the connection should be refused", func() {
+				So(errors.Is(err, ErrForbiddenAddress), ShouldBeTrue)
+			})
+		})
+
+		Convey("When fetching a document over plain http", func() {
+			_, err := NewHTTPFetcher().Fetch(context.Background(), "http://example.com/.well-known/did.json")
+
+			Convey("Then it should be rejected", func() {
+				So(err, ShouldBeError, "fetch http://example.com/.well-known/did.json: not an https URL")
+			})
+		})
+	})
+}
@@ -0,0 +1,94 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"math"
+
+	"github.com/axone-protocol/axone-mcp/internal/search"
+)
+
+// ErrUnavailable is returned when the embedding backend cannot be reached, or fails to answer.
+var ErrUnavailable = errors.New("embedding backend unavailable")
+
+// Embedder turns texts into vectors, the closer the texts in meaning, the closer their vectors.
+type Embedder interface {
+	// Embed returns the vectors of the given texts, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// DefaultHashDimensions is the default size of the vectors of the HashEmbedder.
+const DefaultHashDimensions = 256
+
+// HashEmbedder is an Embedder hashing the terms of the texts, and the pairs of consecutive terms, into fixed size
+// vectors. It runs locally and deterministically, but only captures lexical similarity.
+type HashEmbedder struct {
+	Dimensions int
+}
+
+// NewHashEmbedder creates a HashEmbedder of DefaultHashDimensions dimensions.
+func NewHashEmbedder() *HashEmbedder {
+	return &HashEmbedder{Dimensions: DefaultHashDimensions}
+}
+
+// Embed implements Embedder.
+func (e *HashEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		v := make([]float32, e.Dimensions)
+		terms := search.Tokenize(text)
+		for i, term := range terms {
+			e.add(v, term, 1)
+			if i > 0 {
+				e.add(v, terms[i-1]+" "+term, 0.5)
+			}
+		}
+		vectors = append(vectors, Normalize(v))
+	}
+
+	return vectors, nil
+}
+
+// add adds the weight to the dimension the feature hashes to, with the sign given by the hash.
+func (e *HashEmbedder) add(v []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(feature))
+	sum := h.Sum64()
+	if sum&(1<<63) != 0 {
+		weight = -weight
+	}
+	v[sum%uint64(len(v))] += weight
+}
+
+// Normalize scales the vector in place to a unit length, leaving null vectors untouched.
+func Normalize(v []float32) []float32 {
+	var norm float64
+	for _, x := range v {
+		norm += float64(x) * float64(x)
+	}
+	if norm == 0 {
+		return v
+	}
+	norm = math.Sqrt(norm)
+	for i := range v {
+		v[i] = float32(float64(v[i]) / norm)
+	}
+
+	return v
+}
+
+// Cosine returns the cosine similarity of two vectors of the same size, 0 if one of them is null.
+func Cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range min(len(a), len(b)) {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+
+	return dot / math.Sqrt(na*nb)
+}
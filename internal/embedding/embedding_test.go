@@ -0,0 +1,107 @@
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHashEmbedder(t *testing.T) {
+	Convey("Given a hash embedder", t, func() {
+		e := NewHashEmbedder()
+
+		Convey("When embedding texts", func() {
+			vectors, err := e.Embed(context.Background(), []string{
+				"Air quality measurements in France",
+				"Measurements of the air quality",
+				"Road traffic counts",
+				"",
+			})
+
+			Convey("Then unit vectors of the configured size should be returned", func() {
+				So(err, ShouldBeNil)
+				So(vectors, ShouldHaveLength, 4)
+				So(vectors[0], ShouldHaveLength, DefaultHashDimensions)
+				So(Cosine(vectors[0], vectors[0]), ShouldAlmostEqual, 1, 1e-6)
+			})
+
+			Convey("Then texts sharing terms should be closer than unrelated ones", func() {
+				So(Cosine(vectors[0], vectors[1]), ShouldBeGreaterThan, Cosine(vectors[0], vectors[2]))
+			})
+
+			Convey("Then the empty text should be embedded as a null vector", func() {
+				So(Cosine(vectors[0], vectors[3]), ShouldEqual, 0)
+			})
+		})
+
+		Convey("When embedding the same text twice", func() {
+			first, err1 := e.Embed(context.Background(), []string{"air quality"})
+			second, err2 := e.Embed(context.Background(), []string{"air quality"})
+
+			Convey("Then the vectors should be the same", func() {
+				So(err1, ShouldBeNil)
+				So(err2, ShouldBeNil)
+				So(first, ShouldResemble, second)
+			})
+		})
+	})
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	Convey("Given an OpenAI compatible API", t, func() {
+		var (
+			got    embeddingsRequest
+			path   string
+			auth   string
+			status = http.StatusOK
+			body   = `{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`
+		)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+			auth = r.Header.Get("Authorization")
+			_ = json.NewDecoder(r.Body).Decode(&got)
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		Reset(srv.Close)
+
+		e := NewOpenAIEmbedder(srv.URL+"/v1/", "test-model", "secret")
+
+		Convey("When embedding texts", func() {
+			vectors, err := e.Embed(context.Background(), []string{"first", "second"})
+
+			Convey("Then the vectors should be returned in the order of the texts", func() {
+				So(err, ShouldBeNil)
+				So(path, ShouldEqual, "/v1/embeddings")
+				So(got, ShouldResemble, embeddingsRequest{Model: "test-model", Input: []string{"first", "second"}})
+				So(auth, ShouldEqual, "Bearer secret")
+				So(vectors, ShouldResemble, [][]float32{{1, 0}, {0, 1}})
+			})
+		})
+
+		Convey("When the API rejects the request", func() {
+			status = http.StatusUnauthorized
+			body = `{"error":{"message":"invalid api key"}}`
+			_, err := e.Embed(context.Background(), []string{"first"})
+
+			Convey("Then the error of the API should be returned", func() {
+				So(errors.Is(err, ErrUnavailable), ShouldBeTrue)
+				So(err.Error(), ShouldEqual, "embedding backend unavailable: 401 Unauthorized: invalid api key")
+			})
+		})
+
+		Convey("When the API misses an embedding", func() {
+			body = `{"data":[{"index":0,"embedding":[1,0]}]}`
+			_, err := e.Embed(context.Background(), []string{"first", "second"})
+
+			Convey("Then an error should be returned", func() {
+				So(err, ShouldBeError, "embedding backend unavailable: no embedding returned for input 1")
+			})
+		})
+	})
+}
@@ -0,0 +1,106 @@
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOpenAIURL is the base URL of the OpenAI API.
+	DefaultOpenAIURL = "https://api.openai.com/v1"
+	// DefaultOpenAIModel is the default embedding model.
+	DefaultOpenAIModel = "text-embedding-3-small"
+)
+
+// OpenAIEmbedder is an Embedder calling the embeddings endpoint of an OpenAI compatible API, such as OpenAI itself,
+// Ollama or vLLM.
+type OpenAIEmbedder struct {
+	Client *http.Client
+	// URL is the base URL of the API, the embeddings being requested to URL/embeddings.
+	URL    string
+	Model  string
+	APIKey string
+}
+
+// NewOpenAIEmbedder creates an OpenAIEmbedder with a sensible timeout. The API key may be empty for local servers.
+func NewOpenAIEmbedder(url, model, apiKey string) *OpenAIEmbedder {
+	return &OpenAIEmbedder{
+		Client: &http.Client{Timeout: 30 * time.Second},
+		URL:    strings.TrimSuffix(url, "/"),
+		Model:  model,
+		APIKey: apiKey,
+	}
+}
+
+type embeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type embeddingsResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Embed implements Embedder.
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(embeddingsRequest{Model: e.Model, Input: texts})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.APIKey)
+	}
+
+	resp, err := e.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, 64<<20))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+	var out embeddingsResponse
+	if err := json.Unmarshal(bz, &out); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("%w: decode response: %w", ErrUnavailable, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if out.Error != nil && out.Error.Message != "" {
+			return nil, fmt.Errorf("%w: %s: %s", ErrUnavailable, resp.Status, out.Error.Message)
+		}
+		return nil, fmt.Errorf("%w: unexpected status %s", ErrUnavailable, resp.Status)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("%w: embedding index %d out of range", ErrUnavailable, d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("%w: no embedding returned for input %d", ErrUnavailable, i)
+		}
+	}
+
+	return vectors, nil
+}
@@ -0,0 +1,496 @@
+package fakenode
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	schema "github.com/axone-protocol/axone-contract-schema/go/cognitarium-schema/v6"
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/samber/lo"
+)
+
+// DefaultLimit is the number of solutions answered by a select query without any limit, as the real store does.
+const DefaultLimit = 30
+
+type termKind int
+
+const (
+	kindIRI termKind = iota
+	kindBlankNode
+	kindLiteral
+)
+
+// Term is a node or a literal of a triple.
+type Term struct {
+	kind     termKind
+	Value    string
+	Language string
+	Datatype string
+}
+
+// IRI returns the named node of the given full IRI.
+func IRI(iri string) Term {
+	return Term{kind: kindIRI, Value: iri}
+}
+
+// BlankNode returns the blank node of the given identifier.
+func BlankNode(id string) Term {
+	return Term{kind: kindBlankNode, Value: id}
+}
+
+// Literal returns the simple literal of the given value.
+func Literal(value string) Term {
+	return Term{kind: kindLiteral, Value: value}
+}
+
+// LangLiteral returns the literal of the given value, tagged with the given language.
+func LangLiteral(value, language string) Term {
+	return Term{kind: kindLiteral, Value: value, Language: language}
+}
+
+// TypedLiteral returns the literal of the given value, of the datatype of the given full IRI.
+func TypedLiteral(value, datatype string) Term {
+	return Term{kind: kindLiteral, Value: value, Datatype: datatype}
+}
+
+// value returns the term as bound in a select response.
+func (t Term) value() schema.ValueType {
+	switch t.kind {
+	case kindIRI:
+		return schema.URI{Type: "uri", Value: schema.IRI{Full: lo.ToPtr(schema.IRI_Full(t.Value))}}
+	case kindBlankNode:
+		return schema.BlankNode{Type: "blank_node", Value: t.Value}
+	default:
+		literal := schema.Value_Literal{Type: "literal", Value: t.Value}
+		if t.Language != "" {
+			literal.Lang = lo.ToPtr(t.Language)
+		}
+		if t.Datatype != "" {
+			literal.Datatype = &schema.IRI{Full: lo.ToPtr(schema.IRI_Full(t.Datatype))}
+		}
+		return literal
+	}
+}
+
+// Triple is a statement held by a cognitarium.
+type Triple struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+}
+
+// Cognitarium is a simulated cognitarium contract holding its triples in memory, answering the select query by
+// evaluating its basic graph patterns, filters and lateral joins. Solutions are answered in the order of the triples.
+type Cognitarium struct {
+	mu      sync.RWMutex
+	triples []Triple
+}
+
+// NewCognitarium returns a cognitarium holding the given triples.
+func NewCognitarium(triples ...Triple) *Cognitarium {
+	return &Cognitarium{triples: triples}
+}
+
+// Insert adds the given triples to the cognitarium.
+func (c *Cognitarium) Insert(triples ...Triple) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.triples = append(c.triples, triples...)
+}
+
+// Query implements Contract.
+func (c *Cognitarium) Query(name string, msg json.RawMessage) (any, error) {
+	if name != "select" {
+		return nil, unknownQuery(name)
+	}
+
+	var req struct {
+		Query selectQuery `json:"query"`
+	}
+	if err := json.Unmarshal(msg, &req); err != nil {
+		return nil, fmt.Errorf("invalid select query: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.selectQuery(req.Query)
+}
+
+// selectQuery is the select query of the cognitarium. It is decoded by hand, as the generated schema does not decode
+// the operands of the comparisons, encoded as an array.
+type selectQuery struct {
+	Limit    *int                `json:"limit"`
+	Prefixes []schema.Prefix     `json:"prefixes"`
+	Select   []schema.SelectItem `json:"select"`
+	Where    whereClause         `json:"where"`
+}
+
+type whereClause struct {
+	Bgp         *schema.WhereClause_Bgp `json:"bgp"`
+	LateralJoin *lateralJoin            `json:"lateral_join"`
+	Filter      *filter                 `json:"filter"`
+}
+
+type lateralJoin struct {
+	Left  whereClause `json:"left"`
+	Right whereClause `json:"right"`
+}
+
+type filter struct {
+	Expr  expression  `json:"expr"`
+	Inner whereClause `json:"inner"`
+}
+
+type expression struct {
+	NamedNode      *schema.IRI     `json:"named_node"`
+	Literal        *schema.Literal `json:"literal"`
+	Variable       *string         `json:"variable"`
+	And            []expression    `json:"and"`
+	Or             []expression    `json:"or"`
+	Equal          []expression    `json:"equal"`
+	Greater        []expression    `json:"greater"`
+	GreaterOrEqual []expression    `json:"greater_or_equal"`
+	Less           []expression    `json:"less"`
+	LessOrEqual    []expression    `json:"less_or_equal"`
+	Not            *expression     `json:"not"`
+}
+
+// solution binds the variables of a query to terms.
+type solution map[string]Term
+
+type selectResponse struct {
+	Head    schema.Head `json:"head"`
+	Results struct {
+		Bindings []map[string]schema.ValueType `json:"bindings"`
+	} `json:"results"`
+}
+
+func (c *Cognitarium) selectQuery(q selectQuery) (*selectResponse, error) {
+	e := evaluator{triples: c.triples, prefixes: make(map[string]string, len(q.Prefixes))}
+	for _, p := range q.Prefixes {
+		e.prefixes[p.Prefix] = p.Namespace
+	}
+
+	solutions, err := e.where(q.Where, []solution{{}})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &selectResponse{Head: schema.Head{Vars: []string{}}}
+	response.Results.Bindings = []map[string]schema.ValueType{}
+	for _, item := range q.Select {
+		if item.Variable == nil {
+			return nil, errors.New("unsupported select item")
+		}
+		response.Head.Vars = append(response.Head.Vars, string(*item.Variable))
+	}
+
+	limit := DefaultLimit
+	if q.Limit != nil {
+		limit = *q.Limit
+	}
+	for _, s := range solutions[:min(limit, len(solutions))] {
+		binding := make(map[string]schema.ValueType, len(response.Head.Vars))
+		for _, v := range response.Head.Vars {
+			if t, ok := s[v]; ok {
+				binding[v] = t.value()
+			}
+		}
+		response.Results.Bindings = append(response.Results.Bindings, binding)
+	}
+
+	return response, nil
+}
+
+// evaluator evaluates the where clause of a query over triples.
+type evaluator struct {
+	triples  []Triple
+	prefixes map[string]string
+}
+
+func (e evaluator) where(w whereClause, input []solution) ([]solution, error) {
+	switch {
+	case w.Bgp != nil:
+		return e.bgp(w.Bgp.Patterns, input)
+	case w.LateralJoin != nil:
+		left, err := e.where(w.LateralJoin.Left, input)
+		if err != nil {
+			return nil, err
+		}
+		return e.where(w.LateralJoin.Right, left)
+	case w.Filter != nil:
+		inner, err := e.where(w.Filter.Inner, input)
+		if err != nil {
+			return nil, err
+		}
+		var output []solution
+		for _, s := range inner {
+			ok, err := e.test(w.Filter.Expr, s)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				output = append(output, s)
+			}
+		}
+		return output, nil
+	default:
+		return nil, errors.New("empty where clause")
+	}
+}
+
+// termPattern is either a variable or a term of a triple pattern.
+type termPattern struct {
+	variable string
+	term     Term
+}
+
+func (e evaluator) bgp(patterns []schema.TriplePattern, solutions []solution) ([]solution, error) {
+	for _, p := range patterns {
+		subject, err := e.subject(p.Subject)
+		if err != nil {
+			return nil, err
+		}
+		predicate, err := e.predicate(p.Predicate)
+		if err != nil {
+			return nil, err
+		}
+		object, err := e.object(p.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []solution
+		for _, s := range solutions {
+			for _, t := range e.triples {
+				if extended, ok := match(s, [3]termPattern{subject, predicate, object}, [3]Term{
+					t.Subject, t.Predicate, t.Object,
+				}); ok {
+					next = append(next, extended)
+				}
+			}
+		}
+		solutions = next
+	}
+
+	return solutions, nil
+}
+
+// match returns the solution extended with the bindings matching the patterns with the terms, if they match.
+func match(s solution, patterns [3]termPattern, terms [3]Term) (solution, bool) {
+	extended := s
+	for n, p := range patterns {
+		if p.variable == "" {
+			if p.term != terms[n] {
+				return nil, false
+			}
+			continue
+		}
+		if bound, ok := extended[p.variable]; ok {
+			if bound != terms[n] {
+				return nil, false
+			}
+			continue
+		}
+		if len(extended) == len(s) {
+			extended = make(solution, len(s)+3)
+			for k, v := range s {
+				extended[k] = v
+			}
+		}
+		extended[p.variable] = terms[n]
+	}
+
+	return extended, true
+}
+
+func (e evaluator) subject(v schema.VarOrNode) (termPattern, error) {
+	if v.Variable != nil {
+		return termPattern{variable: string(*v.Variable)}, nil
+	}
+	if v.Node != nil {
+		return e.node(schema.Node(*v.Node))
+	}
+
+	return termPattern{}, errors.New("empty subject")
+}
+
+func (e evaluator) predicate(v schema.VarOrNamedNode) (termPattern, error) {
+	if v.Variable != nil {
+		return termPattern{variable: string(*v.Variable)}, nil
+	}
+	if v.NamedNode != nil {
+		iri, err := e.iri(schema.IRI(*v.NamedNode))
+		return termPattern{term: IRI(iri)}, err
+	}
+
+	return termPattern{}, errors.New("empty predicate")
+}
+
+func (e evaluator) object(v schema.VarOrNodeOrLiteral) (termPattern, error) {
+	switch {
+	case v.Variable != nil:
+		return termPattern{variable: string(*v.Variable)}, nil
+	case v.Node != nil:
+		return e.node(schema.Node(*v.Node))
+	case v.Literal != nil:
+		t, err := e.literal(schema.Literal(*v.Literal))
+		return termPattern{term: t}, err
+	default:
+		return termPattern{}, errors.New("empty object")
+	}
+}
+
+// node returns the pattern of a node, blank nodes standing for variables as in SPARQL.
+func (e evaluator) node(n schema.Node) (termPattern, error) {
+	switch {
+	case n.NamedNode != nil:
+		iri, err := e.iri(schema.IRI(*n.NamedNode))
+		return termPattern{term: IRI(iri)}, err
+	case n.BlankNode != nil:
+		return termPattern{variable: "_:" + string(*n.BlankNode)}, nil
+	default:
+		return termPattern{}, errors.New("empty node")
+	}
+}
+
+func (e evaluator) literal(l schema.Literal) (Term, error) {
+	switch {
+	case l.Simple != nil:
+		return Literal(string(*l.Simple)), nil
+	case l.LanguageTaggedString != nil:
+		return LangLiteral(l.LanguageTaggedString.Value, l.LanguageTaggedString.Language), nil
+	case l.TypedValue != nil:
+		datatype, err := e.iri(l.TypedValue.Datatype)
+		return TypedLiteral(l.TypedValue.Value, datatype), err
+	default:
+		return Term{}, errors.New("empty literal")
+	}
+}
+
+// iri returns the full IRI, expanding its prefix if prefixed.
+func (e evaluator) iri(iri schema.IRI) (string, error) {
+	switch {
+	case iri.Full != nil:
+		return string(*iri.Full), nil
+	case iri.Prefixed != nil:
+		prefix, local, _ := strings.Cut(string(*iri.Prefixed), ":")
+		namespace, ok := e.prefixes[prefix]
+		if !ok {
+			return "", fmt.Errorf("Prefix not found: %s", prefix) //nolint:stylecheck // as the contract reports it
+		}
+		return namespace + local, nil
+	default:
+		return "", errors.New("empty IRI")
+	}
+}
+
+// test evaluates the boolean expression against the solution, comparisons with unbound variables being false.
+//
+//nolint:cyclop
+func (e evaluator) test(x expression, s solution) (bool, error) {
+	switch {
+	case x.And != nil:
+		for _, operand := range x.And {
+			if ok, err := e.test(operand, s); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case x.Or != nil:
+		for _, operand := range x.Or {
+			if ok, err := e.test(operand, s); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case x.Not != nil:
+		ok, err := e.test(*x.Not, s)
+		return !ok, err
+	case x.Equal != nil:
+		return e.compare(x.Equal, s, func(a, b Term) bool { return a == b })
+	case x.Greater != nil:
+		return e.compare(x.Greater, s, func(a, b Term) bool { return order(a, b) > 0 })
+	case x.GreaterOrEqual != nil:
+		return e.compare(x.GreaterOrEqual, s, func(a, b Term) bool { return order(a, b) >= 0 })
+	case x.Less != nil:
+		return e.compare(x.Less, s, func(a, b Term) bool { return order(a, b) < 0 })
+	case x.LessOrEqual != nil:
+		return e.compare(x.LessOrEqual, s, func(a, b Term) bool { return order(a, b) <= 0 })
+	default:
+		return false, errors.New("expected a boolean expression")
+	}
+}
+
+func (e evaluator) compare(operands []expression, s solution, cmp func(a, b Term) bool) (bool, error) {
+	if len(operands) != 2 {
+		return false, errors.New("expected 2 operands to compare")
+	}
+
+	a, okA, err := e.term(operands[0], s)
+	if err != nil {
+		return false, err
+	}
+	b, okB, err := e.term(operands[1], s)
+	if err != nil {
+		return false, err
+	}
+
+	return okA && okB && cmp(a, b), nil
+}
+
+// term evaluates the operand of a comparison against the solution, returning false if it is an unbound variable.
+func (e evaluator) term(x expression, s solution) (Term, bool, error) {
+	switch {
+	case x.Variable != nil:
+		t, ok := s[*x.Variable]
+		return t, ok, nil
+	case x.NamedNode != nil:
+		iri, err := e.iri(*x.NamedNode)
+		return IRI(iri), true, err
+	case x.Literal != nil:
+		t, err := e.literal(*x.Literal)
+		return t, true, err
+	default:
+		return Term{}, false, errors.New("expected a variable, a named node or a literal to compare")
+	}
+}
+
+// order compares two terms, numerically if both are numbers, by their value otherwise.
+func order(a, b Term) int {
+	x, errA := strconv.ParseFloat(a.Value, 64)
+	y, errB := strconv.ParseFloat(b.Value, 64)
+	if a.kind == kindLiteral && b.kind == kindLiteral && errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	return strings.Compare(a.Value, b.Value)
+}
+
+// GovernanceCredential returns the triples of a governance text credential, of the given IRI, governing the resource
+// of the given DID by the law-stone contract at the given address, as found by the governance tool.
+func GovernanceCredential(credential, resource, lawStone string) []Triple {
+	gov := cognitarium.W3IDPrefix + "/schema/credential/governance/text/"
+	claim, governance := BlankNode(credential+"#claim"), BlankNode(credential+"#governance")
+
+	return []Triple{
+		{IRI(credential), IRI(string(cognitarium.VcBodySubject)), IRI(resource)},
+		{IRI(credential), IRI(string(cognitarium.VcBodyType)), IRI(gov + "GovernanceTextCredential")},
+		{IRI(credential), IRI(string(cognitarium.VcBodyClaim)), claim},
+		{claim, IRI(gov + "isGovernedBy"), governance},
+		{governance, IRI(gov + "fromGovernance"), IRI("cosmwasm:law-stone:" + lawStone)},
+	}
+}
@@ -0,0 +1,42 @@
+package fakenode
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	dataverseschema "github.com/axone-protocol/axone-contract-schema/go/dataverse-schema/v6"
+)
+
+// Dataverse is a simulated dataverse contract, answering the dataverse query.
+type Dataverse struct {
+	Name string
+	// TriplestoreAddress is the address of the cognitarium of the dataverse.
+	TriplestoreAddress string
+}
+
+// Query implements Contract.
+func (d Dataverse) Query(name string, _ json.RawMessage) (any, error) {
+	if name != "dataverse" {
+		return nil, unknownQuery(name)
+	}
+
+	return dataverseschema.DataverseResponse{
+		Name:               d.Name,
+		TriplestoreAddress: dataverseschema.Addr(d.TriplestoreAddress),
+	}, nil
+}
+
+// LawStone is a simulated law-stone contract, answering the program_code query.
+type LawStone struct {
+	// Program is the Prolog program of the law, answered base64 encoded.
+	Program string
+}
+
+// Query implements Contract.
+func (l LawStone) Query(name string, _ json.RawMessage) (any, error) {
+	if name != "program_code" {
+		return nil, unknownQuery(name)
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(l.Program)), nil
+}
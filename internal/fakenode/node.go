@@ -0,0 +1,181 @@
+package fakenode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	cmtproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultHeight is the latest block height of a node, unless given.
+const DefaultHeight = 1000
+
+// Contract is a smart contract simulated by the node, answering its smart queries.
+type Contract interface {
+	// Query answers the smart query of the given name, with the given JSON message, returning a value encoded in JSON.
+	Query(name string, msg json.RawMessage) (any, error)
+}
+
+// Node is a fake axone node serving the smart queries of simulated contracts over a real gRPC server, along with the
+// latest block and the health of the node. It is meant for integration tests, to be reached through the same client
+// connections as a real node, without TLS.
+type Node struct {
+	wasmtypes.UnimplementedQueryServer
+	cmtservice.UnimplementedServiceServer
+
+	server   *grpc.Server
+	listener net.Listener
+
+	mu        sync.RWMutex
+	contracts map[string]Contract
+	height    int64
+}
+
+// Option configures a Node.
+type Option func(*Node)
+
+// WithContract deploys the given contract at the given address.
+func WithContract(address string, contract Contract) Option {
+	return func(n *Node) {
+		n.contracts[address] = contract
+	}
+}
+
+// WithHeight sets the latest block height of the node.
+func WithHeight(height int64) Option {
+	return func(n *Node) {
+		n.height = height
+	}
+}
+
+// Start starts a node listening on a free local port, until stopped.
+func Start(opts ...Option) (*Node, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	n := &Node{
+		server:    grpc.NewServer(),
+		listener:  listener,
+		contracts: make(map[string]Contract),
+		height:    DefaultHeight,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	wasmtypes.RegisterQueryServer(n.server, n)
+	cmtservice.RegisterServiceServer(n.server, n)
+	healthpb.RegisterHealthServer(n.server, health.NewServer())
+	go func() {
+		_ = n.server.Serve(listener)
+	}()
+
+	return n, nil
+}
+
+// Addr returns the address <host>:<port> the node listens on.
+func (n *Node) Addr() string {
+	return n.listener.Addr().String()
+}
+
+// Stop stops the node, closing the connections of its clients.
+func (n *Node) Stop() {
+	n.server.Stop()
+}
+
+// Deploy deploys the given contract at the given address, replacing any contract already there.
+func (n *Node) Deploy(address string, contract Contract) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.contracts[address] = contract
+}
+
+// SetHeight sets the latest block height of the node.
+func (n *Node) SetHeight(height int64) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.height = height
+}
+
+// SmartContractState implements wasmtypes.QueryServer.
+//
+// The queries are answered at the block height they target, or else at the latest one, which is reported in the
+// response header. The state of the contracts does not depend on the height: a height beyond the latest one fails as
+// a real node would.
+func (n *Node) SmartContractState(
+	ctx context.Context, req *wasmtypes.QuerySmartContractStateRequest,
+) (*wasmtypes.QuerySmartContractStateResponse, error) {
+	n.mu.RLock()
+	contract, ok := n.contracts[req.Address]
+	latest := n.height
+	n.mu.RUnlock()
+
+	h := latest
+	if md, _ := metadata.FromIncomingContext(ctx); md != nil {
+		if requested, ok := height.FromMetadata(md); ok && requested > 0 {
+			h = requested
+		}
+	}
+	if h > latest {
+		return nil, status.Errorf(codes.InvalidArgument,
+			"failed to load state at height %d; version does not exist (latest height: %d)", h, latest)
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(height.MetadataKey, strconv.FormatInt(h, 10)))
+
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no such contract: %s", req.Address)
+	}
+
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(req.QueryData, &msg); err != nil || len(msg) != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid query %s: expected a single query", req.QueryData)
+	}
+	for name, m := range msg {
+		response, err := contract.Query(name, m)
+		if err != nil {
+			return nil, status.Errorf(codes.Unknown, "Generic error: Querier contract error: %s", err)
+		}
+		data, err := json.Marshal(response)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "encode response: %s", err)
+		}
+		return &wasmtypes.QuerySmartContractStateResponse{Data: data}, nil
+	}
+
+	return nil, nil //nolint:nilnil // unreachable
+}
+
+// GetLatestBlock implements cmtservice.ServiceServer, answering the height of the latest block only.
+func (n *Node) GetLatestBlock(
+	context.Context, *cmtservice.GetLatestBlockRequest,
+) (*cmtservice.GetLatestBlockResponse, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return &cmtservice.GetLatestBlockResponse{
+		SdkBlock: &cmtservice.Block{Header: cmtservice.Header{Height: n.height}},
+		Block:    &cmtproto.Block{Header: cmtproto.Header{Height: n.height}},
+	}, nil
+}
+
+// unknownQuery returns the error of a contract asked a query it does not know.
+func unknownQuery(name string) error {
+	return fmt.Errorf("unknown variant `%s`", name)
+}
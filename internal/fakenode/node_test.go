@@ -0,0 +1,230 @@
+package fakenode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	schema "github.com/axone-protocol/axone-contract-schema/go/cognitarium-schema/v6"
+	dataverseschema "github.com/axone-protocol/axone-contract-schema/go/dataverse-schema/v6"
+	lawstoneschema "github.com/axone-protocol/axone-contract-schema/go/law-stone-schema/v6"
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/axone-protocol/axone-mcp/internal/axone/dataverse"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/axone-protocol/axone-mcp/internal/axone/lawstone"
+	"github.com/cosmos/cosmos-sdk/client/grpc/cmtservice"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testDataverse   = "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"
+	testTriplestore = "axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"
+	testLawStone    = "axone1wlp9d7cqehpnq35wenwz7nq0yp4wpg5jgf4fcyqd7uqfpnagtvysjtfzkn"
+)
+
+func TestNode(t *testing.T) {
+	Convey("Given a node running simulated contracts", t, func() {
+		store := NewCognitarium(
+			Triple{IRI("https://ex.org/cred1"), IRI(string(cognitarium.VcBodySubject)), IRI("did:key:resource")},
+			Triple{IRI("https://ex.org/cred1"), IRI(string(cognitarium.VcBodyClaim)), BlankNode("c1")},
+			Triple{BlankNode("c1"), IRI("https://ex.org/title"), LangLiteral("Lune", "fr")},
+			Triple{BlankNode("c1"), IRI("https://ex.org/size"), TypedLiteral("12", "http://www.w3.org/2001/XMLSchema#integer")},
+			Triple{IRI("https://ex.org/cred2"), IRI(string(cognitarium.VcBodySubject)), IRI("did:key:other")},
+			Triple{IRI("https://ex.org/cred2"), IRI(string(cognitarium.VcBodyClaim)), BlankNode("c2")},
+			Triple{BlankNode("c2"), IRI("https://ex.org/title"), Literal("Moon")},
+			Triple{IRI("https://ex.org/cred0"), IRI(string(cognitarium.VcBodySubject)), IRI("did:key:first")},
+			Triple{IRI("https://ex.org/cred0"), IRI(string(cognitarium.VcBodyClaim)), BlankNode("c0")},
+			Triple{BlankNode("c0"), IRI("https://ex.org/title"), Literal("Sun")},
+		)
+		node, err := Start(
+			WithContract(testDataverse, Dataverse{Name: "my-dataverse", TriplestoreAddress: testTriplestore}),
+			WithContract(testTriplestore, store),
+			WithContract(testLawStone, LawStone{Program: "allow :- true."}),
+			WithHeight(42),
+		)
+		So(err, ShouldBeNil)
+		Reset(node.Stop)
+
+		cc, err := grpc.NewClient(node.Addr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		So(err, ShouldBeNil)
+		Reset(func() { _ = cc.Close() })
+		ctx := context.Background()
+
+		Convey("When querying the dataverse", func() {
+			var header metadata.MD
+			response, err := dataverse.Dataverse(ctx, cc, testDataverse, &dataverseschema.QueryMsg_Dataverse{},
+				grpc.Header(&header))
+
+			Convey("Then it should be answered at the latest height", func() {
+				So(err, ShouldBeNil)
+				So(response, ShouldResemble, &dataverseschema.DataverseResponse{
+					Name: "my-dataverse", TriplestoreAddress: testTriplestore,
+				})
+				h, ok := height.FromMetadata(header)
+				So(ok, ShouldBeTrue)
+				So(h, ShouldEqual, 42)
+			})
+		})
+
+		Convey("When querying the program of the law-stone", func() {
+			code, err := lawstone.ProgramCode(ctx, cc, testLawStone, &lawstoneschema.QueryMsg_ProgramCode{})
+
+			Convey("Then it should be answered base64 encoded", func() {
+				So(err, ShouldBeNil)
+				So(*code, ShouldEqual, "YWxsb3cgOi0gdHJ1ZS4=")
+			})
+		})
+
+		Convey("When selecting triples with a prefixed predicate", func() {
+			var query schema.SelectQuery
+			err := json.Unmarshal([]byte(`{
+				"prefixes": [{"prefix": "ex", "namespace": "https://ex.org/"}],
+				"select": [{"variable": "cred"}, {"variable": "title"}],
+				"where": {"bgp": {"patterns": [
+					{"subject": {"variable": "cred"}, "predicate": {"named_node": {"full": "dataverse:credential:body#claim"}},
+						"object": {"variable": "c"}},
+					{"subject": {"variable": "c"}, "predicate": {"named_node": {"prefixed": "ex:title"}},
+						"object": {"variable": "title"}}
+				]}}
+			}`), &query)
+			So(err, ShouldBeNil)
+			response, err := cognitarium.Select(ctx, cc, testTriplestore, &schema.QueryMsg_Select{Query: query})
+
+			Convey("Then the solutions should be answered in the order of the triples", func() {
+				So(err, ShouldBeNil)
+				So(response.Head.Vars, ShouldResemble, []string{"cred", "title"})
+				So(response.Results.Bindings, ShouldHaveLength, 3)
+				So(response.Results.Bindings[0]["title"].ValueType, ShouldResemble,
+					schema.Value_Literal{Type: "literal", Value: "Lune", Lang: ref("fr")})
+				So(response.Results.Bindings[1]["cred"].ValueType, ShouldResemble,
+					schema.URI{Type: "uri", Value: schema.IRI{Full: ref(schema.IRI_Full("https://ex.org/cred2"))}})
+			})
+		})
+
+		Convey("When selecting triples with a literal object and a limit", func() {
+			var query schema.SelectQuery
+			err := json.Unmarshal([]byte(`{
+				"limit": 1,
+				"select": [{"variable": "c"}],
+				"where": {"bgp": {"patterns": [
+					{"subject": {"variable": "c"}, "predicate": {"named_node": {"full": "https://ex.org/size"}},
+						"object": {"literal": {"typed_value": {
+							"datatype": {"full": "http://www.w3.org/2001/XMLSchema#integer"}, "value": "12"}}}}
+				]}}
+			}`), &query)
+			So(err, ShouldBeNil)
+			response, err := cognitarium.Select(ctx, cc, testTriplestore, &schema.QueryMsg_Select{Query: query})
+
+			Convey("Then the typed literal should be matched", func() {
+				So(err, ShouldBeNil)
+				So(response.Results.Bindings, ShouldHaveLength, 1)
+				So(response.Results.Bindings[0]["c"].ValueType, ShouldResemble, schema.BlankNode{Type: "blank_node", Value: "c1"})
+			})
+		})
+
+		Convey("When selecting triples with an undeclared prefix", func() {
+			_, err := cognitarium.Select(ctx, cc, testTriplestore, &schema.QueryMsg_Select{Query: schema.SelectQuery{
+				Select: []schema.SelectItem{{Variable: ref(schema.SelectItem_Variable("s"))}},
+				Where: schema.WhereClause{Bgp: &schema.WhereClause_Bgp{Patterns: []schema.TriplePattern{{
+					Subject: schema.VarOrNode{Variable: ref(schema.VarOrNode_Variable("s"))},
+					Predicate: schema.VarOrNamedNode{
+						NamedNode: &schema.VarOrNamedNode_NamedNode{Prefixed: ref(schema.IRI_Prefixed("ex:title"))},
+					},
+					Object: schema.VarOrNodeOrLiteral{Variable: ref(schema.VarOrNodeOrLiteral_Variable("o"))},
+				}}}},
+			}})
+
+			Convey("Then it should fail as the contract does", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEndWith, "code = Unknown desc = Generic error: Querier contract error: Prefix not found: ex")
+			})
+		})
+
+		Convey("When querying the literal claims page by page, the credentials being stored out of order", func() {
+			literals, err := cognitarium.GetClaimLiterals(ctx, cc, testTriplestore, 3)
+
+			Convey("Then all the literals should be answered", func() {
+				So(err, ShouldBeNil)
+				So(literals, ShouldHaveLength, 4)
+				So(literals, ShouldContain, cognitarium.ClaimLiteral{
+					Credential: "https://ex.org/cred0", Subject: "did:key:first",
+					Property: "https://ex.org/title", Value: "Sun",
+				})
+				So(literals, ShouldContain, cognitarium.ClaimLiteral{
+					Credential: "https://ex.org/cred1", Subject: "did:key:resource",
+					Property: "https://ex.org/title", Value: "Lune", Language: "fr",
+				})
+				So(literals, ShouldContain, cognitarium.ClaimLiteral{
+					Credential: "https://ex.org/cred1", Subject: "did:key:resource",
+					Property: "https://ex.org/size", Value: "12",
+				})
+				So(literals, ShouldContain, cognitarium.ClaimLiteral{
+					Credential: "https://ex.org/cred2", Subject: "did:key:other",
+					Property: "https://ex.org/title", Value: "Moon",
+				})
+			})
+		})
+
+		Convey("When querying the literal claims by pages a single credential fills", func() {
+			_, err := cognitarium.GetClaimLiterals(ctx, cc, testTriplestore, 2)
+
+			Convey("Then it should fail rather than leave claims out", func() {
+				So(errors.Is(err, cognitarium.ErrTooManyClaims), ShouldBeTrue)
+				So(err.Error(), ShouldContainSubstring, "the claims of https://ex.org/cred1 fill a page of 2 solutions")
+			})
+		})
+
+		Convey("When querying a contract not deployed", func() {
+			_, err := dataverse.Dataverse(ctx, cc, testTriplestore[:10], &dataverseschema.QueryMsg_Dataverse{})
+
+			Convey("Then it should not be found", func() {
+				So(err, ShouldNotBeNil)
+				So(status.Code(errors.Unwrap(err)), ShouldEqual, codes.NotFound)
+			})
+		})
+
+		Convey("When querying a contract with a query it does not know", func() {
+			_, err := dataverse.Dataverse(ctx, cc, testLawStone, &dataverseschema.QueryMsg_Dataverse{})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldContainSubstring, "unknown variant `dataverse`")
+			})
+		})
+
+		Convey("When querying a past height, then a future one", func() {
+			var header metadata.MD
+			_, errPast := dataverse.Dataverse(height.WithHeight(ctx, 10), cc, testDataverse,
+				&dataverseschema.QueryMsg_Dataverse{}, grpc.Header(&header))
+			_, errFuture := dataverse.Dataverse(height.WithHeight(ctx, 43), cc, testDataverse,
+				&dataverseschema.QueryMsg_Dataverse{})
+
+			Convey("Then the past height should be answered, and the future one reported as not available", func() {
+				So(errPast, ShouldBeNil)
+				h, _ := height.FromMetadata(header)
+				So(h, ShouldEqual, 10)
+				So(errors.Is(errFuture, height.ErrPruned), ShouldBeTrue)
+			})
+		})
+
+		Convey("When getting the latest block after a new one", func() {
+			node.SetHeight(43)
+			response, err := cmtservice.NewServiceClient(cc).GetLatestBlock(ctx, &cmtservice.GetLatestBlockRequest{})
+
+			Convey("Then its height should be answered", func() {
+				So(err, ShouldBeNil)
+				So(response.SdkBlock.Header.Height, ShouldEqual, 43)
+			})
+		})
+	})
+}
+
+func ref[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,233 @@
+package grpcpool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var ErrNoEndpoint = errors.New("no gRPC endpoint")
+
+// Config holds the pool settings.
+type Config struct {
+	// HealthCheckInterval is the interval between two health checks of the endpoints.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds the duration of a single health check.
+	HealthCheckTimeout time.Duration
+	// MaxAttempts is the maximum number of attempts of a call failing with codes.Unavailable, across the endpoints.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, doubled at each subsequent retry.
+	Backoff time.Duration
+	// MaxBackoff caps the delay between two retries.
+	MaxBackoff time.Duration
+}
+
+// DefaultConfig returns the default pool settings.
+func DefaultConfig() Config {
+	return Config{
+		HealthCheckInterval: 10 * time.Second,
+		HealthCheckTimeout:  2 * time.Second,
+		MaxAttempts:         3,
+		Backoff:             100 * time.Millisecond,
+		MaxBackoff:          2 * time.Second,
+	}
+}
+
+// Endpoint is a gRPC connection to one of the nodes.
+type Endpoint struct {
+	Address string
+	Conn    grpc.ClientConnInterface
+
+	healthy atomic.Bool
+	latency atomic.Int64
+}
+
+// Pool is a grpc.ClientConnInterface spreading the calls over several endpoints: calls go to the healthy endpoint with
+// the lowest latency, and are retried with backoff on the next best one when it is unavailable.
+type Pool struct {
+	config    Config
+	endpoints []*Endpoint
+	sleep     func(context.Context, time.Duration) error
+
+	mu      sync.RWMutex
+	ordered []*Endpoint
+
+	// closed is closed along with the pool, to stop its health checks.
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+var _ grpc.ClientConnInterface = (*Pool)(nil)
+
+// New creates a pool over the given connections, considered healthy until checked otherwise.
+func New(config Config, endpoints ...*Endpoint) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoint
+	}
+
+	for _, e := range endpoints {
+		e.healthy.Store(true)
+	}
+
+	return &Pool{
+		config:    config,
+		endpoints: endpoints,
+		ordered:   slices.Clone(endpoints),
+		sleep:     sleep,
+		closed:    make(chan struct{}),
+	}, nil
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (p *Pool) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	candidates := p.candidates()
+	attempts := max(p.config.MaxAttempts, 1)
+	backoff := p.config.Backoff
+
+	var err error
+	for attempt := range attempts {
+		if attempt > 0 {
+			if err := p.sleep(ctx, backoff); err != nil {
+				return err
+			}
+			backoff = min(backoff*2, p.config.MaxBackoff)
+		}
+
+		endpoint := candidates[attempt%len(candidates)]
+		err = endpoint.Conn.Invoke(ctx, method, args, reply, opts...)
+		if status.Code(err) != codes.Unavailable {
+			return err
+		}
+
+		log.Logger.Warn().
+			Str("endpoint", endpoint.Address).
+			Str("method", method).
+			Int("attempt", attempt+1).
+			Err(err).
+			Msg("gRPC endpoint unavailable")
+		p.markUnhealthy(endpoint)
+	}
+
+	return err
+}
+
+// NewStream implements grpc.ClientConnInterface. Streams are opened on the best endpoint and are not retried.
+func (p *Pool) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return p.candidates()[0].Conn.NewStream(ctx, desc, method, opts...)
+}
+
+// Run checks the health of the endpoints periodically, until the context is done or the pool is closed.
+func (p *Pool) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.closed:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth checks the health and measures the latency of all the endpoints, then reorders them accordingly.
+// Endpoints not implementing the gRPC health checking protocol are deemed healthy as long as they answer.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range p.endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.check(ctx, e)
+		}()
+	}
+	wg.Wait()
+
+	p.reorder()
+}
+
+// Close stops the health checks and closes the underlying connections.
+func (p *Pool) Close() error {
+	p.closeOnce.Do(func() { close(p.closed) })
+
+	return errors.Join(lo.FilterMap(p.endpoints, func(e *Endpoint, _ int) (error, bool) {
+		if closer, ok := e.Conn.(io.Closer); ok {
+			return closer.Close(), true
+		}
+		return nil, false
+	})...)
+}
+
+func (p *Pool) check(ctx context.Context, e *Endpoint) {
+	ctx, cancel := context.WithTimeout(ctx, p.config.HealthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(e.Conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	latency := time.Since(start)
+
+	healthy := status.Code(err) == codes.Unimplemented ||
+		(err == nil && resp.GetStatus() == healthpb.HealthCheckResponse_SERVING)
+	if e.healthy.Swap(healthy) != healthy {
+		log.Logger.Info().Str("endpoint", e.Address).Bool("healthy", healthy).Err(err).Msg("gRPC endpoint health changed")
+	}
+	e.latency.Store(int64(latency))
+}
+
+// candidates returns the endpoints ordered by preference: healthy ones first, by increasing latency.
+func (p *Pool) candidates() []*Endpoint {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.ordered
+}
+
+func (p *Pool) markUnhealthy(e *Endpoint) {
+	if e.healthy.Swap(false) {
+		p.reorder()
+	}
+}
+
+func (p *Pool) reorder() {
+	ordered := slices.Clone(p.endpoints)
+	slices.SortStableFunc(ordered, func(a, b *Endpoint) int {
+		if a.healthy.Load() != b.healthy.Load() {
+			return lo.Ternary(a.healthy.Load(), -1, 1)
+		}
+		return cmp.Compare(a.latency.Load(), b.latency.Load())
+	})
+
+	p.mu.Lock()
+	p.ordered = ordered
+	p.mu.Unlock()
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
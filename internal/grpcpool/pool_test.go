@@ -0,0 +1,152 @@
+package grpcpool
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+type fakeNode struct {
+	wasmtypes.UnimplementedQueryServer
+
+	name   string
+	server *grpc.Server
+	health *health.Server
+}
+
+func (n *fakeNode) SmartContractState(
+	_ context.Context, _ *wasmtypes.QuerySmartContractStateRequest,
+) (*wasmtypes.QuerySmartContractStateResponse, error) {
+	return &wasmtypes.QuerySmartContractStateResponse{Data: []byte(n.name)}, nil
+}
+
+func startNode(t *testing.T, name string, withHealth bool) (*fakeNode, *Endpoint) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := &fakeNode{name: name, server: grpc.NewServer(), health: health.NewServer()}
+	wasmtypes.RegisterQueryServer(node.server, node)
+	if withHealth {
+		healthpb.RegisterHealthServer(node.server, node.health)
+	}
+	go func() { _ = node.server.Serve(lis) }()
+	t.Cleanup(node.server.Stop)
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return node, &Endpoint{Address: lis.Addr().String(), Conn: conn}
+}
+
+func query(pool grpc.ClientConnInterface) (string, error) {
+	out := &wasmtypes.QuerySmartContractStateResponse{}
+	err := pool.Invoke(context.Background(), "/cosmwasm.wasm.v1.Query/SmartContractState",
+		&wasmtypes.QuerySmartContractStateRequest{Address: "axone1", QueryData: []byte("{}")}, out)
+	return string(out.Data), err
+}
+
+func TestPool(t *testing.T) {
+	Convey("Given a pool over several nodes", t, func() {
+		nodeA, endpointA := startNode(t, "a", true)
+		nodeB, endpointB := startNode(t, "b", false)
+
+		config := DefaultConfig()
+		config.Backoff = time.Millisecond
+		pool, err := New(config, endpointA, endpointB)
+		So(err, ShouldBeNil)
+
+		Convey("When all nodes are healthy", func() {
+			pool.CheckHealth(context.Background())
+
+			Convey("Then nodes without health service should be deemed healthy", func() {
+				So(endpointA.healthy.Load(), ShouldBeTrue)
+				So(endpointB.healthy.Load(), ShouldBeTrue)
+			})
+		})
+
+		Convey("When a node reports it is not serving", func() {
+			nodeA.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+			pool.CheckHealth(context.Background())
+
+			got, err := query(pool)
+
+			Convey("Then calls should go to the healthy node", func() {
+				So(err, ShouldBeNil)
+				So(got, ShouldEqual, "b")
+				So(pool.candidates()[0], ShouldEqual, endpointB)
+			})
+		})
+
+		Convey("When the preferred node goes down", func() {
+			pool.CheckHealth(context.Background())
+			preferred, other := pool.candidates()[0], pool.candidates()[1]
+			if preferred == endpointA {
+				nodeA.server.Stop()
+			} else {
+				nodeB.server.Stop()
+			}
+
+			got, err := query(pool)
+
+			Convey("Then the call should fail over to the other node", func() {
+				So(err, ShouldBeNil)
+				So(got, ShouldEqual, map[*Endpoint]string{endpointA: "a", endpointB: "b"}[other])
+				So(preferred.healthy.Load(), ShouldBeFalse)
+				So(pool.candidates()[0], ShouldEqual, other)
+			})
+		})
+
+		Convey("When the pool is closed while checking the health of the nodes", func() {
+			done := make(chan struct{})
+			go func() {
+				pool.Run(context.Background())
+				close(done)
+			}()
+			So(pool.Close(), ShouldBeNil)
+
+			Convey("Then the health checks should stop", func() {
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatal("health checks still running")
+				}
+			})
+		})
+
+		Convey("When all the nodes are down", func() {
+			nodeA.server.Stop()
+			nodeB.server.Stop()
+
+			_, err := query(pool)
+
+			Convey("Then the call should fail as unavailable after all attempts", func() {
+				So(status.Code(err), ShouldEqual, codes.Unavailable)
+			})
+		})
+	})
+
+	Convey("Given no endpoint", t, func() {
+		_, err := New(DefaultConfig())
+
+		Convey("Then the pool cannot be created", func() {
+			So(err, ShouldEqual, ErrNoEndpoint)
+		})
+	})
+}
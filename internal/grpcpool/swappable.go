@@ -0,0 +1,55 @@
+package grpcpool
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+)
+
+// Swappable is a grpc.ClientConnInterface forwarding the calls to a connection which can be replaced at any time, the
+// calls in flight completing on the replaced one.
+type Swappable struct {
+	current atomic.Pointer[swappableConn]
+}
+
+type swappableConn struct {
+	grpc.ClientConnInterface
+}
+
+var _ grpc.ClientConnInterface = (*Swappable)(nil)
+
+// NewSwappable creates a connection forwarding the calls to the given one, until swapped.
+func NewSwappable(cc grpc.ClientConnInterface) *Swappable {
+	s := &Swappable{}
+	s.current.Store(&swappableConn{cc})
+
+	return s
+}
+
+// Swap forwards the next calls to the given connection, and returns the replaced one.
+func (s *Swappable) Swap(cc grpc.ClientConnInterface) grpc.ClientConnInterface {
+	return s.current.Swap(&swappableConn{cc}).ClientConnInterface
+}
+
+// Close closes the current connection, if it can be closed.
+func (s *Swappable) Close() error {
+	if closer, ok := s.current.Load().ClientConnInterface.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (s *Swappable) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	return s.current.Load().Invoke(ctx, method, args, reply, opts...)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (s *Swappable) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return s.current.Load().NewStream(ctx, desc, method, opts...)
+}
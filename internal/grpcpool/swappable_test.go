@@ -0,0 +1,45 @@
+package grpcpool
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSwappable(t *testing.T) {
+	Convey("Given a swappable connection to a node", t, func() {
+		_, endpointA := startNode(t, "a", false)
+		_, endpointB := startNode(t, "b", false)
+		conn := NewSwappable(endpointA.Conn)
+
+		Convey("When calls are made before and after swapping it for another node", func() {
+			before, errBefore := query(conn)
+			previous := conn.Swap(endpointB.Conn)
+			after, errAfter := query(conn)
+
+			Convey("Then the calls should go to the node of the current connection", func() {
+				So(errBefore, ShouldBeNil)
+				So(before, ShouldEqual, "a")
+				So(errAfter, ShouldBeNil)
+				So(after, ShouldEqual, "b")
+				So(previous, ShouldEqual, endpointA.Conn)
+			})
+		})
+
+		Convey("When closed after being swapped for another node", func() {
+			conn.Swap(endpointB.Conn)
+			err := conn.Close()
+			_, errCurrent := query(conn)
+			previous, errPrevious := query(NewSwappable(endpointA.Conn))
+
+			Convey("Then the current connection only should be closed", func() {
+				So(err, ShouldBeNil)
+				So(status.Code(errCurrent), ShouldEqual, codes.Canceled)
+				So(errPrevious, ShouldBeNil)
+				So(previous, ShouldEqual, "a")
+			})
+		})
+	})
+}
@@ -0,0 +1,271 @@
+package inspect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/version"
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/client/transport"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Transports reaching an MCP server.
+const (
+	TransportAuto  = "auto"
+	TransportStdio = "stdio"
+	TransportSSE   = "sse"
+	TransportHTTP  = "http"
+)
+
+// Output formats of the inspector.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+var (
+	ErrUnknownTransport = errors.New("unknown transport")
+	ErrUnknownFormat    = errors.New("unknown output format")
+)
+
+// Target describes how to reach the inspected MCP server.
+type Target struct {
+	// Transport is the transport reaching the server, or auto to infer it from the address: an URL ending with /sse is
+	// reached over SSE, any other URL over streamable HTTP, and anything else is a command run over stdio.
+	Transport string
+	// Address is the URL of the server, or the command running it over stdio.
+	Address string
+	// Args are the arguments of the command running the server over stdio.
+	Args []string
+	// Headers are the HTTP headers sent to the server, e.g. an Authorization header.
+	Headers map[string]string
+	// Stderr receives the logs of the command running the server over stdio, discarded if nil.
+	Stderr io.Writer
+}
+
+// transport returns the transport reaching the target, inferred from its address if auto.
+func (t Target) transport() string {
+	if t.Transport != "" && t.Transport != TransportAuto {
+		return t.Transport
+	}
+
+	u, err := url.Parse(t.Address)
+	switch {
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https"):
+		return TransportStdio
+	case strings.HasSuffix(strings.TrimSuffix(u.Path, "/"), "/sse"):
+		return TransportSSE
+	default:
+		return TransportHTTP
+	}
+}
+
+// Connect starts a client of the target server, which must then be given to New.
+func Connect(ctx context.Context, target Target) (*client.Client, error) {
+	var (
+		t   transport.Interface
+		err error
+	)
+	switch kind := target.transport(); kind {
+	case TransportStdio:
+		t, err = startProcess(ctx, target)
+	case TransportSSE:
+		t, err = transport.NewSSE(target.Address, transport.WithHeaders(target.Headers))
+	case TransportHTTP:
+		t, err = transport.NewStreamableHTTP(target.Address, transport.WithHTTPHeaders(target.Headers))
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTransport, kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c := client.NewClient(t)
+	if err := c.Start(ctx); err != nil {
+		return nil, errors.Join(fmt.Errorf("connect to %s: %w", target.Address, err), t.Close())
+	}
+
+	return c, nil
+}
+
+// process is a server run over stdio by a command.
+type process struct {
+	*transport.Stdio
+	cmd *exec.Cmd
+}
+
+// startProcess starts the command running the target server over stdio.
+//
+// Unlike transport.NewStdio, the standard output of the command is not closed while being read once the command
+// exits, which would be reported on our own standard output.
+func startProcess(ctx context.Context, target Target) (*process, error) {
+	cmd := exec.CommandContext(ctx, target.Address, target.Args...)
+	cmd.Stderr = target.Stderr
+	stdinReader, stdin, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, errors.Join(err, stdinReader.Close(), stdin.Close())
+	}
+	cmd.Stdin, cmd.Stdout = stdinReader, stdoutWriter
+	err = cmd.Start()
+	// The ends of the pipes given to the command are its own once started, and of no use otherwise.
+	_ = stdinReader.Close()
+	_ = stdoutWriter.Close()
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("start %s: %w", target.Address, err), stdin.Close(), stdout.Close())
+	}
+
+	return &process{Stdio: transport.NewIO(stdout, stdin, io.NopCloser(strings.NewReader(""))), cmd: cmd}, nil
+}
+
+// Close closes the standard input of the command, and waits for it to exit.
+func (p *process) Close() error {
+	return errors.Join(p.Stdio.Close(), p.cmd.Wait())
+}
+
+// Inspector lists the features of an MCP server and calls its tools, printing what it gets in a given format.
+type Inspector struct {
+	client *client.Client
+	out    io.Writer
+	format string
+	server *mcp.InitializeResult
+}
+
+// New initializes a session with the server of the given started client, whose results are printed to out in the
+// given format.
+func New(ctx context.Context, c *client.Client, out io.Writer, format string) (*Inspector, error) {
+	format = strings.ToLower(format)
+	if format != FormatText && format != FormatJSON && format != FormatYAML {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: version.Name + "-inspect", Version: version.Version}
+	server, err := c.Initialize(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("initialize: %w", err)
+	}
+
+	return &Inspector{client: c, out: out, format: format, server: server}, nil
+}
+
+// Close ends the session, and stops the server if run over stdio.
+func (i *Inspector) Close() error {
+	return i.client.Close()
+}
+
+// Listing describes the features of a server.
+type Listing struct {
+	Server            mcp.Implementation     `json:"server"`
+	Instructions      string                 `json:"instructions,omitempty"`
+	Tools             []mcp.Tool             `json:"tools"`
+	Resources         []mcp.Resource         `json:"resources"`
+	ResourceTemplates []mcp.ResourceTemplate `json:"resourceTemplates"`
+	Prompts           []mcp.Prompt           `json:"prompts"`
+}
+
+// List prints the tools, resources and prompts of the server, along with their schemas.
+func (i *Inspector) List(ctx context.Context) error {
+	listing := Listing{Server: i.server.ServerInfo, Instructions: i.server.Instructions}
+	capabilities := i.server.Capabilities
+
+	if capabilities.Tools != nil {
+		result, err := i.client.ListTools(ctx, mcp.ListToolsRequest{})
+		if err != nil {
+			return fmt.Errorf("list tools: %w", err)
+		}
+		listing.Tools = result.Tools
+	}
+	if capabilities.Resources != nil {
+		result, err := i.client.ListResources(ctx, mcp.ListResourcesRequest{})
+		if err != nil {
+			return fmt.Errorf("list resources: %w", err)
+		}
+		listing.Resources = result.Resources
+
+		templates, err := i.client.ListResourceTemplates(ctx, mcp.ListResourceTemplatesRequest{})
+		if err != nil {
+			return fmt.Errorf("list resource templates: %w", err)
+		}
+		listing.ResourceTemplates = templates.ResourceTemplates
+	}
+	if capabilities.Prompts != nil {
+		result, err := i.client.ListPrompts(ctx, mcp.ListPromptsRequest{})
+		if err != nil {
+			return fmt.Errorf("list prompts: %w", err)
+		}
+		listing.Prompts = result.Prompts
+	}
+
+	return i.print(listing, func(w io.Writer) { renderListing(w, listing) })
+}
+
+// Call calls the given tool with the given arguments and prints its result, returned to tell whether it failed.
+func (i *Inspector) Call(ctx context.Context, name string, arguments map[string]any) (*mcp.CallToolResult, error) {
+	result, err := i.call(ctx, name, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, i.print(result, func(w io.Writer) { renderResult(w, result) })
+}
+
+func (i *Inspector) call(ctx context.Context, name string, arguments map[string]any) (*mcp.CallToolResult, error) {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	result, err := i.client.CallTool(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// Read reads the resource at the given URI and prints its contents.
+func (i *Inspector) Read(ctx context.Context, uri string) error {
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	result, err := i.client.ReadResource(ctx, request)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", uri, err)
+	}
+
+	return i.print(result, func(w io.Writer) { renderContents(w, result.Contents) })
+}
+
+// print prints the given value in the output format, using the given function for the text format.
+func (i *Inspector) print(v any, text func(w io.Writer)) error {
+	switch i.format {
+	case FormatJSON:
+		bz, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(i.out, string(bz))
+		return err
+	case FormatYAML:
+		bz, err := toYAML(v)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprint(i.out, string(bz))
+		return err
+	default:
+		text(i.out)
+		return nil
+	}
+}
@@ -0,0 +1,231 @@
+package inspect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func newTestServer() *server.MCPServer {
+	s := server.NewMCPServer("test-server", "1.0.0",
+		server.WithToolCapabilities(false),
+		server.WithResourceCapabilities(false, false))
+
+	s.AddTool(
+		mcp.NewTool("echo",
+			mcp.WithDescription("Echo a text."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithString("text", mcp.Required())),
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			text := request.GetString("text", "")
+			if text == "" {
+				result := mcp.NewToolResultError("empty text")
+				result.Meta = map[string]any{errorMetaKey: map[string]any{"code": "INVALID_TEXT", "hint": "Give a text."}}
+				return result, nil
+			}
+			return mcp.NewToolResultText(text), nil
+		})
+	s.AddResource(
+		mcp.NewResource("test://hello", "hello", mcp.WithMIMEType("text/plain")),
+		func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return []mcp.ResourceContents{
+				mcp.TextResourceContents{URI: request.Params.URI, MIMEType: "text/plain", Text: "Hello!"},
+			}, nil
+		})
+
+	return s
+}
+
+func newTestInspector(format string) (*Inspector, *bytes.Buffer) {
+	c, err := client.NewInProcessClient(newTestServer())
+	So(err, ShouldBeNil)
+	So(c.Start(context.Background()), ShouldBeNil)
+
+	var out bytes.Buffer
+	inspector, err := New(context.Background(), c, &out, format)
+	So(err, ShouldBeNil)
+	Reset(func() { _ = inspector.Close() })
+
+	return inspector, &out
+}
+
+func TestTargetTransport(t *testing.T) {
+	Convey("Given targets to inspect", t, func() {
+		tests := []struct {
+			target   Target
+			expected string
+		}{
+			{target: Target{Address: "axone-mcp", Args: []string{"serve", "stdio"}}, expected: TransportStdio},
+			{target: Target{Address: "http://localhost:8080/sse"}, expected: TransportSSE},
+			{target: Target{Address: "https://mcp.example.com/sse/"}, expected: TransportSSE},
+			{target: Target{Address: "https://mcp.example.com/mcp"}, expected: TransportHTTP},
+			{target: Target{Transport: TransportAuto, Address: "http://localhost:8080"}, expected: TransportHTTP},
+			{target: Target{Transport: TransportHTTP, Address: "http://localhost:8080/sse"}, expected: TransportHTTP},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Then the transport of %s should be %s", tt.target.Address, tt.expected), func() {
+				So(tt.target.transport(), ShouldEqual, tt.expected)
+			})
+		}
+
+		Convey("When connecting with an unknown transport", func() {
+			_, err := Connect(context.Background(), Target{Transport: "carrier-pigeon", Address: "coop"})
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldBeError, "unknown transport: carrier-pigeon")
+			})
+		})
+
+		Convey("When running a command which cannot be started", func() {
+			fds, err := os.ReadDir("/proc/self/fd")
+			if err != nil {
+				SkipSo(err, ShouldBeNil)
+				return
+			}
+			_, err = Connect(context.Background(), Target{Address: filepath.Join(t.TempDir(), "missing-server")})
+			after, _ := os.ReadDir("/proc/self/fd")
+
+			Convey("Then it should fail, without leaking the pipes to the command", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "start ")
+				So(after, ShouldHaveLength, len(fds))
+			})
+		})
+	})
+}
+
+func TestInspector(t *testing.T) {
+	Convey("Given an inspector of a server", t, func() {
+		Convey("When listing its features as text", func() {
+			inspector, out := newTestInspector(FormatText)
+			err := inspector.List(context.Background())
+
+			Convey("Then the tools and resources should be printed with their schemas", func() {
+				So(err, ShouldBeNil)
+				So(out.String(), ShouldStartWith, "test-server 1.0.0\n\nTools (1)\n  echo [read-only]\n    Echo a text.\n")
+				So(out.String(), ShouldContainSubstring, `      "required": [`+"\n"+`        "text"`)
+				So(out.String(), ShouldEndWith, "Resources (1)\n  test://hello  hello (text/plain)\n\nPrompts (0)\n")
+			})
+		})
+
+		Convey("When listing its features as JSON", func() {
+			inspector, out := newTestInspector(FormatJSON)
+			err := inspector.List(context.Background())
+
+			Convey("Then the listing should be printed as JSON", func() {
+				So(err, ShouldBeNil)
+				So(out.String(), ShouldStartWith, "{\n  \"server\": {\n    \"name\": \"test-server\",")
+				So(out.String(), ShouldContainSubstring, `"name": "echo"`)
+				So(out.String(), ShouldContainSubstring, `"uri": "test://hello"`)
+			})
+		})
+
+		Convey("When calling a tool failing with a code", func() {
+			inspector, out := newTestInspector(FormatText)
+			result, err := inspector.Call(context.Background(), "echo", map[string]any{"text": ""})
+
+			Convey("Then the error should be printed with its code and hint", func() {
+				So(err, ShouldBeNil)
+				So(result.IsError, ShouldBeTrue)
+				So(out.String(), ShouldEqual, "error INVALID_TEXT:\nempty text\nhint: Give a text.\n")
+			})
+		})
+
+		Convey("When reading a resource as YAML", func() {
+			inspector, out := newTestInspector(FormatYAML)
+			err := inspector.Read(context.Background(), "test://hello")
+
+			Convey("Then its contents should be printed as YAML", func() {
+				So(err, ShouldBeNil)
+				So(out.String(), ShouldEqual, "contents:\n- mimeType: text/plain\n  text: Hello!\n  uri: test://hello\n")
+			})
+		})
+
+		Convey("When running calls read from a file", func() {
+			inspector, out := newTestInspector(FormatText)
+			calls, err := ReadCalls(strings.NewReader(`[
+				{"name": "echo", "arguments": {"text": "{\"a\":1}"}},
+				{"name": "unknown"}
+			]`))
+			So(err, ShouldBeNil)
+			err = inspector.RunCalls(context.Background(), calls)
+
+			Convey("Then each result should be printed, and the failures counted", func() {
+				So(err, ShouldBeError, "calls failed: 1 of 2")
+				So(out.String(), ShouldStartWith, "=== echo\n{\n  \"a\": 1\n}\n=== unknown\nerror: call unknown: ")
+			})
+		})
+
+		Convey("When running an interactive session", func() {
+			inspector, out := newTestInspector(FormatText)
+			err := inspector.Interactive(context.Background(), strings.NewReader(
+				"call echo {\"text\": \"hi\"}\ncall echo {\ndance\nread test://hello\nquit\ncall echo {\"text\": \"never\"}\n"))
+
+			Convey("Then each command should be answered until quitting", func() {
+				So(err, ShouldBeNil)
+				So(out.String(), ShouldEqual, "Connected to test-server 1.0.0; type help for the commands.\n"+
+					"> hi\n"+
+					"> error: arguments must be a JSON object: unexpected end of JSON input\n"+
+					"> error: unknown command \"dance\"; type help for the commands\n"+
+					"> test://hello (text/plain)\nHello!\n"+
+					"> ")
+			})
+		})
+
+		Convey("When creating it with an unknown format", func() {
+			c, err := client.NewInProcessClient(newTestServer())
+			So(err, ShouldBeNil)
+			_, err = New(context.Background(), c, &bytes.Buffer{}, "xml")
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldBeError, "unknown output format: xml")
+			})
+		})
+	})
+}
+
+func TestReadCalls(t *testing.T) {
+	Convey("Given calls files", t, func() {
+		tests := []struct {
+			input    string
+			expected []Call
+			err      string
+		}{
+			{
+				input:    `{"name": "echo", "arguments": {"text": "hi"}}`,
+				expected: []Call{{Name: "echo", Arguments: map[string]any{"text": "hi"}}},
+			},
+			{
+				input:    `[{"name": "a"}, {"name": "b", "arguments": {}}]`,
+				expected: []Call{{Name: "a"}, {Name: "b", Arguments: map[string]any{}}},
+			},
+			{input: `[{"name": "a"}, {"arguments": {}}]`, err: "invalid calls: call #2 has no name"},
+			{input: `[{"name": "a"}`, err: "invalid calls: unexpected end of JSON input"},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("When reading %s", tt.input), func() {
+				calls, err := ReadCalls(strings.NewReader(tt.input))
+
+				Convey("Then the calls should be read", func() {
+					if tt.err != "" {
+						So(err, ShouldBeError, tt.err)
+					} else {
+						So(err, ShouldBeNil)
+						So(calls, ShouldResemble, tt.expected)
+					}
+				})
+			})
+		}
+	})
+}
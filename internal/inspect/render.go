@@ -0,0 +1,166 @@
+package inspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v2"
+)
+
+// errorMetaKey is the key of the result metadata holding the code and hint of a tool failure, if any.
+const errorMetaKey = "error"
+
+func renderListing(w io.Writer, listing Listing) {
+	fmt.Fprintf(w, "%s %s\n", listing.Server.Name, listing.Server.Version)
+	if listing.Instructions != "" {
+		fmt.Fprintf(w, "%s\n", listing.Instructions)
+	}
+
+	fmt.Fprintf(w, "\nTools (%d)\n", len(listing.Tools))
+	for _, tool := range listing.Tools {
+		fmt.Fprintf(w, "  %s%s\n", tool.Name, toolHints(tool.Annotations))
+		writeIndented(w, tool.Description, 4)
+		if schema, err := json.MarshalIndent(tool.InputSchema, "", "  "); err == nil {
+			writeIndented(w, string(schema), 4)
+		}
+	}
+
+	fmt.Fprintf(w, "\nResources (%d)\n", len(listing.Resources))
+	for _, resource := range listing.Resources {
+		fmt.Fprintf(w, "  %s  %s%s\n", resource.URI, resource.Name, mimeType(resource.MIMEType))
+		writeIndented(w, resource.Description, 4)
+	}
+	if len(listing.ResourceTemplates) > 0 {
+		fmt.Fprintf(w, "\nResource templates (%d)\n", len(listing.ResourceTemplates))
+		for _, template := range listing.ResourceTemplates {
+			fmt.Fprintf(w, "  %s  %s%s\n", template.URITemplate.Raw(), template.Name, mimeType(template.MIMEType))
+			writeIndented(w, template.Description, 4)
+		}
+	}
+
+	fmt.Fprintf(w, "\nPrompts (%d)\n", len(listing.Prompts))
+	for _, prompt := range listing.Prompts {
+		fmt.Fprintf(w, "  %s\n", prompt.Name)
+		writeIndented(w, prompt.Description, 4)
+		for _, arg := range prompt.Arguments {
+			fmt.Fprintf(w, "    - %s%s: %s\n", arg.Name, lo.Ternary(arg.Required, " (required)", ""), arg.Description)
+		}
+	}
+}
+
+// toolHints returns the behaviour hints of a tool worth knowing before calling it.
+func toolHints(annotations mcp.ToolAnnotation) string {
+	var hints []string
+	if lo.FromPtr(annotations.ReadOnlyHint) {
+		hints = append(hints, "read-only")
+	}
+	if lo.FromPtr(annotations.DestructiveHint) && !lo.FromPtr(annotations.ReadOnlyHint) {
+		hints = append(hints, "destructive")
+	}
+	if len(hints) == 0 {
+		return ""
+	}
+
+	return " [" + strings.Join(hints, ", ") + "]"
+}
+
+func mimeType(t string) string {
+	if t == "" {
+		return ""
+	}
+
+	return " (" + t + ")"
+}
+
+func renderResult(w io.Writer, result *mcp.CallToolResult) {
+	if result.IsError {
+		code, hint := "", ""
+		if meta, ok := result.Meta[errorMetaKey].(map[string]any); ok {
+			code, _ = meta["code"].(string)
+			hint, _ = meta["hint"].(string)
+		}
+		fmt.Fprintf(w, "error%s:\n", lo.Ternary(code != "", " "+code, ""))
+		renderContent(w, result.Content)
+		if hint != "" {
+			fmt.Fprintf(w, "hint: %s\n", hint)
+		}
+		return
+	}
+
+	renderContent(w, result.Content)
+	for _, key := range slices.Sorted(maps.Keys(result.Meta)) {
+		fmt.Fprintf(w, "%s: %v\n", key, result.Meta[key])
+	}
+}
+
+func renderContent(w io.Writer, content []mcp.Content) {
+	for _, c := range content {
+		switch c := c.(type) {
+		case mcp.TextContent:
+			fmt.Fprintln(w, prettyJSON(c.Text))
+		case mcp.ImageContent:
+			fmt.Fprintf(w, "[image %s, %d bytes base64 encoded]\n", c.MIMEType, len(c.Data))
+		case mcp.AudioContent:
+			fmt.Fprintf(w, "[audio %s, %d bytes base64 encoded]\n", c.MIMEType, len(c.Data))
+		case mcp.EmbeddedResource:
+			renderContents(w, []mcp.ResourceContents{c.Resource})
+		default:
+			bz, _ := json.MarshalIndent(c, "", "  ")
+			fmt.Fprintln(w, string(bz))
+		}
+	}
+}
+
+func renderContents(w io.Writer, contents []mcp.ResourceContents) {
+	for _, c := range contents {
+		switch c := c.(type) {
+		case mcp.TextResourceContents:
+			fmt.Fprintf(w, "%s%s\n%s\n", c.URI, mimeType(c.MIMEType), prettyJSON(c.Text))
+		case mcp.BlobResourceContents:
+			fmt.Fprintf(w, "%s%s\n[blob, %d bytes base64 encoded]\n", c.URI, mimeType(c.MIMEType), len(c.Blob))
+		}
+	}
+}
+
+// prettyJSON returns the given text indented if it is a JSON document, as is otherwise.
+func prettyJSON(text string) string {
+	var buf bytes.Buffer
+	if !json.Valid([]byte(text)) || json.Indent(&buf, []byte(text), "", "  ") != nil {
+		return text
+	}
+
+	return buf.String()
+}
+
+func writeIndented(w io.Writer, text string, indent int) {
+	if text == "" {
+		return
+	}
+
+	prefix := strings.Repeat(" ", indent)
+	for _, line := range strings.Split(text, "\n") {
+		fmt.Fprintf(w, "%s%s\n", prefix, line)
+	}
+}
+
+// toYAML marshals the given value in YAML, as it would be in JSON.
+func toYAML(v any) ([]byte, error) {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	if err := yaml.Unmarshal(bz, &value); err != nil {
+		return nil, err
+	}
+
+	return yaml.Marshal(value)
+}
@@ -0,0 +1,160 @@
+package inspect
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ErrCallsFailed is returned when some of the calls of a file failed.
+var ErrCallsFailed = errors.New("calls failed")
+
+// Call is a tool call read from a calls file.
+type Call struct {
+	Name      string         `json:"name"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+}
+
+// outcome is the outcome of a tool call read from a calls file, as printed in the JSON and YAML formats.
+type outcome struct {
+	Call
+	Result *mcp.CallToolResult `json:"result,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// ReadCalls reads the tool calls of a calls file: a JSON array of {"name": ..., "arguments": {...}} objects, or a
+// single one.
+func ReadCalls(r io.Reader) ([]Call, error) {
+	bz, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []Call
+	if bz = bytes.TrimSpace(bz); len(bz) > 0 && bz[0] == '{' {
+		calls = make([]Call, 1)
+		err = json.Unmarshal(bz, &calls[0])
+	} else {
+		err = json.Unmarshal(bz, &calls)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid calls: %w", err)
+	}
+	for n, call := range calls {
+		if call.Name == "" {
+			return nil, fmt.Errorf("invalid calls: call #%d has no name", n+1)
+		}
+	}
+
+	return calls, nil
+}
+
+// RunCalls makes the given tool calls in order, printing their results, and fails if any of them failed.
+func (i *Inspector) RunCalls(ctx context.Context, calls []Call) error {
+	outcomes := make([]outcome, 0, len(calls))
+	failed := 0
+	for _, call := range calls {
+		o := outcome{Call: call}
+		o.Result, o.Error = i.outcomeOf(ctx, call)
+		if o.Error != "" || o.Result.IsError {
+			failed++
+		}
+		outcomes = append(outcomes, o)
+
+		if i.format == FormatText {
+			fmt.Fprintf(i.out, "=== %s\n", call.Name)
+			if o.Error != "" {
+				fmt.Fprintf(i.out, "error: %s\n", o.Error)
+			} else {
+				renderResult(i.out, o.Result)
+			}
+		}
+	}
+
+	if i.format != FormatText {
+		if err := i.print(outcomes, nil); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%w: %d of %d", ErrCallsFailed, failed, len(calls))
+	}
+
+	return nil
+}
+
+func (i *Inspector) outcomeOf(ctx context.Context, call Call) (*mcp.CallToolResult, string) {
+	result, err := i.call(ctx, call.Name, call.Arguments)
+	if err != nil {
+		return nil, err.Error()
+	}
+
+	return result, ""
+}
+
+const interactiveHelp = `Commands:
+  list                          list the tools, resources and prompts
+  call <tool> [<arguments>]     call a tool, with its arguments as a JSON object
+  read <uri>                    read a resource
+  help                          print this help
+  quit                          end the session`
+
+// Interactive reads commands from in until it is exhausted or the quit command is given, printing the outcome of each.
+func (i *Inspector) Interactive(ctx context.Context, in io.Reader) error {
+	fmt.Fprintf(i.out, "Connected to %s %s; type help for the commands.\n", i.server.ServerInfo.Name,
+		i.server.ServerInfo.Version)
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1<<20)
+	for fmt.Fprint(i.out, "> "); scanner.Scan(); fmt.Fprint(i.out, "> ") {
+		command, rest, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		rest = strings.TrimSpace(rest)
+
+		var err error
+		switch command {
+		case "":
+		case "quit", "exit":
+			return nil
+		case "help":
+			fmt.Fprintln(i.out, interactiveHelp)
+		case "list":
+			err = i.List(ctx)
+		case "call":
+			err = i.interactiveCall(ctx, rest)
+		case "read":
+			err = i.Read(ctx, rest)
+		default:
+			err = fmt.Errorf("unknown command %q; type help for the commands", command)
+		}
+		if err != nil {
+			fmt.Fprintf(i.out, "error: %s\n", err)
+		}
+	}
+	fmt.Fprintln(i.out)
+
+	return scanner.Err()
+}
+
+func (i *Inspector) interactiveCall(ctx context.Context, args string) error {
+	name, rawArguments, _ := strings.Cut(args, " ")
+	if name == "" {
+		return errors.New("usage: call <tool> [<arguments>]")
+	}
+
+	var arguments map[string]any
+	if rawArguments = strings.TrimSpace(rawArguments); rawArguments != "" {
+		if err := json.Unmarshal([]byte(rawArguments), &arguments); err != nil {
+			return fmt.Errorf("arguments must be a JSON object: %w", err)
+		}
+	}
+
+	_, err := i.Call(ctx, name, arguments)
+	return err
+}
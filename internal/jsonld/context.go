@@ -0,0 +1,517 @@
+package jsonld
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrInvalidContext = errors.New("invalid JSON-LD context")
+	ErrUnsupported    = errors.New("unsupported JSON-LD feature")
+)
+
+// maxRemoteContexts bounds the number of remote contexts a single context may pull in, guarding against cycles.
+const maxRemoteContexts = 32
+
+var (
+	keywords = []string{
+		"@base", "@container", "@context", "@direction", "@graph", "@id", "@import", "@included", "@index", "@json",
+		"@language", "@list", "@nest", "@none", "@prefix", "@propagate", "@protected", "@reverse", "@set", "@type",
+		"@value", "@version", "@vocab",
+	}
+	keywordLike = regexp.MustCompile(`^@[a-zA-Z]+$`)
+	schemeRe    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+func isKeyword(s string) bool {
+	return slices.Contains(keywords, s)
+}
+
+// isAbsoluteIRI tells whether the given string is an absolute IRI, that is one with a scheme.
+func isAbsoluteIRI(s string) bool {
+	return schemeRe.MatchString(s) && !strings.ContainsAny(s, " <>\"{}|\\^`")
+}
+
+// termDefinition is the definition of a term within an active context.
+type termDefinition struct {
+	// id is the IRI, blank node or keyword the term expands to. It is empty when null is set.
+	id   string
+	null bool
+	// typ is the type the values of the term are coerced to, either an IRI or one of @id, @vocab, @json and @none.
+	typ        string
+	containers []string
+	// language is the default language of the values of the term, the empty string standing for no language.
+	language   *string
+	context    any
+	hasContext bool
+	protected  bool
+	simpleTerm bool
+}
+
+func (d *termDefinition) hasContainer(c string) bool {
+	return d != nil && slices.Contains(d.containers, c)
+}
+
+// sameAs tells whether the two definitions are identical, but for their protection.
+func (d *termDefinition) sameAs(o *termDefinition) bool {
+	a, b := *d, *o
+	a.protected, b.protected = false, false
+	a.simpleTerm, b.simpleTerm = false, false
+
+	return reflect.DeepEqual(a, b)
+}
+
+// activeContext holds the term definitions and defaults in effect while processing a node.
+type activeContext struct {
+	terms    map[string]*termDefinition
+	base     string
+	vocab    string
+	hasVocab bool
+	language string
+	// previous is the context to revert to when entering a node object, set by non propagated type-scoped contexts.
+	previous *activeContext
+}
+
+func newActiveContext() *activeContext {
+	return &activeContext{terms: map[string]*termDefinition{}}
+}
+
+func (a *activeContext) clone() *activeContext {
+	c := *a
+	c.terms = make(map[string]*termDefinition, len(a.terms))
+	for k, v := range a.terms {
+		c.terms[k] = v
+	}
+
+	return &c
+}
+
+// contextProcessor processes local contexts against active contexts, loading remote contexts on demand.
+type contextProcessor struct {
+	ctx    context.Context
+	loader Loader
+}
+
+// process applies the given local context to the active context, as defined by the JSON-LD 1.1 Context Processing
+// algorithm.
+func (p *contextProcessor) process(active *activeContext, local any, propagate, overrideProtected bool) (
+	*activeContext, error,
+) {
+	return p.processWith(active, local, propagate, overrideProtected, nil)
+}
+
+func (p *contextProcessor) processWith(
+	active *activeContext, local any, propagate, overrideProtected bool, remotes []string,
+) (*activeContext, error) {
+	if m, ok := local.(map[string]any); ok {
+		if v, ok := m["@propagate"]; ok {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("%w: @propagate must be a boolean", ErrInvalidContext)
+			}
+			propagate = b
+		}
+	}
+
+	result := active.clone()
+	if !propagate && result.previous == nil {
+		result.previous = active
+	}
+
+	for _, item := range asArray(local) {
+		var err error
+		switch item := item.(type) {
+		case nil:
+			if !overrideProtected && slices.ContainsFunc(mapValues(result.terms), func(d *termDefinition) bool {
+				return d.protected
+			}) {
+				return nil, fmt.Errorf("%w: cannot reset a context holding protected terms", ErrInvalidContext)
+			}
+			reset := newActiveContext()
+			reset.base = result.base
+			if !propagate {
+				reset.previous = result
+			}
+			result = reset
+		case string:
+			result, err = p.processRemote(result, item, overrideProtected, remotes)
+		case map[string]any:
+			err = p.processDefinitions(result, item, overrideProtected)
+		default:
+			err = fmt.Errorf("%w: a context must be an object, a string or null", ErrInvalidContext)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (p *contextProcessor) processRemote(
+	active *activeContext, ref string, overrideProtected bool, remotes []string,
+) (*activeContext, error) {
+	u := ref
+	if active.base != "" {
+		if resolved, err := resolveIRI(active.base, ref); err == nil {
+			u = resolved
+		}
+	}
+	if !isAbsoluteIRI(u) {
+		return nil, fmt.Errorf("%w: context reference %q is not an absolute IRI", ErrInvalidContext, ref)
+	}
+	if slices.Contains(remotes, u) || len(remotes) >= maxRemoteContexts {
+		return nil, fmt.Errorf("%w: recursive inclusion of context %s", ErrInvalidContext, u)
+	}
+
+	doc, err := p.loader.Load(p.ctx, u)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := doc.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: remote context %s is not an object", ErrInvalidContext, u)
+	}
+	local, ok := m["@context"]
+	if !ok {
+		return nil, fmt.Errorf("%w: remote context %s has no @context", ErrInvalidContext, u)
+	}
+
+	return p.processWith(active, local, true, overrideProtected, append(slices.Clone(remotes), u))
+}
+
+// processDefinitions applies the given context definition to the active context, in place.
+func (p *contextProcessor) processDefinitions(active *activeContext, local map[string]any, overrideProtected bool) error {
+	if v, ok := local["@version"]; ok && v != 1.1 {
+		return fmt.Errorf("%w: unsupported @version %v", ErrInvalidContext, v)
+	}
+	if _, ok := local["@import"]; ok {
+		return fmt.Errorf("%w: @import", ErrUnsupported)
+	}
+	if v, ok := local["@base"]; ok {
+		switch v := v.(type) {
+		case nil:
+			active.base = ""
+		case string:
+			if active.base != "" {
+				resolved, err := resolveIRI(active.base, v)
+				if err != nil {
+					return fmt.Errorf("%w: invalid @base %q", ErrInvalidContext, v)
+				}
+				v = resolved
+			}
+			active.base = v
+		default:
+			return fmt.Errorf("%w: @base must be a string or null", ErrInvalidContext)
+		}
+	}
+	if v, ok := local["@vocab"]; ok {
+		switch v := v.(type) {
+		case nil:
+			active.vocab, active.hasVocab = "", false
+		case string:
+			vocab, ok := expandIRI(active, v, true, true, nil, nil)
+			if !ok || !(isAbsoluteIRI(vocab) || strings.HasPrefix(vocab, "_:")) {
+				return fmt.Errorf("%w: invalid @vocab %q", ErrInvalidContext, v)
+			}
+			active.vocab, active.hasVocab = vocab, true
+		default:
+			return fmt.Errorf("%w: @vocab must be a string or null", ErrInvalidContext)
+		}
+	}
+	if v, ok := local["@language"]; ok {
+		switch v := v.(type) {
+		case nil:
+			active.language = ""
+		case string:
+			active.language = strings.ToLower(v)
+		default:
+			return fmt.Errorf("%w: @language must be a string or null", ErrInvalidContext)
+		}
+	}
+
+	protected := false
+	if v, ok := local["@protected"]; ok {
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%w: @protected must be a boolean", ErrInvalidContext)
+		}
+		protected = b
+	}
+
+	defined := map[string]bool{}
+	for _, term := range sortedKeys(local) {
+		switch term {
+		case "@base", "@direction", "@import", "@language", "@propagate", "@protected", "@version", "@vocab":
+			continue
+		}
+		if err := createTerm(active, local, term, defined, protected, overrideProtected); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createTerm defines the given term of the local context in the active context, as defined by the JSON-LD 1.1 Create
+// Term Definition algorithm.
+//
+//nolint:funlen,gocognit,cyclop // follows the steps of the specification.
+func createTerm(
+	active *activeContext, local map[string]any, term string, defined map[string]bool, protectedDefault, overrideProtected bool,
+) error {
+	if done, ok := defined[term]; ok {
+		if !done {
+			return fmt.Errorf("%w: cyclic definition of term %q", ErrInvalidContext, term)
+		}
+		return nil
+	}
+	defined[term] = false
+	defer func() { defined[term] = true }()
+
+	if term == "" {
+		return fmt.Errorf("%w: empty term", ErrInvalidContext)
+	}
+	if isKeyword(term) {
+		return fmt.Errorf("%w: keyword %s cannot be redefined", ErrInvalidContext, term)
+	}
+	if keywordLike.MatchString(term) {
+		return nil
+	}
+
+	previous := active.terms[term]
+	delete(active.terms, term)
+
+	def := &termDefinition{protected: protectedDefault}
+	var value map[string]any
+	switch v := local[term].(type) {
+	case nil:
+		value = map[string]any{"@id": nil}
+	case string:
+		value = map[string]any{"@id": v}
+		def.simpleTerm = true
+	case map[string]any:
+		value = v
+	default:
+		return fmt.Errorf("%w: definition of term %q must be an object, a string or null", ErrInvalidContext, term)
+	}
+
+	if v, ok := value["@protected"]; ok {
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%w: @protected of term %q must be a boolean", ErrInvalidContext, term)
+		}
+		def.protected = b
+	}
+
+	if v, ok := value["@type"]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%w: @type of term %q must be a string", ErrInvalidContext, term)
+		}
+		typ, ok := expandIRI(active, s, false, true, local, defined)
+		if !ok || (typ != "@id" && typ != "@vocab" && typ != "@json" && typ != "@none" && !isAbsoluteIRI(typ)) {
+			return fmt.Errorf("%w: invalid @type %q of term %q", ErrInvalidContext, s, term)
+		}
+		def.typ = typ
+	}
+
+	if _, ok := value["@reverse"]; ok {
+		return fmt.Errorf("%w: @reverse term %q", ErrUnsupported, term)
+	}
+	if _, ok := value["@nest"]; ok {
+		return fmt.Errorf("%w: @nest term %q", ErrUnsupported, term)
+	}
+
+	switch id, hasID := value["@id"]; {
+	case hasID && id == nil:
+		def.null = true
+	case hasID && id != term:
+		s, ok := id.(string)
+		if !ok {
+			return fmt.Errorf("%w: @id of term %q must be a string", ErrInvalidContext, term)
+		}
+		if !isKeyword(s) && keywordLike.MatchString(s) {
+			def.null = true
+			break
+		}
+		expanded, ok := expandIRI(active, s, false, true, local, defined)
+		if !ok || !(isKeyword(expanded) || isAbsoluteIRI(expanded) || strings.HasPrefix(expanded, "_:")) {
+			return fmt.Errorf("%w: invalid @id %q of term %q", ErrInvalidContext, s, term)
+		}
+		if expanded == "@context" {
+			return fmt.Errorf("%w: term %q cannot be an alias of @context", ErrInvalidContext, term)
+		}
+		def.id = expanded
+	default:
+		prefix, suffix, isCompact := strings.Cut(term, ":")
+		switch {
+		case isCompact && prefix != "" && !strings.HasPrefix(suffix, "//"):
+			if _, ok := local[prefix]; ok {
+				if err := createTerm(active, local, prefix, defined, protectedDefault, overrideProtected); err != nil {
+					return err
+				}
+			}
+			if p, ok := active.terms[prefix]; ok && !p.null {
+				def.id = p.id + suffix
+			} else {
+				def.id = term
+			}
+		case isCompact:
+			def.id = term
+		case active.hasVocab:
+			def.id = active.vocab + term
+		default:
+			return fmt.Errorf("%w: term %q has no IRI mapping", ErrInvalidContext, term)
+		}
+	}
+
+	if v, ok := value["@container"]; ok {
+		for _, c := range asArray(v) {
+			s, ok := c.(string)
+			if !ok {
+				return fmt.Errorf("%w: @container of term %q must hold strings", ErrInvalidContext, term)
+			}
+			switch s {
+			case "@list", "@set", "@index", "@language", "@graph":
+				def.containers = append(def.containers, s)
+			case "@id", "@type":
+				return fmt.Errorf("%w: %s maps of term %q", ErrUnsupported, s, term)
+			default:
+				return fmt.Errorf("%w: invalid @container %q of term %q", ErrInvalidContext, s, term)
+			}
+		}
+		slices.Sort(def.containers)
+	}
+
+	if v, ok := value["@context"]; ok {
+		def.context, def.hasContext = v, true
+	}
+
+	if v, ok := value["@language"]; ok {
+		switch v := v.(type) {
+		case nil:
+			empty := ""
+			def.language = &empty
+		case string:
+			lang := strings.ToLower(v)
+			def.language = &lang
+		default:
+			return fmt.Errorf("%w: @language of term %q must be a string or null", ErrInvalidContext, term)
+		}
+	}
+
+	if !overrideProtected && previous != nil && previous.protected {
+		if !previous.sameAs(def) {
+			return fmt.Errorf("%w: protected term %q cannot be redefined", ErrInvalidContext, term)
+		}
+		def = previous
+	}
+	active.terms[term] = def
+
+	return nil
+}
+
+// expandIRI expands the given value to an IRI, a blank node identifier or a keyword, as defined by the JSON-LD 1.1 IRI
+// Expansion algorithm. It returns false when the value expands to null.
+func expandIRI(
+	active *activeContext, value string, documentRelative, vocab bool, local map[string]any, defined map[string]bool,
+) (string, bool) {
+	if isKeyword(value) {
+		return value, true
+	}
+	if keywordLike.MatchString(value) {
+		return "", false
+	}
+
+	if local != nil {
+		if _, ok := local[value]; ok {
+			if done, ok := defined[value]; !ok || !done {
+				_ = createTerm(active, local, value, defined, false, false)
+			}
+		}
+	}
+
+	if def, ok := active.terms[value]; ok && (vocab || isKeyword(def.id)) {
+		if def.null {
+			return "", false
+		}
+		return def.id, true
+	}
+
+	if prefix, suffix, ok := strings.Cut(value, ":"); ok && prefix != "" {
+		if prefix == "_" || strings.HasPrefix(suffix, "//") {
+			return value, true
+		}
+		if local != nil {
+			if _, ok := local[prefix]; ok {
+				if done, ok := defined[prefix]; !ok || !done {
+					_ = createTerm(active, local, prefix, defined, false, false)
+				}
+			}
+		}
+		if def, ok := active.terms[prefix]; ok && !def.null && def.id != "" {
+			return def.id + suffix, true
+		}
+		if isAbsoluteIRI(value) {
+			return value, true
+		}
+	}
+
+	if vocab && active.hasVocab {
+		return active.vocab + value, true
+	}
+	if documentRelative && active.base != "" {
+		if resolved, err := resolveIRI(active.base, value); err == nil {
+			return resolved, true
+		}
+	}
+
+	return value, true
+}
+
+func resolveIRI(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return b.ResolveReference(r).String(), nil
+}
+
+func asArray(v any) []any {
+	if a, ok := v.([]any); ok {
+		return a
+	}
+
+	return []any{v}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func mapValues[V any](m map[string]V) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+
+	return values
+}
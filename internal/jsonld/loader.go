@@ -0,0 +1,184 @@
+package jsonld
+
+import (
+	"container/list"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/axone-protocol/axone-mcp/internal/publichttp"
+)
+
+const (
+	ContextCredentialsV1 = "https://www.w3.org/2018/credentials/v1"
+	ContextEd25519V1     = "https://w3id.org/security/suites/ed25519-2020/v1"
+)
+
+var ErrContextNotFound = errors.New("JSON-LD context not found")
+
+//go:embed contexts/*.jsonld
+var contexts embed.FS
+
+// embedded maps the URL of the well-known contexts to their file in contexts.
+var embedded = map[string]string{
+	ContextCredentialsV1: "contexts/credentials-v1.jsonld",
+	ContextEd25519V1:     "contexts/ed25519-2020-v1.jsonld",
+}
+
+// Loader retrieves the remote contexts referenced by JSON-LD documents.
+type Loader interface {
+	// Load returns the parsed document at the given URL.
+	Load(ctx context.Context, url string) (any, error)
+}
+
+// EmbeddedLoader is a Loader serving the well-known contexts bundled with the binary, falling back to another
+// Loader, if any, for the others.
+type EmbeddedLoader struct {
+	Fallback Loader
+}
+
+// Load implements Loader.
+func (l *EmbeddedLoader) Load(ctx context.Context, url string) (any, error) {
+	if name, ok := embedded[url]; ok {
+		bz, err := contexts.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		var doc any
+		if err := json.Unmarshal(bz, &doc); err != nil {
+			return nil, fmt.Errorf("load %s: %w", url, err)
+		}
+		return doc, nil
+	}
+	if l.Fallback != nil {
+		return l.Fallback.Load(ctx, url)
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrContextNotFound, url)
+}
+
+// DefaultMaxCached is the number of fetched contexts an HTTPLoader keeps in memory by default.
+const DefaultMaxCached = 64
+
+// HTTPLoader is a Loader fetching the contexts over HTTPS, keeping the most recently used ones in memory once fetched.
+type HTTPLoader struct {
+	Client *http.Client
+	// MaxSize is the maximum size in bytes of a fetched context.
+	MaxSize int64
+	// MaxCached is the maximum number of contexts kept in memory.
+	MaxCached int
+
+	mu    sync.Mutex
+	lru   *list.List
+	cache map[string]*list.Element
+}
+
+type cached struct {
+	url string
+	doc any
+}
+
+// NewHTTPLoader returns an HTTPLoader with sensible timeout, size and cache limits, which does not follow redirects
+// and only connects to public addresses, the contexts being referenced by the documents of the callers.
+func NewHTTPLoader() *HTTPLoader {
+	return &HTTPLoader{
+		Client:    publichttp.NewClient(),
+		MaxSize:   1 << 20,
+		MaxCached: DefaultMaxCached,
+	}
+}
+
+// Load implements Loader.
+func (l *HTTPLoader) Load(ctx context.Context, url string) (any, error) {
+	if !strings.HasPrefix(url, "https://") {
+		return nil, fmt.Errorf("%w: %s: only https contexts can be fetched", ErrContextNotFound, url)
+	}
+
+	if doc, ok := l.get(url); ok {
+		return doc, nil
+	}
+
+	doc, err := l.fetch(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	l.put(url, doc)
+
+	return doc, nil
+}
+
+func (l *HTTPLoader) get(url string) (any, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.cache[url]
+	if !ok {
+		return nil, false
+	}
+	l.lru.MoveToFront(elem)
+
+	return elem.Value.(*cached).doc, true //nolint:forcetypeassert
+}
+
+func (l *HTTPLoader) put(url string, doc any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cache == nil {
+		l.lru, l.cache = list.New(), make(map[string]*list.Element)
+	}
+	if elem, ok := l.cache[url]; ok {
+		l.lru.MoveToFront(elem)
+		return
+	}
+
+	l.cache[url] = l.lru.PushFront(&cached{url: url, doc: doc})
+	for l.MaxCached > 0 && l.lru.Len() > l.MaxCached {
+		oldest := l.lru.Back()
+		l.lru.Remove(oldest)
+		delete(l.cache, oldest.Value.(*cached).url) //nolint:forcetypeassert
+	}
+}
+
+func (l *HTTPLoader) fetch(ctx context.Context, url string) (any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/ld+json, application/json")
+
+	resp, err := l.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%w: %s", ErrContextNotFound, url)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("load %s: unexpected status %s", url, resp.Status)
+	}
+
+	bz, err := io.ReadAll(io.LimitReader(resp.Body, l.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(bz)) > l.MaxSize {
+		return nil, fmt.Errorf("load %s: context exceeds %d bytes", url, l.MaxSize)
+	}
+
+	var doc any
+	if err := json.Unmarshal(bz, &doc); err != nil {
+		return nil, fmt.Errorf("load %s: %w", url, err)
+	}
+
+	return doc, nil
+}
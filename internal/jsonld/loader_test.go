@@ -0,0 +1,60 @@
+package jsonld
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/publichttp"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHTTPLoader(t *testing.T) {
+	Convey("Given a server publishing contexts", t, func() {
+		var fetched atomic.Int32
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetched.Add(1)
+			if r.URL.Path == "/redirected" {
+				http.Redirect(w, r, "/context", http.StatusFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"@context": {"@vocab": "https://ex.org/"}}`))
+		}))
+		Reset(srv.Close)
+
+		client := srv.Client()
+		client.CheckRedirect = publichttp.NoRedirect
+		loader := &HTTPLoader{Client: client, MaxSize: 1 << 10, MaxCached: 2}
+
+		Convey("When loading more contexts than it can keep in memory", func() {
+			for _, path := range []string{"/a", "/b", "/a", "/c", "/a", "/b"} {
+				_, err := loader.Load(context.Background(), srv.URL+path)
+				So(err, ShouldBeNil)
+			}
+
+			Convey("Then the least recently used ones should be fetched again", func() {
+				So(fetched.Load(), ShouldEqual, 4)
+				So(loader.cache, ShouldHaveLength, 2)
+			})
+		})
+
+		Convey("When loading a redirected context", func() {
+			_, err := loader.Load(context.Background(), srv.URL+"/redirected")
+
+			Convey("Then the redirect should not be followed", func() {
+				So(err, ShouldBeError, "load "+srv.URL+"/redirected: unexpected status 302 Found")
+			})
+		})
+
+		Convey("When loading a context on a loopback address with the default loader", func() {
+			_, err := NewHTTPLoader().Load(context.Background(), srv.URL+"/context")
+
+			Convey("Then the connection should be refused", func() {
+				So(errors.Is(err, publichttp.ErrForbiddenAddress), ShouldBeTrue)
+			})
+		})
+	})
+}
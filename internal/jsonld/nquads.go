@@ -0,0 +1,123 @@
+package jsonld
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	XSDString     = "http://www.w3.org/2001/XMLSchema#string"
+	XSDBoolean    = "http://www.w3.org/2001/XMLSchema#boolean"
+	XSDInteger    = "http://www.w3.org/2001/XMLSchema#integer"
+	XSDDouble     = "http://www.w3.org/2001/XMLSchema#double"
+	RDFType       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"
+	RDFFirst      = "http://www.w3.org/1999/02/22-rdf-syntax-ns#first"
+	RDFRest       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#rest"
+	RDFNil        = "http://www.w3.org/1999/02/22-rdf-syntax-ns#nil"
+	RDFLangString = "http://www.w3.org/1999/02/22-rdf-syntax-ns#langString"
+	RDFJSON       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#JSON"
+)
+
+// TermKind is the kind of an RDF term.
+type TermKind int
+
+const (
+	IRI TermKind = iota
+	BlankNode
+	Literal
+)
+
+// Term is an RDF term. The zero value, an IRI with no value, stands for the default graph.
+type Term struct {
+	Kind  TermKind
+	Value string
+	// Datatype is the datatype IRI of a literal.
+	Datatype string
+	// Language is the language tag of a literal.
+	Language string
+}
+
+// NewIRI returns the IRI term with the given value.
+func NewIRI(value string) Term {
+	return Term{Kind: IRI, Value: value}
+}
+
+// NewBlankNode returns the blank node term with the given label, without the _: prefix.
+func NewBlankNode(label string) Term {
+	return Term{Kind: BlankNode, Value: label}
+}
+
+// NewLiteral returns the literal term with the given lexical form and datatype.
+func NewLiteral(value, datatype string) Term {
+	return Term{Kind: Literal, Value: value, Datatype: datatype}
+}
+
+// IsDefaultGraph tells whether the term stands for the default graph.
+func (t Term) IsDefaultGraph() bool {
+	return t.Kind == IRI && t.Value == ""
+}
+
+// String returns the N-Quads serialization of the term.
+func (t Term) String() string {
+	switch t.Kind {
+	case BlankNode:
+		return "_:" + t.Value
+	case Literal:
+		s := `"` + escape(t.Value) + `"`
+		switch {
+		case t.Language != "":
+			return s + "@" + t.Language
+		case t.Datatype != "" && t.Datatype != XSDString:
+			return s + "^^<" + t.Datatype + ">"
+		default:
+			return s
+		}
+	default:
+		return "<" + t.Value + ">"
+	}
+}
+
+// Quad is an RDF statement, in the default graph if its graph is the zero Term.
+type Quad struct {
+	Subject   Term
+	Predicate Term
+	Object    Term
+	Graph     Term
+}
+
+// String returns the N-Quads serialization of the quad, new line included.
+func (q Quad) String() string {
+	var sb strings.Builder
+	sb.WriteString(q.Subject.String())
+	sb.WriteByte(' ')
+	sb.WriteString(q.Predicate.String())
+	sb.WriteByte(' ')
+	sb.WriteString(q.Object.String())
+	if !q.Graph.IsDefaultGraph() {
+		sb.WriteByte(' ')
+		sb.WriteString(q.Graph.String())
+	}
+	sb.WriteString(" .\n")
+
+	return sb.String()
+}
+
+// Dataset is a set of quads.
+type Dataset []Quad
+
+// NQuads returns the N-Quads serialization of the dataset, with its lines sorted.
+func (d Dataset) NQuads() string {
+	lines := make([]string, 0, len(d))
+	for _, q := range d {
+		lines = append(lines, q.String())
+	}
+	sort.Strings(lines)
+
+	return strings.Join(lines, "")
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+func escape(s string) string {
+	return escaper.Replace(s)
+}
@@ -0,0 +1,577 @@
+package jsonld
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidDocument = errors.New("invalid JSON-LD document")
+
+// ToRDF converts the given JSON-LD document, as decoded by encoding/json, to an RDF dataset, as the JSON-LD 1.1
+// Deserialization algorithm would on its expanded form.
+//
+// Only the subset of JSON-LD needed by Verifiable Credentials is supported: term definitions, @vocab, compact IRIs,
+// type coercion, language and value objects, lists, sets, indexes, named graphs and property or type scoped contexts.
+// The documents relying on other features are rejected with ErrUnsupported.
+func ToRDF(ctx context.Context, doc any, loader Loader) (Dataset, error) {
+	c := &converter{
+		contexts: &contextProcessor{ctx: ctx, loader: loader},
+		blanks:   map[string]string{},
+		seen:     map[Quad]struct{}{},
+	}
+
+	for _, item := range asArray(doc) {
+		node, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%w: top level value must be an object", ErrInvalidDocument)
+		}
+
+		active, nodes, err := c.topLevelGraph(node)
+		if err != nil {
+			return nil, err
+		}
+		if nodes == nil {
+			active, nodes = newActiveContext(), []map[string]any{node}
+		}
+		for _, n := range nodes {
+			if _, err := c.node(active, n, Term{}, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return c.quads, nil
+}
+
+type converter struct {
+	contexts *contextProcessor
+	// blanks maps the blank node identifiers of the document to the ones issued by the converter.
+	blanks  map[string]string
+	counter int
+	quads   Dataset
+	seen    map[Quad]struct{}
+}
+
+// topLevelGraph returns the nodes of the given top level object, along with its context, if it only holds a @graph,
+// which then stands for the default graph. It returns no nodes otherwise.
+func (c *converter) topLevelGraph(node map[string]any) (*activeContext, []map[string]any, error) {
+	active := newActiveContext()
+	if local, ok := node["@context"]; ok {
+		var err error
+		if active, err = c.contexts.process(active, local, true, false); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var graph any
+	for key, value := range node {
+		if key == "@context" {
+			continue
+		}
+		if expanded, _ := expandIRI(active, key, false, true, nil, nil); expanded != "@graph" {
+			return nil, nil, nil
+		}
+		graph = value
+	}
+	if graph == nil {
+		return nil, nil, nil
+	}
+
+	nodes := make([]map[string]any, 0)
+	for _, item := range asArray(graph) {
+		m, ok := item.(map[string]any)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: @graph must hold node objects", ErrInvalidDocument)
+		}
+		nodes = append(nodes, m)
+	}
+
+	return active, nodes, nil
+}
+
+func (c *converter) blankNode() Term {
+	label := "b" + strconv.Itoa(c.counter)
+	c.counter++
+
+	return NewBlankNode(label)
+}
+
+// identify returns the term identified by the given expanded IRI or blank node identifier.
+func (c *converter) identify(id string) Term {
+	if label, ok := strings.CutPrefix(id, "_:"); ok {
+		issued, ok := c.blanks[label]
+		if !ok {
+			issued = c.blankNode().Value
+			c.blanks[label] = issued
+		}
+		return NewBlankNode(issued)
+	}
+
+	return NewIRI(id)
+}
+
+// emit adds the quad to the dataset, unless it is a duplicate or involves relative IRIs, which RDF cannot represent.
+func (c *converter) emit(s, p, o, g Term) {
+	for _, t := range []Term{s, p, o} {
+		if t.Kind == IRI && !isAbsoluteIRI(t.Value) {
+			return
+		}
+	}
+	if !g.IsDefaultGraph() && g.Kind == IRI && !isAbsoluteIRI(g.Value) {
+		return
+	}
+
+	q := Quad{Subject: s, Predicate: p, Object: o, Graph: g}
+	if _, ok := c.seen[q]; ok {
+		return
+	}
+	c.seen[q] = struct{}{}
+	c.quads = append(c.quads, q)
+}
+
+// node converts the given node object into the given graph, returning its subject. The scoped context is the one of
+// the property holding the node, if any.
+//
+//nolint:funlen,gocognit,cyclop // follows the steps of the specification.
+func (c *converter) node(active *activeContext, node map[string]any, graph Term, scoped any) (Term, error) {
+	var err error
+	if active.previous != nil {
+		active = active.previous
+	}
+	if scoped != nil {
+		if active, err = c.contexts.process(active, scoped, true, true); err != nil {
+			return Term{}, err
+		}
+	}
+	if local, ok := node["@context"]; ok {
+		if active, err = c.contexts.process(active, local, true, false); err != nil {
+			return Term{}, err
+		}
+	}
+
+	typeScoped := active
+	var types []string
+	for _, key := range sortedKeys(node) {
+		if expanded, _ := expandIRI(active, key, false, true, nil, nil); expanded != "@type" {
+			continue
+		}
+		for _, t := range asArray(node[key]) {
+			s, ok := t.(string)
+			if !ok {
+				return Term{}, fmt.Errorf("%w: @type values must be strings", ErrInvalidDocument)
+			}
+			types = append(types, s)
+		}
+	}
+	for _, t := range slices.Sorted(slices.Values(types)) {
+		if def, ok := typeScoped.terms[t]; ok && def.hasContext {
+			if active, err = c.contexts.process(active, def.context, false, false); err != nil {
+				return Term{}, err
+			}
+		}
+	}
+
+	var subject Term
+	for key, value := range node {
+		if expanded, _ := expandIRI(active, key, false, true, nil, nil); expanded != "@id" {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			return Term{}, fmt.Errorf("%w: @id must be a string", ErrInvalidDocument)
+		}
+		id, _ := expandIRI(active, s, true, false, nil, nil)
+		subject = c.identify(id)
+	}
+	if subject == (Term{}) {
+		subject = c.blankNode()
+	}
+
+	for _, t := range types {
+		if iri, ok := expandIRI(typeScoped, t, true, true, nil, nil); ok {
+			c.emit(subject, NewIRI(RDFType), c.identify(iri), graph)
+		}
+	}
+
+	for _, key := range sortedKeys(node) {
+		expanded, ok := expandIRI(active, key, false, true, nil, nil)
+		if !ok {
+			continue
+		}
+
+		switch expanded {
+		case "@context", "@id", "@type", "@index":
+			continue
+		case "@graph":
+			for _, item := range asArray(node[key]) {
+				m, ok := item.(map[string]any)
+				if !ok {
+					return Term{}, fmt.Errorf("%w: @graph must hold node objects", ErrInvalidDocument)
+				}
+				if _, err := c.node(active, m, subject, nil); err != nil {
+					return Term{}, err
+				}
+			}
+			continue
+		case "@included":
+			for _, item := range asArray(node[key]) {
+				m, ok := item.(map[string]any)
+				if !ok {
+					return Term{}, fmt.Errorf("%w: @included must hold node objects", ErrInvalidDocument)
+				}
+				if _, err := c.node(active, m, graph, nil); err != nil {
+					return Term{}, err
+				}
+			}
+			continue
+		case "@reverse", "@nest":
+			return Term{}, fmt.Errorf("%w: %s", ErrUnsupported, expanded)
+		}
+		if isKeyword(expanded) {
+			return Term{}, fmt.Errorf("%w: %s is not allowed in a node object", ErrInvalidDocument, expanded)
+		}
+		if !isAbsoluteIRI(expanded) {
+			continue
+		}
+
+		objects, err := c.values(active, active.terms[key], node[key], graph)
+		if err != nil {
+			return Term{}, err
+		}
+		predicate := NewIRI(expanded)
+		for _, o := range objects {
+			c.emit(subject, predicate, o, graph)
+		}
+	}
+
+	return subject, nil
+}
+
+// values converts the value of a property defined by the given term, if any, into the RDF terms it stands for.
+//
+//nolint:gocognit,cyclop // one case per container.
+func (c *converter) values(active *activeContext, def *termDefinition, value any, graph Term) ([]Term, error) {
+	if m, ok := value.(map[string]any); ok {
+		switch {
+		case def.hasContainer("@language"):
+			return c.languageMap(active, def, m)
+		case def.hasContainer("@index") && !def.hasContainer("@graph"):
+			items := make([]any, 0, len(m))
+			for _, k := range sortedKeys(m) {
+				items = append(items, asArray(m[k])...)
+			}
+			value = items
+		}
+	}
+
+	if def.hasContainer("@list") {
+		list, err := c.list(active, def, asArray(value), graph)
+		if err != nil {
+			return nil, err
+		}
+		return []Term{list}, nil
+	}
+
+	var terms []Term
+	for _, item := range asArray(value) {
+		if m, ok := item.(map[string]any); ok && def.hasContainer("@graph") && !c.isValueOrList(active, m) {
+			name := c.blankNode()
+			if _, err := c.node(active, m, name, scopedContext(def)); err != nil {
+				return nil, err
+			}
+			terms = append(terms, name)
+			continue
+		}
+
+		t, err := c.value(active, def, item, graph)
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, t...)
+	}
+
+	return terms, nil
+}
+
+func scopedContext(def *termDefinition) any {
+	if def == nil || !def.hasContext {
+		return nil
+	}
+
+	return def.context
+}
+
+func (c *converter) isValueOrList(active *activeContext, m map[string]any) bool {
+	for key := range m {
+		switch expanded, _ := expandIRI(active, key, false, true, nil, nil); expanded {
+		case "@value", "@list", "@set":
+			return true
+		}
+	}
+
+	return false
+}
+
+// value converts a single value into the RDF terms it stands for, adding the quads of the nodes and lists it holds.
+//
+//nolint:cyclop // one case per kind of value.
+func (c *converter) value(active *activeContext, def *termDefinition, value any, graph Term) ([]Term, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		list, err := c.list(active, def, v, graph)
+		if err != nil {
+			return nil, err
+		}
+		return []Term{list}, nil
+	case map[string]any:
+		keys := map[string]any{}
+		for key, value := range v {
+			expanded, _ := expandIRI(active, key, false, true, nil, nil)
+			keys[expanded] = value
+		}
+
+		switch {
+		case hasKey(keys, "@value"):
+			t, ok, err := c.valueObject(active, keys)
+			if err != nil || !ok {
+				return nil, err
+			}
+			return []Term{t}, nil
+		case hasKey(keys, "@list"):
+			list, err := c.list(active, def, asArray(keys["@list"]), graph)
+			if err != nil {
+				return nil, err
+			}
+			return []Term{list}, nil
+		case hasKey(keys, "@set"):
+			return c.values(active, &termDefinition{typ: typeOf(def), language: languageOf(def)}, keys["@set"], graph)
+		}
+
+		subject, err := c.node(active, v, graph, scopedContext(def))
+		if err != nil {
+			return nil, err
+		}
+		return []Term{subject}, nil
+	default:
+		if scoped := scopedContext(def); scoped != nil {
+			var err error
+			if active, err = c.contexts.process(active, scoped, true, true); err != nil {
+				return nil, err
+			}
+		}
+		t, ok, err := c.scalar(active, def, v)
+		if err != nil || !ok {
+			return nil, err
+		}
+		return []Term{t}, nil
+	}
+}
+
+func hasKey(m map[string]any, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+func typeOf(def *termDefinition) string {
+	if def == nil {
+		return ""
+	}
+
+	return def.typ
+}
+
+func languageOf(def *termDefinition) *string {
+	if def == nil {
+		return nil
+	}
+
+	return def.language
+}
+
+// scalar converts a string, number or boolean into the RDF term it stands for, coerced as the term defines.
+func (c *converter) scalar(active *activeContext, def *termDefinition, value any) (Term, bool, error) {
+	typ := typeOf(def)
+	switch typ {
+	case "@json":
+		return jsonLiteral(value)
+	case "@id", "@vocab":
+		s, ok := value.(string)
+		if !ok {
+			break
+		}
+		iri, ok := expandIRI(active, s, true, typ == "@vocab", nil, nil)
+		if !ok {
+			return Term{}, false, nil
+		}
+		return c.identify(iri), true, nil
+	}
+
+	if typ == "@id" || typ == "@vocab" || typ == "@none" {
+		typ = ""
+	}
+
+	if s, ok := value.(string); ok && typ == "" {
+		language := active.language
+		if l := languageOf(def); l != nil {
+			language = *l
+		}
+		if language != "" {
+			return Term{Kind: Literal, Value: s, Datatype: RDFLangString, Language: language}, true, nil
+		}
+	}
+
+	return literal(value, typ)
+}
+
+// valueObject converts a value object, whose keys have been expanded, to a literal.
+func (c *converter) valueObject(active *activeContext, object map[string]any) (Term, bool, error) {
+	value := object["@value"]
+	var typ string
+	if t, ok := object["@type"]; ok {
+		s, ok := t.(string)
+		if !ok {
+			return Term{}, false, fmt.Errorf("%w: @type of a value object must be a string", ErrInvalidDocument)
+		}
+		if typ, ok = expandIRI(active, s, true, true, nil, nil); !ok {
+			return Term{}, false, nil
+		}
+	}
+
+	if typ == "@json" {
+		return jsonLiteral(value)
+	}
+	if value == nil {
+		return Term{}, false, nil
+	}
+	if l, ok := object["@language"]; ok {
+		s, ok := value.(string)
+		lang, isString := l.(string)
+		if !ok || !isString || typ != "" {
+			return Term{}, false, fmt.Errorf("%w: invalid language-tagged string", ErrInvalidDocument)
+		}
+		return Term{Kind: Literal, Value: s, Datatype: RDFLangString, Language: strings.ToLower(lang)}, true, nil
+	}
+	if _, ok := value.(map[string]any); ok {
+		return Term{}, false, fmt.Errorf("%w: @value must be a scalar", ErrInvalidDocument)
+	}
+
+	return literal(value, typ)
+}
+
+func (c *converter) languageMap(active *activeContext, def *termDefinition, m map[string]any) ([]Term, error) {
+	var terms []Term
+	for _, lang := range sortedKeys(m) {
+		expanded, _ := expandIRI(active, lang, false, true, nil, nil)
+		for _, item := range asArray(m[lang]) {
+			s, ok := item.(string)
+			switch {
+			case item == nil:
+				continue
+			case !ok:
+				return nil, fmt.Errorf("%w: language map values must be strings", ErrInvalidDocument)
+			case expanded == "@none":
+				terms = append(terms, NewLiteral(s, XSDString))
+			default:
+				terms = append(terms, Term{Kind: Literal, Value: s, Datatype: RDFLangString, Language: strings.ToLower(lang)})
+			}
+		}
+	}
+
+	return terms, nil
+}
+
+// list converts the given items to an RDF collection, returning its head.
+func (c *converter) list(active *activeContext, def *termDefinition, items []any, graph Term) (Term, error) {
+	itemDef := &termDefinition{typ: typeOf(def), language: languageOf(def)}
+	if def != nil {
+		itemDef.context, itemDef.hasContext = def.context, def.hasContext
+	}
+
+	var terms []Term
+	for _, item := range items {
+		t, err := c.value(active, itemDef, item, graph)
+		if err != nil {
+			return Term{}, err
+		}
+		terms = append(terms, t...)
+	}
+
+	head := NewIRI(RDFNil)
+	for i := len(terms) - 1; i >= 0; i-- {
+		node := c.blankNode()
+		c.emit(node, NewIRI(RDFFirst), terms[i], graph)
+		c.emit(node, NewIRI(RDFRest), head, graph)
+		head = node
+	}
+
+	return head, nil
+}
+
+// literal converts a native JSON value to a literal of the given datatype, or of the datatype matching its type.
+func literal(value any, typ string) (Term, bool, error) {
+	switch v := value.(type) {
+	case string:
+		if typ == "" {
+			typ = XSDString
+		}
+		return NewLiteral(v, typ), true, nil
+	case bool:
+		if typ == "" {
+			typ = XSDBoolean
+		}
+		return NewLiteral(strconv.FormatBool(v), typ), true, nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e21 && typ != XSDDouble {
+			if typ == "" {
+				typ = XSDInteger
+			}
+			return NewLiteral(strconv.FormatFloat(v, 'f', -1, 64), typ), true, nil
+		}
+		if typ == "" {
+			typ = XSDDouble
+		}
+		return NewLiteral(canonicalDouble(v), typ), true, nil
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return Term{}, false, fmt.Errorf("%w: invalid number %s", ErrInvalidDocument, v)
+		}
+		return literal(f, typ)
+	default:
+		return Term{}, false, fmt.Errorf("%w: unexpected value %v", ErrInvalidDocument, value)
+	}
+}
+
+// canonicalDouble formats a double in the canonical lexical form of xsd:double used by JSON-LD, such as 1.1E0.
+func canonicalDouble(v float64) string {
+	mantissa, exponent, _ := strings.Cut(strconv.FormatFloat(v, 'E', 15, 64), "E")
+	whole, fraction, _ := strings.Cut(mantissa, ".")
+	fraction = strings.TrimRight(fraction, "0")
+	if fraction == "" {
+		fraction = "0"
+	}
+	exp, _ := strconv.Atoi(exponent)
+
+	return whole + "." + fraction + "E" + strconv.Itoa(exp)
+}
+
+// jsonLiteral converts a JSON value to an rdf:JSON literal, in its canonical form.
+func jsonLiteral(value any) (Term, bool, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return Term{}, false, fmt.Errorf("%w: %w", ErrInvalidDocument, err)
+	}
+
+	return NewLiteral(strings.TrimSuffix(buf.String(), "\n"), RDFJSON), true, nil
+}
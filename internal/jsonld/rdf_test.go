@@ -0,0 +1,167 @@
+package jsonld
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func toCanonical(src string) (string, error) {
+	var doc any
+	if err := json.Unmarshal([]byte(src), &doc); err != nil {
+		return "", err
+	}
+
+	dataset, err := ToRDF(context.Background(), doc, &EmbeddedLoader{})
+	if err != nil {
+		return "", err
+	}
+
+	return Canonicalize(dataset)
+}
+
+func TestToRDF(t *testing.T) {
+	Convey("Given JSON-LD documents", t, func() {
+		cases := []struct {
+			name     string
+			document string
+			expected string
+		}{
+			{
+				name: "a verifiable credential",
+				document: `{
+  "@context": [
+    "https://www.w3.org/2018/credentials/v1",
+    "https://w3id.org/security/suites/ed25519-2020/v1",
+    {"name": "https://schema.org/name"}
+  ],
+  "id": "urn:uuid:1",
+  "type": ["VerifiableCredential"],
+  "issuer": "did:key:z6Mk",
+  "issuanceDate": "2024-01-01T00:00:00Z",
+  "credentialSubject": {"id": "did:example:abc", "name": "Alice"},
+  "proof": {
+    "type": "Ed25519Signature2020",
+    "created": "2024-01-01T00:00:00Z",
+    "verificationMethod": "did:key:z6Mk#z6Mk",
+    "proofPurpose": "assertionMethod",
+    "proofValue": "z123"
+  }
+}`,
+				expected: `<did:example:abc> <https://schema.org/name> "Alice" .
+<urn:uuid:1> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://www.w3.org/2018/credentials#VerifiableCredential> .
+<urn:uuid:1> <https://w3id.org/security#proof> _:c14n0 .
+<urn:uuid:1> <https://www.w3.org/2018/credentials#credentialSubject> <did:example:abc> .
+<urn:uuid:1> <https://www.w3.org/2018/credentials#issuanceDate> "2024-01-01T00:00:00Z"^^<http://www.w3.org/2001/XMLSchema#dateTime> .
+<urn:uuid:1> <https://www.w3.org/2018/credentials#issuer> <did:key:z6Mk> .
+_:c14n1 <http://purl.org/dc/terms/created> "2024-01-01T00:00:00Z"^^<http://www.w3.org/2001/XMLSchema#dateTime> _:c14n0 .
+_:c14n1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://w3id.org/security#Ed25519Signature2020> _:c14n0 .
+_:c14n1 <https://w3id.org/security#proofPurpose> <https://w3id.org/security#assertionMethod> _:c14n0 .
+_:c14n1 <https://w3id.org/security#proofValue> "z123"^^<https://w3id.org/security#multibase> _:c14n0 .
+_:c14n1 <https://w3id.org/security#verificationMethod> <did:key:z6Mk#z6Mk> _:c14n0 .
+`,
+			},
+			{
+				name: "type-scoped terms used in a nested node",
+				document: `{
+  "@context": "https://www.w3.org/2018/credentials/v1",
+  "id": "urn:uuid:2",
+  "type": "VerifiableCredential",
+  "credentialSubject": {"id": "did:example:abc", "issuanceDate": "2024-01-01T00:00:00Z"}
+}`,
+				expected: `<urn:uuid:2> <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://www.w3.org/2018/credentials#VerifiableCredential> .
+<urn:uuid:2> <https://www.w3.org/2018/credentials#credentialSubject> <did:example:abc> .
+`,
+			},
+			{
+				name: "native values, language and lists",
+				document: `{
+  "@context": {
+    "@vocab": "http://example.org/",
+    "@language": "en",
+    "items": {"@container": "@list"},
+    "code": {"@language": null}
+  },
+  "@id": "http://example.org/s",
+  "label": "hello",
+  "code": "x\"y",
+  "count": 3,
+  "ratio": 1.5,
+  "ok": true,
+  "items": ["a", {"@value": "b", "@type": "http://example.org/T"}]
+}`,
+				expected: `<http://example.org/s> <http://example.org/code> "x\"y" .
+<http://example.org/s> <http://example.org/count> "3"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<http://example.org/s> <http://example.org/items> _:c14n0 .
+<http://example.org/s> <http://example.org/label> "hello"@en .
+<http://example.org/s> <http://example.org/ok> "true"^^<http://www.w3.org/2001/XMLSchema#boolean> .
+<http://example.org/s> <http://example.org/ratio> "1.5E0"^^<http://www.w3.org/2001/XMLSchema#double> .
+_:c14n0 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "a"@en .
+_:c14n0 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> _:c14n1 .
+_:c14n1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#first> "b"^^<http://example.org/T> .
+_:c14n1 <http://www.w3.org/1999/02/22-rdf-syntax-ns#rest> <http://www.w3.org/1999/02/22-rdf-syntax-ns#nil> .
+`,
+			},
+			{
+				name: "a top level graph",
+				document: `{
+  "@context": {"ex": "http://example.org/"},
+  "@graph": [{"@id": "ex:a", "ex:p": {"@id": "ex:b"}}, {"@id": "ex:b", "ex:p": "c"}]
+}`,
+				expected: `<http://example.org/a> <http://example.org/p> <http://example.org/b> .
+<http://example.org/b> <http://example.org/p> "c" .
+`,
+			},
+		}
+
+		for _, tc := range cases {
+			Convey("When converting "+tc.name, func() {
+				nquads, err := toCanonical(tc.document)
+
+				Convey("Then the canonical N-Quads should be the expected ones", func() {
+					So(err, ShouldBeNil)
+					So(nquads, ShouldEqual, tc.expected)
+				})
+			})
+		}
+	})
+
+	Convey("Given JSON-LD documents that cannot be converted", t, func() {
+		cases := []struct {
+			name     string
+			document string
+			err      error
+		}{
+			{
+				name: "a redefined protected term",
+				document: `{
+  "@context": ["https://www.w3.org/2018/credentials/v1", {"VerifiableCredential": "http://example.org/VC"}],
+  "type": "VerifiableCredential"
+}`,
+				err: ErrInvalidContext,
+			},
+			{
+				name:     "an unknown remote context",
+				document: `{"@context": "https://example.org/unknown", "@id": "http://example.org/s"}`,
+				err:      ErrContextNotFound,
+			},
+			{
+				name:     "a reverse property",
+				document: `{"@context": {"p": {"@reverse": "http://example.org/p"}}, "p": "x"}`,
+				err:      ErrUnsupported,
+			},
+		}
+
+		for _, tc := range cases {
+			Convey("When converting "+tc.name, func() {
+				_, err := toCanonical(tc.document)
+
+				Convey("Then it should fail", func() {
+					So(err, ShouldWrap, tc.err)
+				})
+			})
+		}
+	})
+}
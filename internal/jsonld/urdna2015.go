@@ -0,0 +1,315 @@
+package jsonld
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrTooComplex = errors.New("dataset too complex to canonicalize")
+
+// maxSteps bounds the number of N-degree hashes and permutations computed while canonicalizing a dataset, as crafted
+// datasets can make their number grow factorially.
+const maxSteps = 1 << 16
+
+// Canonicalize relabels the blank nodes of the dataset as defined by the URDNA2015 algorithm and returns its
+// canonical N-Quads serialization.
+func Canonicalize(dataset Dataset) (string, error) {
+	c := &canonicalizer{
+		issuer:  newIssuer("c14n"),
+		quads:   map[string][]Quad{},
+		budget:  maxSteps,
+		dataset: dataset,
+	}
+
+	return c.run()
+}
+
+// issuer issues identifiers to blank nodes, keeping track of the order they were issued in.
+type issuer struct {
+	prefix string
+	issued map[string]string
+	order  []string
+}
+
+func newIssuer(prefix string) *issuer {
+	return &issuer{prefix: prefix, issued: map[string]string{}}
+}
+
+func (i *issuer) issue(id string) string {
+	if issued, ok := i.issued[id]; ok {
+		return issued
+	}
+
+	issued := i.prefix + strconv.Itoa(len(i.order))
+	i.issued[id] = issued
+	i.order = append(i.order, id)
+
+	return issued
+}
+
+func (i *issuer) has(id string) bool {
+	_, ok := i.issued[id]
+	return ok
+}
+
+func (i *issuer) clone() *issuer {
+	c := &issuer{prefix: i.prefix, issued: make(map[string]string, len(i.issued)), order: slices.Clone(i.order)}
+	for k, v := range i.issued {
+		c.issued[k] = v
+	}
+
+	return c
+}
+
+type canonicalizer struct {
+	issuer  *issuer
+	quads   map[string][]Quad
+	budget  int
+	dataset Dataset
+}
+
+//nolint:gocognit // follows the steps of the specification.
+func (c *canonicalizer) run() (string, error) {
+	for _, q := range c.dataset {
+		for _, t := range []Term{q.Subject, q.Object, q.Graph} {
+			if t.Kind == BlankNode && !slices.Contains(c.quads[t.Value], q) {
+				c.quads[t.Value] = append(c.quads[t.Value], q)
+			}
+		}
+	}
+
+	pending := sortedKeys(c.quads)
+	hashToNodes := map[string][]string{}
+	for simple := true; simple; {
+		simple = false
+		clear(hashToNodes)
+		for _, id := range pending {
+			h := c.hashFirstDegree(id)
+			hashToNodes[h] = append(hashToNodes[h], id)
+		}
+
+		for _, h := range sortedKeys(hashToNodes) {
+			ids := hashToNodes[h]
+			if len(ids) > 1 {
+				continue
+			}
+			c.issuer.issue(ids[0])
+			pending = slices.DeleteFunc(pending, func(id string) bool { return id == ids[0] })
+			delete(hashToNodes, h)
+			simple = true
+		}
+	}
+
+	for _, h := range sortedKeys(hashToNodes) {
+		var results []degreeResult
+		for _, id := range hashToNodes[h] {
+			if c.issuer.has(id) {
+				continue
+			}
+			tmp := newIssuer("b")
+			tmp.issue(id)
+			result, err := c.hashNDegree(id, tmp)
+			if err != nil {
+				return "", err
+			}
+			results = append(results, result)
+		}
+
+		slices.SortFunc(results, func(a, b degreeResult) int { return strings.Compare(a.hash, b.hash) })
+		for _, result := range results {
+			for _, id := range result.issuer.order {
+				c.issuer.issue(id)
+			}
+		}
+	}
+
+	canonical := make(Dataset, 0, len(c.dataset))
+	for _, q := range c.dataset {
+		canonical = append(canonical, Quad{
+			Subject:   c.relabel(q.Subject),
+			Predicate: q.Predicate,
+			Object:    c.relabel(q.Object),
+			Graph:     c.relabel(q.Graph),
+		})
+	}
+
+	return canonical.NQuads(), nil
+}
+
+func (c *canonicalizer) spend() error {
+	if c.budget--; c.budget < 0 {
+		return ErrTooComplex
+	}
+
+	return nil
+}
+
+func (c *canonicalizer) relabel(t Term) Term {
+	if t.Kind != BlankNode {
+		return t
+	}
+
+	return NewBlankNode(c.issuer.issue(t.Value))
+}
+
+// hashFirstDegree hashes the quads mentioning the given blank node, regardless of the other blank nodes they mention.
+func (c *canonicalizer) hashFirstDegree(id string) string {
+	mask := func(t Term) Term {
+		if t.Kind != BlankNode {
+			return t
+		}
+		if t.Value == id {
+			return NewBlankNode("a")
+		}
+		return NewBlankNode("z")
+	}
+
+	lines := make([]string, 0, len(c.quads[id]))
+	for _, q := range c.quads[id] {
+		lines = append(lines, Quad{
+			Subject:   mask(q.Subject),
+			Predicate: q.Predicate,
+			Object:    mask(q.Object),
+			Graph:     mask(q.Graph),
+		}.String())
+	}
+	slices.Sort(lines)
+
+	return hash(strings.Join(lines, ""))
+}
+
+func (c *canonicalizer) hashRelated(related string, q Quad, iss *issuer, position string) string {
+	var id string
+	switch {
+	case c.issuer.has(related):
+		id = "_:" + c.issuer.issue(related)
+	case iss.has(related):
+		id = "_:" + iss.issue(related)
+	default:
+		id = c.hashFirstDegree(related)
+	}
+
+	input := position
+	if position != "g" {
+		input += "<" + q.Predicate.Value + ">"
+	}
+
+	return hash(input + id)
+}
+
+type degreeResult struct {
+	hash   string
+	issuer *issuer
+}
+
+// hashNDegree hashes the given blank node according to the blank nodes it is related to, as defined by the Hash
+// N-Degree Quads algorithm of URDNA2015.
+//
+//nolint:funlen,gocognit,cyclop // follows the steps of the specification.
+func (c *canonicalizer) hashNDegree(id string, iss *issuer) (degreeResult, error) {
+	if err := c.spend(); err != nil {
+		return degreeResult{}, err
+	}
+
+	hashToRelated := map[string][]string{}
+	for _, q := range c.quads[id] {
+		for _, component := range []struct {
+			term     Term
+			position string
+		}{{q.Subject, "s"}, {q.Object, "o"}, {q.Graph, "g"}} {
+			if component.term.Kind != BlankNode || component.term.Value == id {
+				continue
+			}
+			h := c.hashRelated(component.term.Value, q, iss, component.position)
+			hashToRelated[h] = append(hashToRelated[h], component.term.Value)
+		}
+	}
+
+	var data strings.Builder
+	for _, related := range sortedKeys(hashToRelated) {
+		data.WriteString(related)
+
+		var chosenPath string
+		var chosenIssuer *issuer
+		err := permute(hashToRelated[related], func(permutation []string) error {
+			if err := c.spend(); err != nil {
+				return err
+			}
+			copied := iss.clone()
+			var path strings.Builder
+			var recursion []string
+			longer := func() bool {
+				return chosenPath != "" && path.Len() >= len(chosenPath) && path.String() > chosenPath
+			}
+
+			for _, r := range permutation {
+				if c.issuer.has(r) {
+					path.WriteString("_:" + c.issuer.issue(r))
+				} else {
+					if !copied.has(r) {
+						recursion = append(recursion, r)
+					}
+					path.WriteString("_:" + copied.issue(r))
+				}
+				if longer() {
+					return nil
+				}
+			}
+
+			for _, r := range recursion {
+				result, err := c.hashNDegree(r, copied)
+				if err != nil {
+					return err
+				}
+				path.WriteString("_:" + copied.issue(r))
+				path.WriteString("<" + result.hash + ">")
+				copied = result.issuer
+				if longer() {
+					return nil
+				}
+			}
+
+			if chosenPath == "" || path.String() < chosenPath {
+				chosenPath, chosenIssuer = path.String(), copied
+			}
+			return nil
+		})
+		if err != nil {
+			return degreeResult{}, err
+		}
+
+		data.WriteString(chosenPath)
+		iss = chosenIssuer
+	}
+
+	return degreeResult{hash: hash(data.String()), issuer: iss}, nil
+}
+
+// permute calls fn with every permutation of the given elements, stopping at the first error.
+func permute(elements []string, fn func([]string) error) error {
+	var rec func(int) error
+	rec = func(k int) error {
+		if k == len(elements) {
+			return fn(slices.Clone(elements))
+		}
+		for i := k; i < len(elements); i++ {
+			elements[k], elements[i] = elements[i], elements[k]
+			if err := rec(k + 1); err != nil {
+				return err
+			}
+			elements[k], elements[i] = elements[i], elements[k]
+		}
+		return nil
+	}
+
+	return rec(0)
+}
+
+func hash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,83 @@
+package jsonld
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCanonicalize(t *testing.T) {
+	ex := func(s string) Term { return NewIRI("http://example.com/#" + s) }
+
+	Convey("Given a dataset whose blank nodes have unique first degree hashes", t, func() {
+		dataset := Dataset{
+			{Subject: ex("p"), Predicate: ex("q"), Object: NewBlankNode("e0")},
+			{Subject: ex("p"), Predicate: ex("r"), Object: NewBlankNode("e1")},
+			{Subject: NewBlankNode("e0"), Predicate: ex("s"), Object: ex("u")},
+			{Subject: NewBlankNode("e1"), Predicate: ex("t"), Object: ex("u")},
+		}
+
+		Convey("When canonicalizing it", func() {
+			nquads, err := Canonicalize(dataset)
+
+			Convey("Then the blank nodes should be labelled in the order of their hashes", func() {
+				So(err, ShouldBeNil)
+				So(nquads, ShouldEqual, `<http://example.com/#p> <http://example.com/#q> _:c14n0 .
+<http://example.com/#p> <http://example.com/#r> _:c14n1 .
+_:c14n0 <http://example.com/#s> <http://example.com/#u> .
+_:c14n1 <http://example.com/#t> <http://example.com/#u> .
+`)
+			})
+		})
+	})
+
+	Convey("Given isomorphic datasets with shared first degree hashes", t, func() {
+		cycle := func(labels ...string) Dataset {
+			var d Dataset
+			for i, l := range labels {
+				next := NewBlankNode(labels[(i+1)%len(labels)])
+				d = append(d, Quad{Subject: NewBlankNode(l), Predicate: ex("next"), Object: next})
+			}
+			d = append(d, Quad{Subject: ex("start"), Predicate: ex("in"), Object: NewBlankNode(labels[0])})
+			return d
+		}
+		a := cycle("x", "y", "z", "w")
+		b := cycle("n3", "n1", "n2", "n0")
+		b[0], b[3] = b[3], b[0]
+
+		Convey("When canonicalizing them", func() {
+			nquadsA, errA := Canonicalize(a)
+			nquadsB, errB := Canonicalize(b)
+
+			Convey("Then their canonical forms should be identical", func() {
+				So(errA, ShouldBeNil)
+				So(errB, ShouldBeNil)
+				So(nquadsA, ShouldEqual, nquadsB)
+				So(nquadsA, ShouldContainSubstring, "<http://example.com/#start> <http://example.com/#in> _:c14n")
+			})
+		})
+	})
+
+	Convey("Given a dataset made of many indistinguishable blank nodes", t, func() {
+		var dataset Dataset
+		for i := range 12 {
+			for j := range 12 {
+				if i != j {
+					dataset = append(dataset, Quad{
+						Subject:   NewBlankNode("n" + string(rune('a'+i))),
+						Predicate: ex("p"),
+						Object:    NewBlankNode("n" + string(rune('a'+j))),
+					})
+				}
+			}
+		}
+
+		Convey("When canonicalizing it", func() {
+			_, err := Canonicalize(dataset)
+
+			Convey("Then it should give up", func() {
+				So(err, ShouldEqual, ErrTooComplex)
+			})
+		})
+	})
+}
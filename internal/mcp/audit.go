@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/audit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// WithToolAudit returns the server option recording each tool invocation in the given audit log, along with a
+// digest of its result, including the calls rejected by the policy or the rate limits.
+func WithToolAudit(l *audit.Log) server.ServerOption {
+	return server.WithToolHandlerMiddleware(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			start := time.Now()
+			result, err := next(ctx, request)
+
+			subject := subjectFromContext(ctx)
+			entry := audit.Entry{
+				Time:      start,
+				SessionID: subject.SessionID,
+				Principal: subject.Principal,
+				Tool:      request.Params.Name,
+				Arguments: request.GetArguments(),
+				LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			switch {
+			case err != nil:
+				entry.ErrorCode = requestErrorCode
+			case result != nil:
+				if result.IsError {
+					entry.ErrorCode = string(resultErrorCode(result))
+				}
+				if digest, err := audit.Digest(result); err == nil {
+					entry.ResultDigest = digest
+				}
+			}
+
+			if err := l.Record(entry); err != nil {
+				log.Logger.Error().
+					Str("session_id", subject.SessionID).
+					Str("tool", request.Params.Name).
+					Err(err).
+					Msg("failed to record tool call in audit log")
+			}
+
+			return result, err
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	goctx "context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/audit"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestToolAudit(t *testing.T) {
+	Convey("Given a server recording the tool invocations in an audit log", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		filename := filepath.Join(t.TempDir(), "audit.jsonl")
+		l, err := audit.Open(filename, audit.Config{Redact: []string{"get_dataverse_info:dataverse"}})
+		So(err, ShouldBeNil)
+		Reset(func() { _ = l.Close() })
+
+		cc := mocks.NewMockClientConnInterface(ctrl)
+		s, err := NewServer(cc, ReadWrite, WithAuditLog(l))
+		So(err, ShouldBeNil)
+
+		Convey("When tools are called", func() {
+			expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+				`{"dataverse":{}}`, `{"name":"dataverse-42"}`, nil)
+
+			ctx := goctx.Background()
+			s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_dataverse_info",`+
+				`"arguments":{"dataverse":"axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"}}}`))
+			s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"resolve_did",`+
+				`"arguments":{"did":"did:foo"}}}`))
+			s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"resolve_did",`+
+				`"arguments":{}}}`))
+
+			Convey("Then each invocation should be recorded, with its result digest or error code", func() {
+				bz, err := os.ReadFile(filename)
+				So(err, ShouldBeNil)
+				lines := strings.Split(strings.TrimSpace(string(bz)), "\n")
+				So(lines, ShouldHaveLength, 3)
+
+				entries := make([]audit.Entry, len(lines))
+				for i, line := range lines {
+					So(json.Unmarshal([]byte(line), &entries[i]), ShouldBeNil)
+				}
+
+				So(entries[0].Tool, ShouldEqual, "get_dataverse_info")
+				So(entries[0].Arguments, ShouldResemble, map[string]any{"dataverse": audit.Redacted})
+				So(entries[0].ResultDigest, ShouldStartWith, "sha256:")
+				So(entries[0].ErrorCode, ShouldBeEmpty)
+				So(entries[0].Time.IsZero(), ShouldBeFalse)
+
+				So(entries[1].Tool, ShouldEqual, "resolve_did")
+				So(entries[1].Arguments, ShouldResemble, map[string]any{"did": "did:foo"})
+				So(entries[1].ResultDigest, ShouldStartWith, "sha256:")
+				So(entries[1].ErrorCode, ShouldEqual, string(ErrorCodeInvalidDID))
+
+				So(entries[2].ResultDigest, ShouldBeEmpty)
+				So(entries[2].ErrorCode, ShouldEqual, requestErrorCode)
+			})
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+)
+
+// ErrUnknownTool is returned when calling a tool which is not one of the chain query tools.
+var ErrUnknownTool = errors.New("unknown tool")
+
+// CallTool invokes the handler of the given chain query tool (e.g. get_dataverse_info) with the given arguments,
+// outside of any session: no policy, rate limit nor default argument applies.
+// A failure reported by the tool is returned as a *ToolError, along with the result reporting it.
+func CallTool(
+	ctx context.Context, cc grpc.ClientConnInterface, name string, arguments map[string]any,
+) (*mcp.CallToolResult, error) {
+	tools := lo.Map(serverToolFactories, createTool(cc))
+	tool, ok := lo.Find(tools, func(tool server.ServerTool) bool { return tool.Tool.Name == name })
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTool, name)
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	result, err := tool.Handler(ctx, request)
+	if err != nil || !result.IsError {
+		return result, err
+	}
+
+	return result, resultError(result)
+}
+
+// ResultText returns the text content of the given tool result.
+func ResultText(result *mcp.CallToolResult) string {
+	return strings.Join(lo.FilterMap(result.Content, func(c mcp.Content, _ int) (string, bool) {
+		text, ok := mcp.AsTextContent(c)
+		if !ok {
+			return "", false
+		}
+		return text.Text, true
+	}), "\n")
+}
+
+// resultError returns the failure reported by the given tool result.
+func resultError(result *mcp.CallToolResult) *ToolError {
+	toolErr := &ToolError{Code: resultErrorCode(result), Err: errors.New(ResultText(result))}
+	if meta, ok := result.Meta[errorMetaKey].(map[string]any); ok {
+		toolErr.Hint, _ = meta["hint"].(string)
+	}
+
+	return toolErr
+}
@@ -0,0 +1,188 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/credential"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/grpc"
+)
+
+func verifyCredential(verifier *credential.Verifier) serverToolFactory {
+	return func(_ grpc.ClientConnInterface) server.ServerTool {
+		const credentialParam = "credential"
+		tool := mcp.NewTool("verify_credential",
+			mcp.WithDescription(`Verify the given JSON-LD Verifiable Credential or Presentation: its `+
+				`Ed25519Signature2020, Ed25519Signature2018 or EcdsaSecp256k1Signature2019 proofs against the keys of `+
+				`its issuer or holder, and its validity period. Returns a detailed verification report.`),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:         "Verify a credential",
+				ReadOnlyHint:  mcp.ToBoolPtr(true),
+				OpenWorldHint: mcp.ToBoolPtr(true),
+			}),
+			mcp.WithObject(credentialParam,
+				mcp.Required(),
+				mcp.Description("The Verifiable Credential or Presentation to verify, in its JSON-LD form")),
+		)
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var document []byte
+			switch v := request.GetArguments()[credentialParam].(type) {
+			case nil:
+				return nil, fmt.Errorf("required argument %q not found", credentialParam)
+			case string:
+				document = []byte(v)
+			default:
+				bz, err := json.Marshal(v)
+				if err != nil {
+					return nil, fmt.Errorf("failed to marshal credential: %w", err)
+				}
+				document = bz
+			}
+
+			report, err := verifier.Verify(ctx, document)
+			if err != nil {
+				return toolResultError(err), nil
+			}
+
+			r, err := json.Marshal(report)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+
+		return server.ServerTool{Tool: tool, Handler: handler}
+	}
+}
+
+// ErrNoSigningKey is returned when a credential is asked to be signed while the server has no signing key.
+var ErrNoSigningKey = errors.New("no signing key configured")
+
+func buildCredential(signer *credential.Signer) serverToolFactory {
+	return func(_ grpc.ClientConnInterface) server.ServerTool {
+		const (
+			templateParam       = "template"
+			subjectParam        = "subject"
+			claimsParam         = "claims"
+			issuerParam         = "issuer"
+			idParam             = "id"
+			expirationDateParam = "expirationDate"
+			signParam           = "sign"
+		)
+		names := slices.Sorted(maps.Keys(credential.Templates))
+		issuerDescription := "The DID of the issuer of the credential"
+		if signer != nil {
+			issuerDescription += ", defaulting to the one of the server signing key: " + signer.DID()
+		}
+		tool := mcp.NewTool("build_credential",
+			mcp.WithDescription(`Build a JSON-LD Verifiable Credential of the Axone ontology from a template, `+
+				`validating the claims against the shape of the credential type, and optionally sign it with the `+
+				`key of the server. The templates and their claims are:`+"\n"+templatesDescription(names)),
+			// signing with the key of the server is not read-only, as it makes the server vouch for the claims.
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:         "Build a credential",
+				ReadOnlyHint:  mcp.ToBoolPtr(signer == nil),
+				OpenWorldHint: mcp.ToBoolPtr(false),
+			}),
+			mcp.WithString(templateParam,
+				mcp.Required(),
+				mcp.Enum(names...),
+				mcp.Description("The template of the credential")),
+			mcp.WithString(subjectParam,
+				mcp.Required(),
+				mcp.Description("The DID of the subject of the credential, e.g. the did:key of a dataset")),
+			mcp.WithObject(claimsParam,
+				mcp.Required(),
+				mcp.Description("The claims about the subject, by name, as listed for the template")),
+			mcp.WithString(issuerParam,
+				mcp.Description(issuerDescription)),
+			mcp.WithString(idParam,
+				mcp.Description("The IRI identifying the credential, defaulting to a random urn:uuid")),
+			mcp.WithString(expirationDateParam,
+				mcp.Description("The RFC 3339 date the credential expires at, if any")),
+			mcp.WithBoolean(signParam,
+				mcp.DefaultBool(false),
+				mcp.Description("Whether to sign the credential with the key of the server")),
+		)
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			req := credential.Request{
+				Template:     request.GetString(templateParam, ""),
+				ID:           request.GetString(idParam, ""),
+				Issuer:       request.GetString(issuerParam, ""),
+				Subject:      request.GetString(subjectParam, ""),
+				IssuanceDate: time.Now(),
+			}
+			claims, ok := request.GetArguments()[claimsParam].(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("required argument %q not found", claimsParam)
+			}
+			req.Claims = claims
+			if req.Issuer == "" && signer != nil {
+				req.Issuer = signer.DID()
+			}
+			if expiration := request.GetString(expirationDateParam, ""); expiration != "" {
+				t, err := time.Parse(time.RFC3339, expiration)
+				if err != nil {
+					return toolResultError(fmt.Errorf("%w: expirationDate: %w", credential.ErrInvalidClaims, err)), nil
+				}
+				req.ExpirationDate = t
+			}
+
+			vc, err := credential.Build(req)
+			if err != nil {
+				return toolResultError(err), nil
+			}
+
+			if request.GetBool(signParam, false) {
+				if signer == nil {
+					return toolResultError(newToolError(ErrorCodeNoSigningKey, ErrNoSigningKey)), nil
+				}
+				if err := signer.Sign(ctx, vc); err != nil {
+					return toolResultError(err), nil
+				}
+			}
+
+			r, err := json.Marshal(vc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+
+		return server.ServerTool{Tool: tool, Handler: handler}
+	}
+}
+
+// templatesDescription lists the given templates along with their claims, required ones marked with a star.
+func templatesDescription(names []string) string {
+	var sb strings.Builder
+	for _, name := range names {
+		t := credential.Templates[name]
+		fmt.Fprintf(&sb, "- %s (%s): %s. Claims:", t.Name, t.Type, t.Description)
+		for i, c := range t.Claims {
+			sep := ","
+			if i == 0 {
+				sep = ""
+			}
+			required := ""
+			if c.Required {
+				required = "*"
+			}
+			fmt.Fprintf(&sb, "%s %s%s (%s)", sep, c.Name, required, c.Description)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("Claims marked with * are required.")
+
+	return sb.String()
+}
@@ -0,0 +1,274 @@
+package mcp
+
+import (
+	goctx "context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/credential"
+	"github.com/axone-protocol/axone-mcp/internal/jsonld"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCredentialJSONRCPMessageHandling(t *testing.T) {
+	Convey("Testing credential JSON-RPC message handling", t, func() {
+		unsigned := map[string]any{
+			"@context":          "https://www.w3.org/2018/credentials/v1",
+			"id":                "urn:uuid:1",
+			"type":              "VerifiableCredential",
+			"issuer":            "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+			"issuanceDate":      "2025-01-01T00:00:00Z",
+			"credentialSubject": map[string]any{"id": "did:example:alice"},
+		}
+		const unsignedReport = `{"verified":false,"type":"VerifiableCredential","id":"urn:uuid:1",` +
+			`"issuer":"did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",` +
+			`"checks":[{"name":"issuer","passed":true},{"name":"issuanceDate","passed":true},` +
+			`{"name":"proof","passed":false,"message":"the document has no proof"}]}`
+		unsignedJSON, _ := json.Marshal(unsigned)
+
+		tests := []struct {
+			name       string
+			credential any
+			validate   func(response mcp.JSONRPCMessage)
+		}{
+			{
+				name:       "verify_credential tool",
+				credential: unsigned,
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, unsignedReport)
+				},
+			},
+			{
+				name:       "verify_credential tool - credential as a string",
+				credential: string(unsignedJSON),
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, unsignedReport)
+				},
+			},
+			{
+				name:       "verify_credential tool - not a credential",
+				credential: map[string]any{"type": "Foo"},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"invalid verifiable credential: type is neither VerifiableCredential nor VerifiablePresentation")
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidCredential)
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Given a new server for %s", tt.name), func() {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+
+				s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), ReadOnly)
+				So(err, ShouldBeNil)
+
+				messageBytes, err := json.Marshal(mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name":      "verify_credential",
+						"arguments": map[string]interface{}{"credential": tt.credential},
+					},
+				})
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("When handling %s message", tt.name), func() {
+					got := s.HandleMessage(goctx.Background(), messageBytes)
+					Convey("Then the response should be valid", func() {
+						tt.validate(got)
+					})
+				})
+			})
+		}
+	})
+}
+
+// templateLoader serves any remote context as one mapping every term to the context URL.
+type templateLoader struct{}
+
+func (templateLoader) Load(_ goctx.Context, url string) (any, error) {
+	return map[string]any{"@context": map[string]any{"@vocab": url}}, nil
+}
+
+// credentialFromResponse returns the credential held by the text of the tool result of a JSON-RPC response.
+func credentialFromResponse(response mcp.JSONRPCMessage) map[string]any {
+	So(response, ShouldHaveSameTypeAs, mcp.JSONRPCResponse{})
+	result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult)
+	So(ok, ShouldBeTrue)
+	So(result.IsError, ShouldBeFalse)
+	So(result.Content, ShouldHaveLength, 1)
+	text, ok := result.Content[0].(mcp.TextContent)
+	So(ok, ShouldBeTrue)
+
+	var vc map[string]any
+	So(json.Unmarshal([]byte(text.Text), &vc), ShouldBeNil)
+
+	return vc
+}
+
+func TestBuildCredentialJSONRCPMessageHandling(t *testing.T) {
+	Convey("Testing build_credential JSON-RPC message handling", t, func() {
+		const subject = "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F"
+		signer := credential.NewSigner(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)),
+			credential.WithSignerLoader(&jsonld.EmbeddedLoader{Fallback: templateLoader{}}))
+
+		tests := []struct {
+			name      string
+			signer    *credential.Signer
+			mode      AccessMode
+			arguments map[string]any
+			validate  func(response mcp.JSONRPCMessage)
+		}{
+			{
+				name:   "build_credential tool - signed with the server key",
+				signer: signer,
+				arguments: map[string]any{
+					"template": "zone-description",
+					"subject":  subject,
+					"claims":   map[string]any{"hasTitle": "Collaborative zone"},
+					"sign":     true,
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					vc := credentialFromResponse(response)
+					So(vc["issuer"], ShouldEqual, signer.DID())
+					So(vc["type"], ShouldResemble, []any{"VerifiableCredential", "ZoneDescriptionCredential"})
+					So(vc["credentialSubject"], ShouldResemble, map[string]any{"id": subject, "hasTitle": "Collaborative zone"})
+					So(vc["proof"], ShouldContainKey, "proofValue")
+				},
+			},
+			{
+				name: "build_credential tool - unsigned",
+				arguments: map[string]any{
+					"template":       "governance-text",
+					"id":             "urn:uuid:1",
+					"issuer":         "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+					"subject":        subject,
+					"claims":         map[string]any{"fromGovernance": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"},
+					"expirationDate": "2030-01-01T00:00:00+01:00",
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					vc := credentialFromResponse(response)
+					So(vc["id"], ShouldEqual, "urn:uuid:1")
+					So(vc["expirationDate"], ShouldEqual, "2029-12-31T23:00:00Z")
+					So(vc, ShouldNotContainKey, "proof")
+				},
+			},
+			{
+				name:   "build_credential tool - not signed unless asked",
+				signer: signer,
+				arguments: map[string]any{
+					"template": "zone-description",
+					"subject":  subject,
+					"claims":   map[string]any{"hasTitle": "Collaborative zone"},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					vc := credentialFromResponse(response)
+					So(vc["issuer"], ShouldEqual, signer.DID())
+					So(vc, ShouldNotContainKey, "proof")
+				},
+			},
+			{
+				name:   "build_credential tool - with a signing key in read-only mode",
+				signer: signer,
+				mode:   ReadOnly,
+				arguments: map[string]any{
+					"template": "zone-description",
+					"subject":  subject,
+					"claims":   map[string]any{"hasTitle": "Collaborative zone"},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"The server is in read-only mode; tool build_credential cannot be invoked.")
+					So(response, ShouldHaveErrorCode, ErrorCodeAccessDenied)
+				},
+			},
+			{
+				name: "build_credential tool - signing without key",
+				arguments: map[string]any{
+					"template": "zone-description",
+					"issuer":   "did:key:z6MkeTG3bFFSLYVU7VqhgZxqr6YzpaGrQtFMh1uvqGy1vDnP",
+					"subject":  subject,
+					"claims":   map[string]any{"hasTitle": "Collaborative zone"},
+					"sign":     true,
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText, "no signing key configured")
+					So(response, ShouldHaveErrorCode, ErrorCodeNoSigningKey)
+				},
+			},
+			{
+				name:   "build_credential tool - invalid claims",
+				signer: signer,
+				arguments: map[string]any{
+					"template": "dataset-description",
+					"subject":  subject,
+					"claims":   map[string]any{"hasTag": "weather"},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText, "invalid claims: hasTitle: required")
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidClaims)
+				},
+			},
+			{
+				name:   "build_credential tool - invalid expiration date",
+				signer: signer,
+				arguments: map[string]any{
+					"template":       "zone-description",
+					"subject":        subject,
+					"claims":         map[string]any{"hasTitle": "Collaborative zone"},
+					"expirationDate": "tomorrow",
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						`invalid claims: expirationDate: parsing time "tomorrow" as "2006-01-02T15:04:05Z07:00": `+
+							`cannot parse "tomorrow" as "2006"`)
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidClaims)
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Given a new server for %s", tt.name), func() {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+
+				var opts []Option
+				if tt.signer != nil {
+					opts = append(opts, WithCredentialSigner(tt.signer))
+				}
+				s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), tt.mode, opts...)
+				So(err, ShouldBeNil)
+
+				messageBytes, err := json.Marshal(mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name":      "build_credential",
+						"arguments": tt.arguments,
+					},
+				})
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("When handling %s message", tt.name), func() {
+					got := s.HandleMessage(goctx.Background(), messageBytes)
+					Convey("Then the response should be valid", func() {
+						tt.validate(got)
+					})
+				})
+			})
+		}
+	})
+}
@@ -6,10 +6,12 @@ import (
 	"fmt"
 
 	dataverseschema "github.com/axone-protocol/axone-contract-schema/go/dataverse-schema/v6"
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
 	"github.com/axone-protocol/axone-mcp/internal/axone/dataverse"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func getDataverse(cc grpc.ClientConnInterface) server.ServerTool {
@@ -23,17 +25,29 @@ func getDataverse(cc grpc.ClientConnInterface) server.ServerTool {
 		}),
 		mcp.WithString(dataverseAddressParam,
 			mcp.Required(),
-			mcp.Description("The address of the dataverse contract")),
+			mcp.Description("The address of the dataverse contract"),
+			mcp.Pattern(address.ContractPattern)),
+		withHeightParam(),
 	)
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		dataverseAddress, err := request.RequireString(dataverseAddressParam)
 		if err != nil {
 			return nil, err
 		}
+		if err := address.ValidateContract(dataverseAddress); err != nil {
+			return toolResultError(err), nil
+		}
+
+		ctx, err = atRequestedHeight(ctx, request)
+		if err != nil {
+			return toolResultError(err), nil
+		}
 
-		dataverseInfo, err := dataverse.Dataverse(ctx, cc, dataverseAddress, ref(dataverseschema.QueryMsg_Dataverse{}))
+		var header metadata.MD
+		dataverseInfo, err := dataverse.Dataverse(ctx, cc, dataverseAddress, ref(dataverseschema.QueryMsg_Dataverse{}),
+			grpc.Header(&header))
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return toolResultError(err), nil
 		}
 
 		r, err := json.Marshal(dataverseInfo)
@@ -41,7 +55,7 @@ func getDataverse(cc grpc.ClientConnInterface) server.ServerTool {
 			return nil, fmt.Errorf("failed to marshal response: %w", err)
 		}
 
-		return mcp.NewToolResultText(string(r)), nil
+		return withAnsweredHeight(mcp.NewToolResultText(string(r)), header), nil
 	}
 
 	return server.ServerTool{Tool: tool, Handler: handler}
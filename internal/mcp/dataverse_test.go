@@ -2,26 +2,27 @@ package mcp
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"testing"
 
 	goctx "context"
 
-	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/axone-protocol/axone-mcp/internal/recording"
 	"github.com/mark3labs/mcp-go/mcp"
 	. "github.com/smartystreets/goconvey/convey"
-	"go.uber.org/mock/gomock"
 )
 
 func TestDataverseJSONRCPMessageHandling(t *testing.T) {
 	requestId := mcp.NewRequestId("42")
 
 	Convey("Testing governance JSON-RPC message handling", t, func() {
+		// the queries to the node are replayed from a fixture, as recorded by serve --record.
+		fixture, err := recording.Load("testdata/dataverse.json")
+		So(err, ShouldBeNil)
+
 		tests := []struct {
 			name     string
 			message  mcp.JSONRPCMessage
-			fixture  func(connInterface *mocks.MockClientConnInterface)
 			validate func(response mcp.JSONRPCMessage)
 		}{
 			{
@@ -39,18 +40,100 @@ func TestDataverseJSONRCPMessageHandling(t *testing.T) {
 						},
 					},
 				},
-				fixture: func(cc *mocks.MockClientConnInterface) {
-					expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
-						`{"dataverse":{}}`,
-						`{"name": "dataverse-42", "triplestore_address":"axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"}`,
-						nil)
-				},
 				validate: func(response mcp.JSONRPCMessage) {
 					So(response, ShouldBeJSONRPCResponseSuccessWithText, `{"name":"dataverse-42","triplestore_address":"axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"}`)
 				},
 			},
 			{
-				name: "get_dataverse_info tool - err1",
+				name: "get_dataverse_info tool - at height",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_dataverse_info",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"height":    1234,
+						},
+					},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, `{"name":"dataverse-42","triplestore_address":""}`)
+					So(response.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult).Meta["height"], ShouldEqual, 1234)
+				},
+			},
+			{
+				name: "get_dataverse_info tool - pruned height",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_dataverse_info",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"height":    12,
+						},
+					},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"query dataverse (axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w): state not available at the requested height: height 12 has been pruned by the node or is in the future "+
+							"(failed to load state at height 12; version does not exist)")
+					So(response, ShouldHaveErrorCode, ErrorCodeHeightUnavailable)
+				},
+			},
+			{
+				name: "get_dataverse_info tool - invalid height",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_dataverse_info",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"height":    -1,
+						},
+					},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						`argument "height" must be a positive integer block height, got -1`)
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidArgument)
+				},
+			},
+			{
+				name: "get_dataverse_info tool - zero height",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_dataverse_info",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"height":    0,
+						},
+					},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						`argument "height" must be a positive integer block height, got 0`)
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidArgument)
+				},
+			},
+			{
+				name: "get_dataverse_info tool - non-integral height",
 				message: mcp.JSONRPCRequest{
 					JSONRPC: mcp.JSONRPC_VERSION,
 					ID:      requestId,
@@ -61,17 +144,55 @@ func TestDataverseJSONRCPMessageHandling(t *testing.T) {
 						"name": "get_dataverse_info",
 						"arguments": map[string]interface{}{
 							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"height":    12.7,
+						},
+					},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						`argument "height" must be a positive integer block height, got 12.7`)
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidArgument)
+				},
+			},
+			{
+				name: "get_dataverse_info tool - err1",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_dataverse_info",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n",
 						},
 					},
 				},
-				fixture: func(cc *mocks.MockClientConnInterface) {
-					expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
-						`{"dataverse":{}}`,
-						"",
-						errors.New("err1"))
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"query dataverse (axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n): err1")
+				},
+			},
+			{
+				name: "get_dataverse_info tool - invalid address",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_dataverse_info",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqs9vgnq",
+						},
+					},
 				},
 				validate: func(response mcp.JSONRPCMessage) {
-					So(response, ShouldBeJSONRPCResponseErrorWithText, "err1")
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						`invalid address "axone1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqs9vgnq": 20 bytes long, expected 32`)
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidAddress)
 				},
 			},
 			{
@@ -94,14 +215,7 @@ func TestDataverseJSONRCPMessageHandling(t *testing.T) {
 
 		for _, tt := range tests {
 			Convey(fmt.Sprintf("Given a new server for %s", tt.name), func() {
-				ctrl := gomock.NewController(t)
-				Reset(ctrl.Finish)
-
-				cc := mocks.NewMockClientConnInterface(ctrl)
-				if tt.fixture != nil {
-					tt.fixture(cc)
-				}
-				s, err := NewServer(cc, ReadWrite)
+				s, err := NewServer(recording.NewReplayer(fixture), ReadWrite)
 				So(err, ShouldBeNil)
 
 				messageBytes, err := json.Marshal(tt.message)
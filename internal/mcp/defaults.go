@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/samber/lo"
+)
+
+// WithDefaultArgument returns the server option giving the argument, in the calls omitting it, the default value of
+// the tool, if any. The default is set before the policy is enforced, so that it is subject to the same restrictions
+// as an explicit value.
+func WithDefaultArgument(name string, defaultValue func(tool string) string) server.ServerOption {
+	return server.WithToolHandlerMiddleware(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return next(ctx, withDefaultArgument(request, name, defaultValue(request.Params.Name)))
+		}
+	})
+}
+
+// withDefaultArgument returns the request giving the argument the given value, if not empty, when omitted or empty.
+func withDefaultArgument(request mcp.CallToolRequest, name, value string) mcp.CallToolRequest {
+	args := request.GetArguments()
+	if v, ok := args[name]; (!ok || v == "") && value != "" {
+		args = maps.Clone(args)
+		if args == nil {
+			args = map[string]any{}
+		}
+		args[name] = value
+		request.Params.Arguments = args
+	}
+
+	return request
+}
+
+// wrapToolWithDefaultArgument rewrites the input schema of the tool, if it takes the argument, so that the argument is
+// no longer required and documents its default value.
+func wrapToolWithDefaultArgument(name, value string) func(srvTool server.ServerTool, _ int) server.ServerTool {
+	return func(srvTool server.ServerTool, _ int) server.ServerTool {
+		schema := srvTool.Tool.InputSchema
+		property, ok := schema.Properties[name].(map[string]any)
+		if !ok {
+			return srvTool
+		}
+
+		property = maps.Clone(property)
+		property["default"] = value
+		if description, ok := property["description"].(string); ok {
+			property["description"] = fmt.Sprintf("%s (defaults to %s)", description, value)
+		}
+		schema.Properties = maps.Clone(schema.Properties)
+		schema.Properties[name] = property
+		schema.Required = slices.DeleteFunc(slices.Clone(schema.Required), func(r string) bool { return r == name })
+		srvTool.Tool.InputSchema = schema
+
+		return srvTool
+	}
+}
+
+// toolsTaking returns the names of the tools taking the given argument.
+func toolsTaking(tools []server.ServerTool, name string) []string {
+	return lo.FilterMap(tools, func(srvTool server.ServerTool, _ int) (string, bool) {
+		_, ok := srvTool.Tool.InputSchema.Properties[name]
+		return srvTool.Tool.Name, ok
+	})
+}
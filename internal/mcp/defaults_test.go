@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	goctx "context"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/axone-protocol/axone-mcp/internal/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/samber/lo"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+const defaultDataverse = "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"
+
+func TestDefaultDataverse(t *testing.T) {
+	Convey("Given a server with a default dataverse", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		cc := mocks.NewMockClientConnInterface(ctrl)
+		s, err := NewServer(cc, ReadWrite, WithDefaultDataverse(defaultDataverse))
+		So(err, ShouldBeNil)
+
+		Convey("When listing the tools", func() {
+			got := s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":"42","method":"tools/list"}`))
+
+			Convey("Then the dataverse argument should be optional, with the default address", func() {
+				resp, ok := got.(mcp.JSONRPCResponse)
+				So(ok, ShouldBeTrue)
+				ctr, ok := resp.Result.(mcp.ListToolsResult)
+				So(ok, ShouldBeTrue)
+				tools := lo.KeyBy(ctr.Tools, func(t mcp.Tool) string { return t.Name })
+
+				for _, name := range []string{"get_dataverse_info", "get_resource_governance_code", "search_resources"} {
+					schema := tools[name].InputSchema
+					So(schema.Required, ShouldNotContain, "dataverse")
+					property, ok := schema.Properties["dataverse"].(map[string]any)
+					So(ok, ShouldBeTrue)
+					So(property["default"], ShouldEqual, defaultDataverse)
+					So(property["description"], ShouldEndWith, "(defaults to "+defaultDataverse+")")
+				}
+				So(tools["get_resource_governance_code"].InputSchema.Required, ShouldContain, "resource")
+				So(tools["resolve_did"].InputSchema.Properties, ShouldNotContainKey, "dataverse")
+			})
+		})
+
+		Convey("When a tool is called without dataverse", func() {
+			expectClientConn(cc, defaultDataverse, `{"dataverse":{}}`, `{"name":"dataverse-42"}`, nil)
+
+			got := s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":"42","method":"tools/call",`+
+				`"params":{"name":"get_dataverse_info"}}`))
+
+			Convey("Then the default dataverse should be queried", func() {
+				So(got, ShouldBeJSONRPCResponseSuccessWithText, `{"name":"dataverse-42","triplestore_address":""}`)
+			})
+		})
+	})
+
+	Convey("Given a server with a default dataverse not granted by its policy", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		p, err := policy.Parse([]byte(`
+rules:
+  - principals: ["*"]
+    tools: ["*"]
+    dataverses: ["axone1other"]
+`))
+		So(err, ShouldBeNil)
+		s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), ReadWrite,
+			WithDefaultDataverse(defaultDataverse), WithPolicy(p))
+		So(err, ShouldBeNil)
+
+		Convey("When a tool is called without dataverse", func() {
+			got := s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":"42","method":"tools/call",`+
+				`"params":{"name":"get_dataverse_info"}}`))
+
+			Convey("Then the call should be denied", func() {
+				So(got, ShouldHaveErrorCode, ErrorCodeAccessDenied)
+			})
+		})
+
+		Convey("When a tool is called with a dataverse which is not a string", func() {
+			got := s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":"42","method":"tools/call",`+
+				`"params":{"name":"get_dataverse_info","arguments":{"dataverse":["axone1other"]}}}`))
+
+			Convey("Then the call should be denied", func() {
+				So(got, ShouldBeJSONRPCResponseErrorWithText, "access denied: dataverse [axone1other] is not a string")
+				So(got, ShouldHaveErrorCode, ErrorCodeAccessDenied)
+			})
+		})
+	})
+}
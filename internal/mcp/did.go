@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/grpc"
+)
+
+func resolveDID(resolver *did.Resolver) serverToolFactory {
+	return func(_ grpc.ClientConnInterface) server.ServerTool {
+		const didParam = "did"
+		tool := mcp.NewTool("resolve_did",
+			mcp.WithDescription(`Resolve the given DID (did:key, did:web or did:axone) into its W3C DID document`),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:         "Resolve a DID",
+				ReadOnlyHint:  mcp.ToBoolPtr(true),
+				OpenWorldHint: mcp.ToBoolPtr(true),
+			}),
+			mcp.WithString(didParam,
+				mcp.Required(),
+				mcp.Description("The DID to resolve"),
+				mcp.Pattern(did.Pattern)),
+		)
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id, err := request.RequireString(didParam)
+			if err != nil {
+				return nil, err
+			}
+
+			doc, err := resolver.Resolve(ctx, id)
+			if err != nil {
+				return toolResultError(err), nil
+			}
+
+			r, err := json.Marshal(doc)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+
+		return server.ServerTool{Tool: tool, Handler: handler}
+	}
+}
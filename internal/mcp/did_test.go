@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	goctx "context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDIDJSONRCPMessageHandling(t *testing.T) {
+	Convey("Testing DID JSON-RPC message handling", t, func() {
+		tests := []struct {
+			name     string
+			did      string
+			validate func(response mcp.JSONRPCMessage)
+		}{
+			{
+				name: "resolve_did tool",
+				did:  "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F",
+				validate: func(response mcp.JSONRPCMessage) {
+					const vmID = "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F" +
+						"#zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F"
+					So(response, ShouldBeJSONRPCResponseSuccessWithText,
+						`{"@context":["https://www.w3.org/ns/did/v1","https://w3id.org/security/suites/secp256k1-2019/v1"],`+
+							`"id":"did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F",`+
+							`"verificationMethod":[{"id":"`+vmID+`","type":"EcdsaSecp256k1VerificationKey2019",`+
+							`"controller":"did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F",`+
+							`"publicKeyMultibase":"zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F"}],`+
+							`"authentication":["`+vmID+`"],"assertionMethod":["`+vmID+`"],`+
+							`"capabilityInvocation":["`+vmID+`"],"capabilityDelegation":["`+vmID+`"]}`)
+				},
+			},
+			{
+				name: "resolve_did tool - invalid DID",
+				did:  "did:axone:axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						`invalid DID "did:axone:axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w": `+
+							`invalid address "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w": `+
+							`32 bytes long, expected 20`)
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidDID)
+				},
+			},
+			{
+				name: "resolve_did tool - unsupported method",
+				did:  "did:example:123",
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText, "unsupported DID method: example")
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidDID)
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Given a new server for %s", tt.name), func() {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+
+				s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), ReadOnly)
+				So(err, ShouldBeNil)
+
+				messageBytes, err := json.Marshal(mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name":      "resolve_did",
+						"arguments": map[string]interface{}{"did": tt.did},
+					},
+				})
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("When handling %s message", tt.name), func() {
+					got := s.HandleMessage(goctx.Background(), messageBytes)
+					Convey("Then the response should be valid", func() {
+						tt.validate(got)
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/axone-protocol/axone-mcp/internal/axone/dataverse"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/axone-protocol/axone-mcp/internal/axone/wasm"
+	"github.com/axone-protocol/axone-mcp/internal/credential"
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/axone-protocol/axone-mcp/internal/embedding"
+	"github.com/axone-protocol/axone-mcp/internal/grpcpool"
+	"github.com/axone-protocol/axone-mcp/internal/policy"
+	"github.com/axone-protocol/axone-mcp/internal/ratelimit"
+	"github.com/axone-protocol/axone-mcp/internal/search"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCode identifies a class of tool failures, stable across releases so that agents can branch on it.
+type ErrorCode string
+
+const (
+	ErrorCodeContractNotFound    ErrorCode = "CONTRACT_NOT_FOUND"
+	ErrorCodeNoGovernance        ErrorCode = "NO_GOVERNANCE"
+	ErrorCodeNodeUnavailable     ErrorCode = "NODE_UNAVAILABLE"
+	ErrorCodeOutOfGas            ErrorCode = "OUT_OF_GAS"
+	ErrorCodeInvalidAddress      ErrorCode = "INVALID_ADDRESS"
+	ErrorCodeInvalidDID          ErrorCode = "INVALID_DID"
+	ErrorCodeDIDNotFound         ErrorCode = "DID_NOT_FOUND"
+	ErrorCodeInvalidCredential   ErrorCode = "INVALID_CREDENTIAL"
+	ErrorCodeInvalidClaims       ErrorCode = "INVALID_CLAIMS"
+	ErrorCodeNoSigningKey        ErrorCode = "NO_SIGNING_KEY"
+	ErrorCodeDecodeFailure       ErrorCode = "DECODE_FAILURE"
+	ErrorCodeEmbedderUnavailable ErrorCode = "EMBEDDER_UNAVAILABLE"
+	ErrorCodeHeightUnavailable   ErrorCode = "HEIGHT_UNAVAILABLE"
+	ErrorCodeInvalidArgument     ErrorCode = "INVALID_ARGUMENT"
+	ErrorCodeAccessDenied        ErrorCode = "ACCESS_DENIED"
+	ErrorCodeRateLimited         ErrorCode = "RATE_LIMITED"
+	ErrorCodeInternal            ErrorCode = "INTERNAL"
+)
+
+// errorMetaKey is the key of the result metadata holding the code and hint of a tool failure.
+const errorMetaKey = "error"
+
+var hints = map[ErrorCode]string{
+	ErrorCodeContractNotFound: "Check the contract address, and that the contract is deployed on the network the " +
+		"server is connected to.",
+	ErrorCodeNoGovernance:      "The resource has no governance attached; nothing restricts its usage.",
+	ErrorCodeNodeUnavailable:   "The Axone node cannot be reached; retry later.",
+	ErrorCodeOutOfGas:          "The query is too expensive for the node; narrow it down.",
+	ErrorCodeInvalidAddress:    "Provide a valid bech32 address with the axone prefix.",
+	ErrorCodeInvalidDID:        "Provide a valid DID, such as did:key:z... or did:web:example.com.",
+	ErrorCodeDIDNotFound:       "No DID document is published for this DID; check it with its controller.",
+	ErrorCodeInvalidCredential: "Provide a JSON-LD Verifiable Credential or Presentation, as a JSON object.",
+	ErrorCodeInvalidClaims: "Give the claims listed for the template, with values of the expected kind; see the " +
+		"build_credential tool description.",
+	ErrorCodeNoSigningKey: "The server has no signing key (--signing-key); build the credential with sign set to " +
+		"false and sign it elsewhere.",
+	ErrorCodeDecodeFailure:       "The contract answered in an unexpected format; check it is of the expected kind.",
+	ErrorCodeEmbedderUnavailable: "The embedding backend cannot be reached; retry later, or use search_resources.",
+	ErrorCodeHeightUnavailable:   "Query a more recent height, or use an archive node.",
+	ErrorCodeInvalidArgument:     "Check the arguments against the input schema of the tool.",
+	ErrorCodeAccessDenied:        "The caller is not allowed to perform this call; ask the operator for access.",
+	ErrorCodeRateLimited:         "Too many calls; retry after the delay given in retryAfter.",
+	ErrorCodeInternal:            "Unexpected failure; retry, and report it to the operator if it persists.",
+}
+
+// grpcStatusPrefix matches the technical prefix of the gRPC errors, not meaningful to agents.
+var grpcStatusPrefix = regexp.MustCompile(`rpc error: code = \w+ desc = `)
+
+// ToolError is a tool failure classified with a stable code and a remediation hint.
+type ToolError struct {
+	Code ErrorCode
+	Hint string
+	Err  error
+}
+
+func (e *ToolError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ToolError) Unwrap() error {
+	return e.Err
+}
+
+// newToolError classifies the given error with the given code and the default hint of the code.
+func newToolError(code ErrorCode, err error) *ToolError {
+	return &ToolError{Code: code, Hint: hints[code], Err: err}
+}
+
+// classifyError returns the classification of the given error, inferred from its chain if not already classified.
+//
+//nolint:cyclop
+func classifyError(err error) *ToolError {
+	if toolErr := (*ToolError)(nil); errors.As(err, &toolErr) {
+		return toolErr
+	}
+
+	msg := strings.ToLower(err.Error())
+	code := status.Code(err)
+
+	switch {
+	case errors.Is(err, cognitarium.ErrNoResult):
+		return newToolError(ErrorCodeNoGovernance, err)
+	case errors.Is(err, height.ErrPruned):
+		return newToolError(ErrorCodeHeightUnavailable, err)
+	case errors.Is(err, wasm.ErrOutOfGas):
+		return newToolError(ErrorCodeOutOfGas, err)
+	case errors.Is(err, did.ErrInvalidDID), errors.Is(err, did.ErrUnsupportedMethod):
+		return newToolError(ErrorCodeInvalidDID, err)
+	case errors.Is(err, address.ErrInvalidAddress):
+		return newToolError(ErrorCodeInvalidAddress, err)
+	case errors.Is(err, did.ErrNotFound):
+		return newToolError(ErrorCodeDIDNotFound, err)
+	case errors.Is(err, credential.ErrInvalidCredential):
+		return newToolError(ErrorCodeInvalidCredential, err)
+	case errors.Is(err, credential.ErrInvalidClaims), errors.Is(err, credential.ErrUnknownTemplate):
+		return newToolError(ErrorCodeInvalidClaims, err)
+	case errors.Is(err, dataverse.ErrNoTriplestore):
+		return &ToolError{
+			Code: ErrorCodeContractNotFound,
+			Hint: "The dataverse has no triplestore attached; check the dataverse address.",
+			Err:  err,
+		}
+	case errors.Is(err, search.ErrTooManyDataverses):
+		return newToolError(ErrorCodeAccessDenied, err)
+	case errors.Is(err, wasm.ErrDecode):
+		return newToolError(ErrorCodeDecodeFailure, err)
+	case errors.Is(err, embedding.ErrUnavailable):
+		return newToolError(ErrorCodeEmbedderUnavailable, err)
+	case errors.Is(err, policy.ErrDenied):
+		return newToolError(ErrorCodeAccessDenied, err)
+	case errors.As(err, new(*ratelimit.ExceededError)):
+		return newToolError(ErrorCodeRateLimited, err)
+	case errors.Is(err, grpcpool.ErrNoEndpoint), code == codes.Unavailable, code == codes.DeadlineExceeded:
+		return newToolError(ErrorCodeNodeUnavailable, err)
+	case strings.Contains(msg, "bech32"), strings.Contains(msg, "invalid address"),
+		strings.Contains(msg, "empty address"):
+		return newToolError(ErrorCodeInvalidAddress, err)
+	case code == codes.NotFound, strings.Contains(msg, "no such contract"):
+		return newToolError(ErrorCodeContractNotFound, err)
+	default:
+		return newToolError(ErrorCodeInternal, err)
+	}
+}
+
+// toolResultError returns the tool result reporting the given error, holding its code and hint in the result metadata.
+func toolResultError(err error) *mcp.CallToolResult {
+	toolErr := classifyError(err)
+
+	result := mcp.NewToolResultError(grpcStatusPrefix.ReplaceAllString(toolErr.Error(), ""))
+	result.Meta = map[string]any{
+		errorMetaKey: map[string]any{
+			"code": toolErr.Code,
+			"hint": toolErr.Hint,
+		},
+	}
+
+	return result
+}
+
+// resultErrorCode returns the code of the error reported by the tool result, INTERNAL if not classified.
+func resultErrorCode(result *mcp.CallToolResult) ErrorCode {
+	if meta, ok := result.Meta[errorMetaKey].(map[string]any); ok {
+		if c, ok := meta["code"].(ErrorCode); ok {
+			return c
+		}
+	}
+
+	return ErrorCodeInternal
+}
+
+// WithFailureLogging returns the server option logging the failed tool calls along with the number of failures of
+// the same code since the server started, as counted by the given function.
+func WithFailureLogging(count func(ErrorCode) uint64) server.ServerOption {
+	return server.WithToolHandlerMiddleware(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, request)
+			if err != nil || result == nil || !result.IsError {
+				return result, err
+			}
+
+			code := resultErrorCode(result)
+			subject := subjectFromContext(ctx)
+			log.Logger.Warn().
+				Str("session_id", subject.SessionID).
+				Str("principal", subject.Principal).
+				Str("tool", request.Params.Name).
+				Str("code", string(code)).
+				Uint64("failures", count(code)).
+				Msg("tool call failed")
+
+			return result, nil
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/axone-protocol/axone-mcp/internal/axone/wasm"
+	"github.com/axone-protocol/axone-mcp/internal/grpcpool"
+	"github.com/axone-protocol/axone-mcp/internal/policy"
+	"github.com/axone-protocol/axone-mcp/internal/ratelimit"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClassifyError(t *testing.T) {
+	Convey("Given errors raised while serving tool calls", t, func() {
+		cases := []struct {
+			err  error
+			code ErrorCode
+		}{
+			{fmt.Errorf("query select (axone1): %w", cognitarium.ErrNoResult), ErrorCodeNoGovernance},
+			{fmt.Errorf("%w: height 12", height.ErrPruned), ErrorCodeHeightUnavailable},
+			{fmt.Errorf("query foo (axone1): %w", wasm.ErrOutOfGas), ErrorCodeOutOfGas},
+			{fmt.Errorf("query foo (axone1): %w: boom", wasm.ErrDecode), ErrorCodeDecodeFailure},
+			{fmt.Errorf("%w: tool foo is not allowed", policy.ErrDenied), ErrorCodeAccessDenied},
+			{&ratelimit.ExceededError{Scope: "session", Key: "s1", RetryAfter: time.Second}, ErrorCodeRateLimited},
+			{grpcpool.ErrNoEndpoint, ErrorCodeNodeUnavailable},
+			{fmt.Errorf("query foo (axone1): %w", status.Error(codes.Unavailable, "connection refused")),
+				ErrorCodeNodeUnavailable},
+			{status.Error(codes.InvalidArgument, "decoding bech32 failed: invalid checksum"), ErrorCodeInvalidAddress},
+			{status.Error(codes.Unknown, "no such contract: axone1"), ErrorCodeContractNotFound},
+			{status.Error(codes.NotFound, "not found"), ErrorCodeContractNotFound},
+			{errors.New("boom"), ErrorCodeInternal},
+			{newToolError(ErrorCodeOutOfGas, errors.New("already classified")), ErrorCodeOutOfGas},
+		}
+
+		for _, c := range cases {
+			Convey(fmt.Sprintf("When classifying %q", c.err), func() {
+				got := classifyError(c.err)
+
+				Convey(fmt.Sprintf("Then it should be classified as %s, with a hint", c.code), func() {
+					So(got.Code, ShouldEqual, c.code)
+					So(got.Hint, ShouldNotBeBlank)
+					So(errors.Is(got, c.err), ShouldBeTrue)
+				})
+			})
+		}
+	})
+
+	Convey("Given a gRPC error", t, func() {
+		err := fmt.Errorf("query foo (axone1): %w", status.Error(codes.Unavailable, "connection refused"))
+
+		Convey("When reporting it as a tool result", func() {
+			result := toolResultError(err)
+
+			Convey("Then the gRPC technical prefix should be hidden, and the code reported in metadata", func() {
+				So(result.IsError, ShouldBeTrue)
+				So(result.Content, ShouldHaveLength, 1)
+				So(result.Content[0].(mcp.TextContent).Text, ShouldEqual, "query foo (axone1): connection refused")
+				So(result.Meta[errorMetaKey], ShouldResemble, map[string]any{
+					"code": ErrorCodeNodeUnavailable,
+					"hint": hints[ErrorCodeNodeUnavailable],
+				})
+			})
+		})
+	})
+}
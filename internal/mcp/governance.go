@@ -5,14 +5,16 @@ import (
 	"encoding/base64"
 	"fmt"
 
-	dataverseschema "github.com/axone-protocol/axone-contract-schema/go/dataverse-schema/v6"
 	lawstoneschema "github.com/axone-protocol/axone-contract-schema/go/law-stone-schema/v6"
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
 	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
 	"github.com/axone-protocol/axone-mcp/internal/axone/dataverse"
 	"github.com/axone-protocol/axone-mcp/internal/axone/lawstone"
+	"github.com/axone-protocol/axone-mcp/internal/did"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func getGovernanceCode(cc grpc.ClientConnInterface) server.ServerTool {
@@ -27,47 +29,61 @@ func getGovernanceCode(cc grpc.ClientConnInterface) server.ServerTool {
 		}),
 		mcp.WithString(dataverseAddressParam,
 			mcp.Required(),
-			mcp.Description("The address of the dataverse contract")),
+			mcp.Description("The address of the dataverse contract"),
+			mcp.Pattern(address.ContractPattern)),
 		mcp.WithString(resourceParam,
 			mcp.Required(),
-			mcp.Description("The DID URI of the resource")),
+			mcp.Description("The DID URI of the resource"),
+			mcp.Pattern(did.Pattern)),
+		withHeightParam(),
 	)
 	handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		dataverseAddress, err := request.RequireString(dataverseAddressParam)
 		if err != nil {
 			return nil, err
 		}
+		if err := address.ValidateContract(dataverseAddress); err != nil {
+			return toolResultError(err), nil
+		}
 
 		resourceDID, err := request.RequireString(resourceParam)
 		if err != nil {
 			return nil, err
 		}
+		if err := did.Validate(resourceDID); err != nil {
+			return toolResultError(err), nil
+		}
 
-		dataverseInfo, err := dataverse.Dataverse(ctx, cc, dataverseAddress, ref(dataverseschema.QueryMsg_Dataverse{}))
+		ctx, err = atRequestedHeight(ctx, request)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return toolResultError(err), nil
 		}
 
-		cognitariumAddress := string(dataverseInfo.TriplestoreAddress)
-		if cognitariumAddress == "" {
-			return mcp.NewToolResultError("no triplestore address found"), nil
+		var header metadata.MD
+		cognitariumAddress, err := dataverse.Triplestore(ctx, cc, dataverseAddress, grpc.Header(&header))
+		if err != nil {
+			return toolResultError(err), nil
 		}
+		ctx = atAnsweredHeight(ctx, header)
 
-		lawstoneAddress, err := cognitarium.GetGovernanceAddressForResource(ctx, cc, cognitariumAddress, resourceDID)
+		lawstoneAddress, err := cognitarium.GetGovernanceAddressForResource(ctx, cc, cognitariumAddress, resourceDID,
+			grpc.Header(&header))
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return toolResultError(err), nil
 		}
-		code, err := lawstone.ProgramCode(ctx, cc, lawstoneAddress, ref(lawstoneschema.QueryMsg_ProgramCode{}))
+		code, err := lawstone.ProgramCode(ctx, cc, lawstoneAddress, ref(lawstoneschema.QueryMsg_ProgramCode{}),
+			grpc.Header(&header))
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return toolResultError(err), nil
 		}
 
 		decodedCode, err := base64.StdEncoding.DecodeString(*code)
 		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("failed to decode base64 code '%s': %v", *code, err)), nil
+			return toolResultError(newToolError(ErrorCodeDecodeFailure,
+				fmt.Errorf("failed to decode base64 code '%s': %w", *code, err))), nil
 		}
 
-		return mcp.NewToolResultText(string(decodedCode)), nil
+		return withAnsweredHeight(mcp.NewToolResultText(string(decodedCode)), header), nil
 	}
 
 	return server.ServerTool{Tool: tool, Handler: handler}
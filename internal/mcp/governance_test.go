@@ -13,6 +13,8 @@ import (
 	"github.com/mark3labs/mcp-go/mcp"
 	. "github.com/smartystreets/goconvey/convey"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestGovernanceJSONRCPMessageHandling(t *testing.T) {
@@ -62,6 +64,43 @@ func TestGovernanceJSONRCPMessageHandling(t *testing.T) {
 					So(response, ShouldBeJSONRPCResponseSuccessWithText, "hello(world).")
 				},
 			},
+			{
+				name: "get_resource_governance_code tool - consistent height",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_resource_governance_code",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"resource":  "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F",
+						},
+					},
+				},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectClientConnAtHeight(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+						`{"dataverse":{}}`,
+						`{"triplestore_address":"axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"}`,
+						0, 500)
+
+					expectClientConnAtHeight(cc, "axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n",
+						selectQuery,
+						`{"head":{"vars":["code"]},"results":{"bindings":[{"code":{"type":"uri","value":{"full":"contract:law-stone:axone10tk8kmhhx49jahdyuxnn8d9luc9kxgc5m406k02s0y0ph59rdh7qstpynz"}}}]}}`,
+						500, 500)
+
+					expectClientConnAtHeight(cc, "axone10tk8kmhhx49jahdyuxnn8d9luc9kxgc5m406k02s0y0ph59rdh7qstpynz",
+						`{"program_code":{}}`,
+						fmt.Sprintf(`"%s"`, base64.StdEncoding.EncodeToString([]byte(`hello(world).`))),
+						500, 500)
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, "hello(world).")
+					So(response.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult).Meta["height"], ShouldEqual, 500)
+				},
+			},
 			{
 				name: "get_resource_governance_code tool - err1",
 				message: mcp.JSONRPCRequest{
@@ -85,7 +124,9 @@ func TestGovernanceJSONRCPMessageHandling(t *testing.T) {
 						errors.New("err1"))
 				},
 				validate: func(response mcp.JSONRPCMessage) {
-					So(response, ShouldBeJSONRPCResponseErrorWithText, "err1")
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"query dataverse (axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w): err1")
+					So(response, ShouldHaveErrorCode, ErrorCodeInternal)
 				},
 			},
 			{
@@ -116,7 +157,8 @@ func TestGovernanceJSONRCPMessageHandling(t *testing.T) {
 						errors.New("err2"))
 				},
 				validate: func(response mcp.JSONRPCMessage) {
-					So(response, ShouldBeJSONRPCResponseErrorWithText, "err2")
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"query select (axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n): err2")
 				},
 			},
 			{
@@ -152,7 +194,8 @@ func TestGovernanceJSONRCPMessageHandling(t *testing.T) {
 						errors.New("err3"))
 				},
 				validate: func(response mcp.JSONRPCMessage) {
-					So(response, ShouldBeJSONRPCResponseErrorWithText, "err3")
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"query program_code (axone10tk8kmhhx49jahdyuxnn8d9luc9kxgc5m406k02s0y0ph59rdh7qstpynz): err3")
 				},
 			},
 			{
@@ -189,6 +232,7 @@ func TestGovernanceJSONRCPMessageHandling(t *testing.T) {
 				},
 				validate: func(response mcp.JSONRPCMessage) {
 					So(response, ShouldBeJSONRPCResponseErrorWithText, "failed to decode base64 code '!!not_base64!!': illegal base64 data at input byte 0")
+					So(response, ShouldHaveErrorCode, ErrorCodeDecodeFailure)
 				},
 			},
 			{
@@ -215,6 +259,90 @@ func TestGovernanceJSONRCPMessageHandling(t *testing.T) {
 				},
 				validate: func(response mcp.JSONRPCMessage) {
 					So(response, ShouldBeJSONRPCResponseErrorWithText, "no triplestore address found")
+					So(response, ShouldHaveErrorCode, ErrorCodeContractNotFound)
+				},
+			},
+			{
+				name: "get_resource_governance_code tool - no governance",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_resource_governance_code",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"resource":  "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F",
+						},
+					},
+				},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+						`{"dataverse":{}}`,
+						`{"triplestore_address":"axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"}`,
+						nil)
+
+					expectClientConn(cc, "axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n",
+						selectQuery,
+						`{"head":{"vars":["code"]},"results":{"bindings":[]}}`,
+						nil)
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText, "no result")
+					So(response, ShouldHaveErrorCode, ErrorCodeNoGovernance)
+				},
+			},
+			{
+				name: "get_resource_governance_code tool - contract not found",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_resource_governance_code",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"resource":  "did:key:zQ3shTd79aJSfrNpMVpUVX1xrG9gabc6fmYJS4gFuwUnjKK3F",
+						},
+					},
+				},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+						`{"dataverse":{}}`,
+						"",
+						status.Error(codes.Unknown, "query wasm contract failed: no such contract"))
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						"query dataverse (axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w): "+
+							"query wasm contract failed: no such contract")
+					So(response, ShouldHaveErrorCode, ErrorCodeContractNotFound)
+				},
+			},
+			{
+				name: "get_resource_governance_code tool - invalid resource",
+				message: mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]interface{}{
+						"name": "get_resource_governance_code",
+						"arguments": map[string]interface{}{
+							"dataverse": "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+							"resource":  "did:key:foo",
+						},
+					},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText,
+						`invalid DID "did:key:foo": key is not base58btc multibase encoded`)
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidDID)
 				},
 			},
 			{
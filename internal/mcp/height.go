@@ -0,0 +1,64 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/grpc/metadata"
+)
+
+const heightParam = "height"
+
+// withHeightParam declares the optional argument allowing to query the chain state at a past block height.
+func withHeightParam() mcp.ToolOption {
+	return mcp.WithNumber(heightParam,
+		mcp.Min(1),
+		mcp.Description("The block height to query the chain state at (defaults to the latest height)"))
+}
+
+// atRequestedHeight returns a context pinned to the block height requested by the tool call, if any. A height which is
+// not a positive integer is rejected with INVALID_ARGUMENT, rather than truncated.
+func atRequestedHeight(ctx context.Context, request mcp.CallToolRequest) (context.Context, error) {
+	if _, ok := request.GetArguments()[heightParam]; !ok {
+		return ctx, nil
+	}
+
+	h, err := request.RequireFloat(heightParam)
+	if err != nil {
+		return nil, newToolError(ErrorCodeInvalidArgument, err)
+	}
+	if h != math.Trunc(h) || h < 1 || h >= math.MaxInt64 {
+		return nil, newToolError(ErrorCodeInvalidArgument,
+			fmt.Errorf("argument %q must be a positive integer block height, got %v", heightParam, h))
+	}
+
+	return height.WithHeight(ctx, int64(h)), nil
+}
+
+// atAnsweredHeight returns a context pinned to the block height a previous query was answered at, so that subsequent
+// queries read a consistent state. The context is returned as is if already pinned or if the height is unknown.
+func atAnsweredHeight(ctx context.Context, header metadata.MD) context.Context {
+	if _, ok := height.FromOutgoingContext(ctx); ok {
+		return ctx
+	}
+	if h, ok := height.FromMetadata(header); ok {
+		return height.WithHeight(ctx, h)
+	}
+
+	return ctx
+}
+
+// withAnsweredHeight reports in the result metadata the block height the queries were answered at, if known.
+func withAnsweredHeight(result *mcp.CallToolResult, header metadata.MD) *mcp.CallToolResult {
+	if h, ok := height.FromMetadata(header); ok {
+		if result.Meta == nil {
+			result.Meta = map[string]any{}
+		}
+		result.Meta[heightParam] = h
+	}
+
+	return result
+}
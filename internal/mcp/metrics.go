@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/metrics"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// requestErrorCode labels the tool calls rejected with a JSON-RPC error rather than a failed result, e.g. because of
+// invalid arguments.
+const requestErrorCode = "REQUEST_ERROR"
+
+// WithHooksMetrics records the sessions and the tool calls in the given metrics.
+func WithHooksMetrics(m *metrics.Metrics) HooksRegistrar {
+	// starts holds the start time of the ongoing tool calls, by session and request ID.
+	var starts sync.Map
+	callKey := func(ctx context.Context, id any) string {
+		return subjectFromContext(ctx).SessionID + "/" + fmt.Sprint(id)
+	}
+	elapsed := func(ctx context.Context, id any) time.Duration {
+		if start, ok := starts.LoadAndDelete(callKey(ctx, id)); ok {
+			return time.Since(start.(time.Time)) //nolint:forcetypeassert
+		}
+		return 0
+	}
+
+	return func(hooks *server.Hooks) {
+		hooks.AddOnRegisterSession(func(_ context.Context, _ server.ClientSession) {
+			m.SessionOpened()
+		})
+		hooks.AddOnUnregisterSession(func(_ context.Context, _ server.ClientSession) {
+			m.SessionClosed()
+		})
+		hooks.AddBeforeCallTool(func(ctx context.Context, id any, _ *mcp.CallToolRequest) {
+			starts.Store(callKey(ctx, id), time.Now())
+		})
+		hooks.AddAfterCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest, result *mcp.CallToolResult) {
+			var code string
+			if result != nil && result.IsError {
+				code = string(resultErrorCode(result))
+			}
+			m.ObserveToolCall(message.Params.Name, elapsed(ctx, id), code)
+		})
+		hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
+			request, ok := message.(*mcp.CallToolRequest)
+			if method != mcp.MethodToolsCall || !ok {
+				return
+			}
+			duration := elapsed(ctx, id)
+			// Unknown tools are not recorded, so that callers cannot create arbitrary series.
+			if errors.Is(err, server.ErrToolNotFound) {
+				return
+			}
+			m.ObserveToolCall(request.Params.Name, duration, requestErrorCode)
+		})
+	}
+}
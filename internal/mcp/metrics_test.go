@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	goctx "context"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/metrics"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHooksMetrics(t *testing.T) {
+	Convey("Given a server recording its metrics", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		cc := mocks.NewMockClientConnInterface(ctrl)
+		m := metrics.New()
+		s, err := NewServer(cc, ReadWrite, WithMetrics(m))
+		So(err, ShouldBeNil)
+
+		scrape := func() string {
+			rec := httptest.NewRecorder()
+			m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+			body, _ := io.ReadAll(rec.Body)
+			return string(body)
+		}
+
+		Convey("When tools are called", func() {
+			expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+				`{"dataverse":{}}`, `{"name":"dataverse-42"}`, nil)
+
+			ctx := goctx.Background()
+			s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_dataverse_info",`+
+				`"arguments":{"dataverse":"axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"}}}`))
+			s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_dataverse_info",`+
+				`"arguments":{"dataverse":"axone1foo"}}}`))
+			s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_dataverse_info",`+
+				`"arguments":{}}}`))
+			s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"unknown",`+
+				`"arguments":{}}}`))
+
+			Convey("Then the calls of the known tools should be counted, along with their errors", func() {
+				body := scrape()
+				So(body, ShouldContainSubstring, `axone_mcp_tool_calls_total{tool="get_dataverse_info"} 3`)
+				So(body, ShouldContainSubstring, `axone_mcp_tool_call_duration_seconds_count{tool="get_dataverse_info"} 3`)
+				So(body, ShouldContainSubstring,
+					`axone_mcp_tool_errors_total{code="INVALID_ADDRESS",tool="get_dataverse_info"} 1`)
+				So(body, ShouldContainSubstring,
+					`axone_mcp_tool_errors_total{code="REQUEST_ERROR",tool="get_dataverse_info"} 1`)
+				So(body, ShouldNotContainSubstring, `tool="unknown"`)
+			})
+		})
+
+		Convey("When a session is registered then unregistered", func() {
+			session := fakeSession{sessionID: "1234", notificationChannel: make(chan mcp.JSONRPCNotification)}
+			defer close(session.notificationChannel)
+
+			So(s.RegisterSession(goctx.Background(), session), ShouldBeNil)
+			active := scrape()
+			s.UnregisterSession(goctx.Background(), session.SessionID())
+
+			Convey("Then the active sessions should be tracked", func() {
+				So(active, ShouldContainSubstring, "axone_mcp_active_sessions 1")
+				So(scrape(), ShouldContainSubstring, "axone_mcp_active_sessions 0")
+			})
+		})
+	})
+}
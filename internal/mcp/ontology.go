@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/axone-protocol/axone-mcp/internal/ontology"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/grpc"
+)
+
+const defaultSearchLimit = 20
+
+// ontologyResources returns the modules of the ontology as resources, identified by their IRI.
+func ontologyResources(o *ontology.Ontology) []server.ServerResource {
+	resources := make([]server.ServerResource, 0, len(o.Modules))
+	for _, m := range o.Modules {
+		resources = append(resources, server.ServerResource{
+			Resource: mcp.NewResource(m.IRI, m.Title,
+				mcp.WithResourceDescription(m.Description),
+				mcp.WithMIMEType(ontology.MIMEType)),
+			Handler: func(_ context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+				return []mcp.ResourceContents{mcp.TextResourceContents{
+					URI:      request.Params.URI,
+					MIMEType: ontology.MIMEType,
+					Text:     string(m.Document),
+				}}, nil
+			},
+		})
+	}
+
+	return resources
+}
+
+func searchOntology(o *ontology.Ontology) serverToolFactory {
+	return func(_ grpc.ClientConnInterface) server.ServerTool {
+		const (
+			keywordParam = "keyword"
+			kindParam    = "kind"
+			limitParam   = "limit"
+		)
+		tool := mcp.NewTool("search_ontology",
+			mcp.WithDescription(`Search the classes, properties and thesaurus concepts of the excerpt of the Axone ontology `+
+				`(`+cognitarium.W3IDPrefix+`) embedded in the server, which covers the credential templates, matching a `+
+				`keyword, with their IRI, label, description, domain and range. Use it to find the exact IRIs to use in `+
+				`queries and credentials. The ontology modules are also available as resources, identified by their IRI.`),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:         "Search the ontology",
+				ReadOnlyHint:  mcp.ToBoolPtr(true),
+				OpenWorldHint: mcp.ToBoolPtr(false),
+			}),
+			mcp.WithString(keywordParam,
+				mcp.Required(),
+				mcp.Description("The words to look for in the name, label or description of the entries, "+
+					"e.g. 'governance' or 'media type' (empty to list all the entries)")),
+			mcp.WithString(kindParam,
+				mcp.Enum(string(ontology.KindClass), string(ontology.KindProperty), string(ontology.KindConcept)),
+				mcp.Description("The kind of entries to look for (defaults to all)")),
+			mcp.WithNumber(limitParam,
+				mcp.Min(1),
+				mcp.DefaultNumber(defaultSearchLimit),
+				mcp.Description("The maximum number of entries to return, best matches first")),
+		)
+		handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			keyword, err := request.RequireString(keywordParam)
+			if err != nil {
+				return nil, err
+			}
+			limit := request.GetInt(limitParam, defaultSearchLimit)
+			if limit <= 0 {
+				return nil, fmt.Errorf("argument %q must be positive", limitParam)
+			}
+
+			entries := o.Search(keyword, ontology.Kind(request.GetString(kindParam, "")))
+			r, err := json.Marshal(map[string]any{
+				"total":   len(entries),
+				"entries": entries[:min(limit, len(entries))],
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+
+		return server.ServerTool{Tool: tool, Handler: handler}
+	}
+}
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	goctx "context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestOntologyJSONRCPMessageHandling(t *testing.T) {
+	Convey("Testing ontology JSON-RPC message handling", t, func() {
+		const governance = "https://w3id.org/axone/ontology/v4/schema/credential/governance/text/"
+
+		tests := []struct {
+			name     string
+			method   string
+			params   map[string]any
+			validate func(response mcp.JSONRPCMessage)
+		}{
+			{
+				name:   "search_ontology tool",
+				method: "tools/call",
+				params: map[string]any{
+					"name":      "search_ontology",
+					"arguments": map[string]any{"keyword": "from governance", "kind": "property", "limit": 1},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, `{"entries":[{"iri":"`+governance+`fromGovernance",`+
+						`"kind":"property","label":"from governance","comment":"The law-stone smart contract holding the `+
+						`governance, identified by a cosmwasm:law-stone IRI suffixed by its address.","module":"`+governance+`",`+
+						`"domain":["`+governance+`GovernanceText"],"range":["http://www.w3.org/2001/XMLSchema#anyURI"]}],`+
+						`"total":1}`)
+				},
+			},
+			{
+				name:   "search_ontology tool - no match",
+				method: "tools/call",
+				params: map[string]any{
+					"name":      "search_ontology",
+					"arguments": map[string]any{"keyword": "diploma"},
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, `{"entries":[],"total":0}`)
+				},
+			},
+			{
+				name:   "resources/list",
+				method: "resources/list",
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldHaveSameTypeAs, mcp.JSONRPCResponse{})
+					result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.ListResourcesResult)
+					So(ok, ShouldBeTrue)
+					So(result.Resources, ShouldContain, mcp.Resource{
+						URI:  governance,
+						Name: "Governance text",
+						Description: "Schema of the credentials attaching a governance, written as rules in a law-stone " +
+							"smart contract, to a resource.",
+						MIMEType: "application/ld+json",
+					})
+				},
+			},
+			{
+				name:   "resources/read",
+				method: "resources/read",
+				params: map[string]any{"uri": governance},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldHaveSameTypeAs, mcp.JSONRPCResponse{})
+					result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.ReadResourceResult)
+					So(ok, ShouldBeTrue)
+					So(result.Contents, ShouldHaveLength, 1)
+					contents, ok := result.Contents[0].(mcp.TextResourceContents)
+					So(ok, ShouldBeTrue)
+					So(contents.MIMEType, ShouldEqual, "application/ld+json")
+					So(contents.Text, ShouldContainSubstring, `"@id": "GovernanceTextCredential"`)
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Given a new server for %s", tt.name), func() {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+
+				s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), ReadOnly)
+				So(err, ShouldBeNil)
+
+				messageBytes, err := json.Marshal(mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: tt.method,
+					},
+					Params: tt.params,
+				})
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("When handling %s message", tt.name), func() {
+					got := s.HandleMessage(goctx.Background(), messageBytes)
+					Convey("Then the response should be valid", func() {
+						tt.validate(got)
+					})
+				})
+			})
+		}
+	})
+}
@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type principalKey struct{}
+
+// WithPrincipal returns a new context carrying the authenticated principal of the caller.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the authenticated principal of the caller, or an empty string if anonymous.
+func PrincipalFromContext(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey{}).(string)
+	return principal
+}
+
+// SessionIDFromContext returns the identifier of the MCP session bound to the context, if any.
+func SessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
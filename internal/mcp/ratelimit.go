@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/axone-protocol/axone-mcp/internal/ratelimit"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+// WithRateLimitEnforcement returns the server option rejecting the tool calls exceeding the limits of the given limiter.
+// The rejection is reported as a tool error stating when the call can be retried.
+func WithRateLimitEnforcement(l *ratelimit.Limiter) server.ServerOption {
+	return server.WithToolHandlerMiddleware(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			subject := subjectFromContext(ctx)
+			err := l.Allow(subject.SessionID, subject.Principal, request.Params.Name)
+
+			var exceeded *ratelimit.ExceededError
+			switch {
+			case errors.As(err, &exceeded):
+				log.Logger.Warn().
+					Str("session_id", subject.SessionID).
+					Str("principal", subject.Principal).
+					Str("tool", request.Params.Name).
+					Err(err).
+					Msg("tool call rate limited")
+
+				result := toolResultError(err)
+				result.Meta["retryAfter"] = exceeded.RetryAfter.Seconds()
+				return result, nil
+			case err != nil:
+				return nil, err
+			}
+
+			return next(ctx, request)
+		}
+	})
+}
+
+// WithHooksRateLimit releases the limiter state of the sessions when they end.
+func WithHooksRateLimit(l *ratelimit.Limiter) HooksRegistrar {
+	return func(hooks *server.Hooks) {
+		hooks.AddOnUnregisterSession(func(_ context.Context, session server.ClientSession) {
+			l.Forget(session.SessionID())
+		})
+	}
+}
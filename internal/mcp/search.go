@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
+	"github.com/axone-protocol/axone-mcp/internal/search"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/grpc"
+)
+
+const defaultSearchResourcesLimit = 10
+
+func searchResources(index *search.Index) serverToolFactory {
+	return func(_ grpc.ClientConnInterface) server.ServerTool {
+		const (
+			dataverseAddressParam = "dataverse"
+			queryParam            = "query"
+			limitParam            = "limit"
+			refreshParam          = "refresh"
+		)
+		tool := mcp.NewTool("search_resources",
+			mcp.WithDescription(`Search the resources of the given dataverse (datasets, services, zones...) whose `+
+				`titles, descriptions, tags or other textual claims best match the given keywords. Returns the DIDs of `+
+				`the matching resources, best first, with their title and an excerpt of the matching claims. `+
+				`The claims are indexed the first time a dataverse is searched, and refreshed periodically.`),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:         "Search resources",
+				ReadOnlyHint:  mcp.ToBoolPtr(true),
+				OpenWorldHint: mcp.ToBoolPtr(true),
+			}),
+			mcp.WithString(dataverseAddressParam,
+				mcp.Required(),
+				mcp.Description("The address of the dataverse contract"),
+				mcp.Pattern(address.ContractPattern)),
+			mcp.WithString(queryParam,
+				mcp.Required(),
+				mcp.Description("The keywords to look for, e.g. 'air quality France'")),
+			mcp.WithNumber(limitParam,
+				mcp.Min(1),
+				mcp.DefaultNumber(defaultSearchResourcesLimit),
+				mcp.Description("The maximum number of resources to return")),
+			mcp.WithBoolean(refreshParam,
+				mcp.DefaultBool(false),
+				mcp.Description("Whether to index the claims of the dataverse again before searching")),
+		)
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			dataverseAddress, err := request.RequireString(dataverseAddressParam)
+			if err != nil {
+				return nil, err
+			}
+			if err := address.ValidateContract(dataverseAddress); err != nil {
+				return toolResultError(err), nil
+			}
+
+			query, err := request.RequireString(queryParam)
+			if err != nil {
+				return nil, err
+			}
+			limit := request.GetInt(limitParam, defaultSearchResourcesLimit)
+			if limit <= 0 {
+				return nil, fmt.Errorf("argument %q must be positive", limitParam)
+			}
+
+			if request.GetBool(refreshParam, false) {
+				if _, err := index.Refresh(ctx, dataverseAddress); err != nil {
+					return toolResultError(err), nil
+				}
+			}
+
+			result, err := index.Search(ctx, dataverseAddress, query, limit)
+			if err != nil {
+				return toolResultError(err), nil
+			}
+
+			r, err := json.Marshal(result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+
+		return server.ServerTool{Tool: tool, Handler: handler}
+	}
+}
@@ -0,0 +1,133 @@
+package mcp
+
+import (
+	goctx "context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/axone-protocol/axone-mcp/internal/search"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSearchResourcesJSONRCPMessageHandling(t *testing.T) {
+	Convey("Testing search_resources JSON-RPC message handling", t, func() {
+		const (
+			dataverse   = "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"
+			triplestore = "axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"
+			dataset     = "https://w3id.org/axone/ontology/v4/schema/credential/dataset/description/"
+			selectQuery = `{"select":{"query":{"limit":30,"prefixes":[],"select":[{"variable":"credential"},` +
+				`{"variable":"subject"},{"variable":"property"},{"variable":"value"}],"where":{"bgp":{"patterns":[` +
+				`{"object":{"variable":"subject"},"predicate":{"named_node":{"full":"dataverse:credential:body#subject"}},` +
+				`"subject":{"variable":"credential"}},` +
+				`{"object":{"variable":"claim"},"predicate":{"named_node":{"full":"dataverse:credential:body#claim"}},` +
+				`"subject":{"variable":"credential"}},` +
+				`{"object":{"variable":"value"},"predicate":{"variable":"property"},"subject":{"variable":"claim"}}]}}}}}`
+		)
+		binding := func(credential, subject, property, value string) string {
+			return fmt.Sprintf(`{"credential":{"type":"uri","value":{"full":%q}},`+
+				`"subject":{"type":"uri","value":{"full":%q}},`+
+				`"property":{"type":"uri","value":{"full":%q}},`+
+				`"value":{"type":"literal","value":%q,"xml:lang":"en"}}`, credential, subject, dataset+property, value)
+		}
+		expectIndexing := func(cc *mocks.MockClientConnInterface, times int) {
+			for range times {
+				expectClientConn(cc, dataverse, `{"dataverse":{}}`,
+					`{"name":"dataverse-42","triplestore_address":"`+triplestore+`"}`, nil)
+				expectClientConn(cc, triplestore, selectQuery,
+					`{"head":{"vars":["credential","subject","property","value"]},"results":{"bindings":[`+
+						binding("https://example.org/vc/1", "did:key:z6MkAir", "hasTitle", "Air quality")+","+
+						binding("https://example.org/vc/1", "did:key:z6MkAir", "hasDescription", "Pollutants measured hourly.")+","+
+						binding("https://example.org/vc/2", "did:key:z6MkWater", "hasTitle", "Water quality")+
+						`]}}`, nil)
+			}
+		}
+
+		tests := []struct {
+			name      string
+			arguments map[string]any
+			fixture   func(cc *mocks.MockClientConnInterface)
+			validate  func(response mcp.JSONRPCMessage)
+		}{
+			{
+				name:      "search_resources tool",
+				arguments: map[string]any{"dataverse": dataverse, "query": "air pollutants"},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectIndexing(cc, 1)
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, `{"total":1,"refreshedAt":"2025-06-01T12:00:00Z",`+
+						`"hits":[{"resource":"did:key:z6MkAir","score":0.866,"title":"Air quality",`+
+						`"snippet":"Pollutants measured hourly."}]}`)
+				},
+			},
+			{
+				name:      "search_resources tool - with limit and refresh",
+				arguments: map[string]any{"dataverse": dataverse, "query": "quality", "limit": 1, "refresh": true},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectIndexing(cc, 1)
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, `{"total":2,"refreshedAt":"2025-06-01T12:00:00Z",`+
+						`"hits":[{"resource":"did:key:z6MkAir","score":0.137,"title":"Air quality",`+
+						`"snippet":"Pollutants measured hourly."}]}`)
+				},
+			},
+			{
+				name:      "search_resources tool - no triplestore",
+				arguments: map[string]any{"dataverse": dataverse, "query": "air"},
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectClientConn(cc, dataverse, `{"dataverse":{}}`, `{"name":"dataverse-42","triplestore_address":""}`, nil)
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseErrorWithText, "no triplestore address found")
+					So(response, ShouldHaveErrorCode, ErrorCodeContractNotFound)
+				},
+			},
+			{
+				name:      "search_resources tool - invalid dataverse",
+				arguments: map[string]any{"dataverse": "axone1foo", "query": "air"},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldHaveErrorCode, ErrorCodeInvalidAddress)
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Given a new server for %s", tt.name), func() {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+
+				cc := mocks.NewMockClientConnInterface(ctrl)
+				if tt.fixture != nil {
+					tt.fixture(cc)
+				}
+				index := search.New(cc, search.NewMemoryStore(),
+					search.WithClock(func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }))
+				s, err := NewServer(cc, ReadOnly, WithSearchIndex(index))
+				So(err, ShouldBeNil)
+
+				messageBytes, err := json.Marshal(mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]any{"name": "search_resources", "arguments": tt.arguments},
+				})
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("When handling %s message", tt.name), func() {
+					got := s.HandleMessage(goctx.Background(), messageBytes)
+					Convey("Then the response should be valid", func() {
+						tt.validate(got)
+					})
+				})
+			})
+		}
+	})
+}
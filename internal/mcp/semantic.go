@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/address"
+	"github.com/axone-protocol/axone-mcp/internal/semantic"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"google.golang.org/grpc"
+)
+
+const defaultSemanticSearchK = 5
+
+func semanticSearch(index *semantic.Index) serverToolFactory {
+	return func(_ grpc.ClientConnInterface) server.ServerTool {
+		const (
+			dataverseAddressParam = "dataverse"
+			queryParam            = "query"
+			kParam                = "k"
+			refreshParam          = "refresh"
+		)
+		tool := mcp.NewTool("semantic_search",
+			mcp.WithDescription(`Search the resources of the given dataverse (datasets, services, zones...) whose `+
+				`description is the closest in meaning to a natural-language query, e.g. 'hourly measurements of the `+
+				`pollution in French cities'. Returns the DIDs of the k closest resources, closest first, with their `+
+				`similarity score between -1 and 1, title and description. Prefer search_resources for exact keywords.`),
+			mcp.WithToolAnnotation(mcp.ToolAnnotation{
+				Title:         "Semantic search",
+				ReadOnlyHint:  mcp.ToBoolPtr(true),
+				OpenWorldHint: mcp.ToBoolPtr(true),
+			}),
+			mcp.WithString(dataverseAddressParam,
+				mcp.Required(),
+				mcp.Description("The address of the dataverse contract"),
+				mcp.Pattern(address.ContractPattern)),
+			mcp.WithString(queryParam,
+				mcp.Required(),
+				mcp.Description("The natural-language description of the resources to look for")),
+			mcp.WithNumber(kParam,
+				mcp.Min(1),
+				mcp.DefaultNumber(defaultSemanticSearchK),
+				mcp.Description("The number of resources to return")),
+			mcp.WithBoolean(refreshParam,
+				mcp.DefaultBool(false),
+				mcp.Description("Whether to index the descriptions of the dataverse again before searching, at most once a minute")),
+		)
+		handler := func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			dataverseAddress, err := request.RequireString(dataverseAddressParam)
+			if err != nil {
+				return nil, err
+			}
+			if err := address.ValidateContract(dataverseAddress); err != nil {
+				return toolResultError(err), nil
+			}
+
+			query, err := request.RequireString(queryParam)
+			if err != nil {
+				return nil, err
+			}
+			k := request.GetInt(kParam, defaultSemanticSearchK)
+			if k <= 0 {
+				return nil, fmt.Errorf("argument %q must be positive", kParam)
+			}
+
+			if request.GetBool(refreshParam, false) {
+				if err := index.Refresh(ctx, dataverseAddress); err != nil {
+					return toolResultError(err), nil
+				}
+			}
+
+			result, err := index.Search(ctx, dataverseAddress, query, k)
+			if err != nil {
+				return toolResultError(err), nil
+			}
+
+			r, err := json.Marshal(result)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal response: %w", err)
+			}
+
+			return mcp.NewToolResultText(string(r)), nil
+		}
+
+		return server.ServerTool{Tool: tool, Handler: handler}
+	}
+}
@@ -0,0 +1,138 @@
+package mcp
+
+import (
+	goctx "context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/embedding"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/axone-protocol/axone-mcp/internal/semantic"
+	"github.com/mark3labs/mcp-go/mcp"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	semanticDataverse   = "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"
+	semanticTriplestore = "axone1xa8wemfrzq03tkwqxnv9lun7rceec7wuhh8x3qjgxkaaj5fl50zsmj8u0n"
+	// claimLiteralsQuery is the first select query of the literal claims of a triplestore.
+	claimLiteralsQuery = `{"select":{"query":{"limit":30,"prefixes":[],"select":[{"variable":"credential"},` +
+		`{"variable":"subject"},{"variable":"property"},{"variable":"value"}],"where":{"bgp":{"patterns":[` +
+		`{"object":{"variable":"subject"},"predicate":{"named_node":{"full":"dataverse:credential:body#subject"}},` +
+		`"subject":{"variable":"credential"}},` +
+		`{"object":{"variable":"claim"},"predicate":{"named_node":{"full":"dataverse:credential:body#claim"}},` +
+		`"subject":{"variable":"credential"}},` +
+		`{"object":{"variable":"value"},"predicate":{"variable":"property"},"subject":{"variable":"claim"}}]}}}}}`
+)
+
+// expectClaimLiterals expects the claims of the semantic dataverse to be queried, answering the given dataset claims.
+func expectClaimLiterals(cc *mocks.MockClientConnInterface, claims ...[4]string) {
+	bindings := make([]string, 0, len(claims))
+	for _, c := range claims {
+		bindings = append(bindings, fmt.Sprintf(`{"credential":{"type":"uri","value":{"full":%q}},`+
+			`"subject":{"type":"uri","value":{"full":%q}},`+
+			`"property":{"type":"uri","value":{"full":"https://w3id.org/axone/ontology/v4/schema/credential/dataset/description/%s"}},`+
+			`"value":{"type":"literal","value":%q,"xml:lang":"en"}}`, c[0], c[1], c[2], c[3]))
+	}
+	expectClientConn(cc, semanticDataverse, `{"dataverse":{}}`,
+		`{"name":"dataverse-42","triplestore_address":"`+semanticTriplestore+`"}`, nil)
+	expectClientConn(cc, semanticTriplestore, claimLiteralsQuery,
+		`{"head":{"vars":["credential","subject","property","value"]},"results":{"bindings":[`+
+			strings.Join(bindings, ",")+`]}}`, nil)
+}
+
+func TestSemanticSearchJSONRCPMessageHandling(t *testing.T) {
+	Convey("Testing semantic_search JSON-RPC message handling", t, func() {
+		Convey("Given a server without embedder", func() {
+			ctrl := gomock.NewController(t)
+			Reset(ctrl.Finish)
+
+			s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), ReadOnly)
+			So(err, ShouldBeNil)
+
+			Convey("When listing the tools", func() {
+				response := s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":"42","method":"tools/list"}`))
+
+				Convey("Then semantic_search should not be listed", func() {
+					result, ok := response.(mcp.JSONRPCResponse).Result.(mcp.ListToolsResult)
+					So(ok, ShouldBeTrue)
+					for _, tool := range result.Tools {
+						So(tool.Name, ShouldNotEqual, "semantic_search")
+					}
+				})
+			})
+		})
+
+		tests := []struct {
+			name      string
+			arguments map[string]any
+			embedder  embedding.Embedder
+			fixture   func(cc *mocks.MockClientConnInterface)
+			validate  func(response mcp.JSONRPCMessage)
+		}{
+			{
+				name:      "semantic_search tool",
+				arguments: map[string]any{"dataverse": semanticDataverse, "query": "hourly pollutants", "k": 1},
+				embedder:  embedding.NewHashEmbedder(),
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectClaimLiterals(cc,
+						[4]string{"https://example.org/vc/1", "did:key:z6MkAir", "hasTitle", "Air quality"},
+						[4]string{"https://example.org/vc/1", "did:key:z6MkAir", "hasDescription", "Pollutants measured hourly."},
+						[4]string{"https://example.org/vc/2", "did:key:z6MkWater", "hasTitle", "Water quality"})
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldBeJSONRPCResponseSuccessWithText, `{"refreshedAt":"2025-06-01T12:00:00Z",`+
+						`"hits":[{"resource":"did:key:z6MkAir","score":0.544,"title":"Air quality",`+
+						`"description":"Pollutants measured hourly."}]}`)
+				},
+			},
+			{
+				name:      "semantic_search tool - embedder unavailable",
+				arguments: map[string]any{"dataverse": semanticDataverse, "query": "hourly pollutants"},
+				embedder:  embedding.NewOpenAIEmbedder("http://127.0.0.1:0", "model", ""),
+				fixture: func(cc *mocks.MockClientConnInterface) {
+					expectClaimLiterals(cc,
+						[4]string{"https://example.org/vc/1", "did:key:z6MkAir", "hasTitle", "Air quality"})
+				},
+				validate: func(response mcp.JSONRPCMessage) {
+					So(response, ShouldHaveErrorCode, ErrorCodeEmbedderUnavailable)
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("Given a new server for %s", tt.name), func() {
+				ctrl := gomock.NewController(t)
+				Reset(ctrl.Finish)
+
+				cc := mocks.NewMockClientConnInterface(ctrl)
+				tt.fixture(cc)
+				index := semantic.New(cc, tt.embedder,
+					semantic.WithClock(func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }))
+				s, err := NewServer(cc, ReadOnly, WithSemanticIndex(index))
+				So(err, ShouldBeNil)
+
+				messageBytes, err := json.Marshal(mcp.JSONRPCRequest{
+					JSONRPC: mcp.JSONRPC_VERSION,
+					ID:      requestId,
+					Request: mcp.Request{
+						Method: "tools/call",
+					},
+					Params: map[string]any{"name": "semantic_search", "arguments": tt.arguments},
+				})
+				So(err, ShouldBeNil)
+
+				Convey(fmt.Sprintf("When handling %s message", tt.name), func() {
+					got := s.HandleMessage(goctx.Background(), messageBytes)
+					Convey("Then the response should be valid", func() {
+						tt.validate(got)
+					})
+				})
+			})
+		}
+	})
+}
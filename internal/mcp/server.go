@@ -2,8 +2,22 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
 
+	"github.com/axone-protocol/axone-mcp/internal/audit"
+	"github.com/axone-protocol/axone-mcp/internal/credential"
+	"github.com/axone-protocol/axone-mcp/internal/did"
+	"github.com/axone-protocol/axone-mcp/internal/metrics"
+	"github.com/axone-protocol/axone-mcp/internal/ontology"
+	"github.com/axone-protocol/axone-mcp/internal/policy"
+	"github.com/axone-protocol/axone-mcp/internal/ratelimit"
+	"github.com/axone-protocol/axone-mcp/internal/search"
+	"github.com/axone-protocol/axone-mcp/internal/semantic"
 	"github.com/axone-protocol/axone-mcp/internal/version"
 	"github.com/mark3labs/mcp-go/mcp"
 	"google.golang.org/grpc"
@@ -32,30 +46,249 @@ var serverToolFactories = []serverToolFactory{
 	getGovernanceCode,
 }
 
+// Option configures optional behaviours of the MCP server.
+type Option func(*options)
+
+type options struct {
+	policy   *policy.Policy
+	limiter  *ratelimit.Limiter
+	resolver *did.Resolver
+	signer   *credential.Signer
+	index    *search.Index
+	semantic *semantic.Index
+	metrics  *metrics.Metrics
+	audit    *audit.Log
+	closers  []io.Closer
+	// dataverse is the address of the dataverse targeted by the tools when not given.
+	dataverse string
+}
+
+// WithPolicy restricts the tools each caller can list and invoke to the ones granted by the given policy.
+func WithPolicy(p *policy.Policy) Option {
+	return func(o *options) {
+		o.policy = p
+	}
+}
+
+// WithRateLimiter rejects the tool calls exceeding the limits enforced by the given limiter.
+func WithRateLimiter(l *ratelimit.Limiter) Option {
+	return func(o *options) {
+		o.limiter = l
+	}
+}
+
+// WithDIDResolver sets the resolver of the DIDs, instead of one with the default settings.
+func WithDIDResolver(r *did.Resolver) Option {
+	return func(o *options) {
+		o.resolver = r
+	}
+}
+
+// WithCredentialSigner sets the signer of the credentials built by the server, which are left unsigned otherwise.
+func WithCredentialSigner(s *credential.Signer) Option {
+	return func(o *options) {
+		o.signer = s
+	}
+}
+
+// WithSearchIndex sets the full-text index of the dataverses, instead of one kept in memory.
+func WithSearchIndex(i *search.Index) Option {
+	return func(o *options) {
+		o.index = i
+	}
+}
+
+// WithSemanticIndex enables the semantic_search tool, backed by the given vector index.
+func WithSemanticIndex(i *semantic.Index) Option {
+	return func(o *options) {
+		o.semantic = i
+	}
+}
+
+// WithMetrics records the sessions and the tool calls in the given metrics.
+func WithMetrics(m *metrics.Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithAuditLog records every tool invocation in the given audit log.
+func WithAuditLog(l *audit.Log) Option {
+	return func(o *options) {
+		o.audit = l
+	}
+}
+
+// WithCloser releases the given resource along with the server, once closed.
+func WithCloser(c io.Closer) Option {
+	return func(o *options) {
+		o.closers = append(o.closers, c)
+	}
+}
+
+// WithDefaultDataverse makes the dataverse argument of the tools optional, the given address being used when omitted.
+func WithDefaultDataverse(address string) Option {
+	return func(o *options) {
+		o.dataverse = address
+	}
+}
+
+// Settings are the settings of the server that can be changed while it runs.
+type Settings struct {
+	Mode AccessMode
+	// Policy restricts the tools each caller can list and invoke, if any.
+	Policy *policy.Policy
+	// Dataverse is the address of the dataverse targeted by the tools when not given, if any.
+	Dataverse string
+}
+
+// Server is an MCP server whose settings can be reloaded without dropping the sessions of its clients.
+type Server struct {
+	*server.MCPServer
+
+	// tools are the tools of the server, before being adapted to its settings.
+	tools []server.ServerTool
+	state atomic.Pointer[serverState]
+	// reloadMu serializes the reloads, so that the tools served follow the last settings published.
+	reloadMu sync.Mutex
+	// closers are the resources given to the server, released when it is closed.
+	closers []io.Closer
+	// failures counts the failed tool calls per error code.
+	failures sync.Map
+}
+
+type serverState struct {
+	Settings
+	// dataverseTools are the names of the tools taking a dataverse argument.
+	dataverseTools []string
+	// tools are the names of the tools served.
+	tools []string
+}
+
 // NewServer creates a new MCP server instance.
 // It takes a gRPC connection to the Axone node and a read-only flag which  restricts the server to read-only operations.
-func NewServer(cc grpc.ClientConnInterface, mode AccessMode) (*server.MCPServer, error) {
-	s := server.NewMCPServer(
-		ServerName,
-		version.Version,
+func NewServer(cc grpc.ClientConnInterface, mode AccessMode, opts ...Option) (*Server, error) {
+	o := options{resolver: did.NewResolver()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.index == nil {
+		o.index = search.New(cc, search.NewMemoryStore())
+	}
+
+	onto, err := ontology.Load()
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{tools: serverTools(cc, onto, o), closers: append([]io.Closer{o.index}, o.closers...)}
+
+	serverOpts := []server.ServerOption{
+		WithToolTracing(),
+		WithDefaultArgument(policy.DataverseArgument, s.defaultDataverse),
+	}
+	if o.audit != nil {
+		s.closers = append(s.closers, o.audit)
+		serverOpts = append(serverOpts, WithToolAudit(o.audit))
+	}
+	serverOpts = append(serverOpts,
 		server.WithLogging(),
-		server.WithToolCapabilities(false),
+		server.WithToolCapabilities(true),
+		server.WithResourceCapabilities(false, false),
 		server.WithToolFilter(func(_ context.Context, tools []mcp.Tool) []mcp.Tool {
+			mode := s.Settings().Mode
 			return lo.Filter(tools, func(tool mcp.Tool, _ int) bool {
 				return mode != ReadOnly || lo.FromPtr(tool.Annotations.ReadOnlyHint)
 			})
 		}),
-		WithHooksLogging(),
+		WithFailureLogging(s.countFailure),
 	)
+	serverOpts = append(serverOpts,
+		withPolicyEnforcement(func() *policy.Policy { return s.Settings().Policy }, s.defaultDataverse)...)
+	hooks := []HooksRegistrar{WithHooksLogging(), WithHooksTracing()}
+	if o.metrics != nil {
+		hooks = append(hooks, WithHooksMetrics(o.metrics))
+	}
+	if o.limiter != nil {
+		s.closers = append(s.closers, o.limiter)
+		serverOpts = append(serverOpts, WithRateLimitEnforcement(o.limiter))
+		hooks = append(hooks, WithHooksRateLimit(o.limiter))
+	}
+	serverOpts = append(serverOpts, WithHooks(hooks...))
 
-	addServerTools(s, mode, cc, serverToolFactories...)
+	s.MCPServer = server.NewMCPServer(ServerName, version.Version, serverOpts...)
+	s.Reload(Settings{Mode: mode, Policy: o.policy, Dataverse: o.dataverse})
+	s.AddResources(ontologyResources(onto)...)
 
 	return s, nil
 }
 
-func addServerTools(s *server.MCPServer, mode AccessMode, cc grpc.ClientConnInterface, factories ...serverToolFactory) {
-	tools := lo.Map(factories, createTool(cc))
-	addTools(s, mode, tools...)
+// Close releases the resources given to the server, such as the stores of the rate limiter and of the search index,
+// and the audit log.
+func (s *Server) Close() error {
+	return errors.Join(lo.Map(s.closers, func(c io.Closer, _ int) error { return c.Close() })...)
+}
+
+// countFailure counts a failed tool call of the given error code, and returns the number of such failures so far.
+func (s *Server) countFailure(code ErrorCode) uint64 {
+	counter, _ := s.failures.LoadOrStore(code, new(atomic.Uint64))
+
+	return counter.(*atomic.Uint64).Add(1) //nolint:forcetypeassert
+}
+
+// Settings returns the current settings of the server.
+func (s *Server) Settings() Settings {
+	return s.state.Load().Settings
+}
+
+// Reload applies the given settings to the server, and notifies the connected clients that the list of tools changed.
+//
+// The calls follow the new settings as soon as they are published, the handlers of the tools reading them at each call;
+// the definitions of the tools are then replaced in place, the ones no longer served being removed, so that no call
+// finds its tool missing in between.
+func (s *Server) Reload(settings Settings) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	tools := lo.Map(s.tools, wrapToolWithModeGuard(func() AccessMode { return s.Settings().Mode }))
+	state := &serverState{Settings: settings}
+	if settings.Dataverse != "" {
+		tools = lo.Map(tools, wrapToolWithDefaultArgument(policy.DataverseArgument, settings.Dataverse))
+		state.dataverseTools = toolsTaking(tools, policy.DataverseArgument)
+	}
+	state.tools = lo.Map(tools, func(srvTool server.ServerTool, _ int) string { return srvTool.Tool.Name })
+
+	previous := s.state.Swap(state)
+	s.AddTools(tools...)
+	if previous != nil {
+		s.DeleteTools(lo.Without(previous.tools, state.tools...)...)
+	}
+}
+
+// defaultDataverse returns the dataverse targeted by the given tool when not given, empty if none.
+func (s *Server) defaultDataverse(tool string) string {
+	state := s.state.Load()
+	if !slices.Contains(state.dataverseTools, tool) {
+		return ""
+	}
+
+	return state.Dataverse
+}
+
+// serverTools creates the tools of the server.
+func serverTools(cc grpc.ClientConnInterface, onto *ontology.Ontology, o options) []server.ServerTool {
+	factories := slices.Concat(serverToolFactories, []serverToolFactory{
+		resolveDID(o.resolver),
+		verifyCredential(credential.NewVerifier(o.resolver)),
+		buildCredential(o.signer),
+		searchOntology(onto),
+		searchResources(o.index),
+	})
+	if o.semantic != nil {
+		factories = append(factories, semanticSearch(o.semantic))
+	}
+
+	return lo.Map(factories, createTool(cc))
 }
 
 func addTools(s *server.MCPServer, mode AccessMode, tools ...server.ServerTool) {
@@ -70,13 +303,19 @@ func createTool(cc grpc.ClientConnInterface) func(factory serverToolFactory, _ i
 }
 
 func wrapToolWithAccessGuard(mode AccessMode) func(srvTool server.ServerTool, _ int) server.ServerTool {
+	return wrapToolWithModeGuard(func() AccessMode { return mode })
+}
+
+// wrapToolWithModeGuard rejects the calls to the tools which are not read-only while the given mode is read-only.
+func wrapToolWithModeGuard(mode func() AccessMode) func(srvTool server.ServerTool, _ int) server.ServerTool {
 	return func(srvTool server.ServerTool, _ int) server.ServerTool {
 		next := srvTool.Handler
 		srvTool.Handler = func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			if mode == ReadOnly && !lo.FromPtr(srvTool.Tool.Annotations.ReadOnlyHint) {
-				return mcp.NewToolResultError(
-					fmt.Sprintf("The server is in read-only mode; tool %s cannot be invoked.", srvTool.Tool.Name),
-				), nil
+			if mode() == ReadOnly && !lo.FromPtr(srvTool.Tool.Annotations.ReadOnlyHint) {
+				return toolResultError(newToolError(ErrorCodeAccessDenied,
+					//nolint:revive,staticcheck // the message clients got before the error codes
+					fmt.Errorf("The server is in read-only mode; tool %s cannot be invoked.", srvTool.Tool.Name),
+				)), nil
 			}
 			return next(ctx, request)
 		}
@@ -85,14 +324,81 @@ func wrapToolWithAccessGuard(mode AccessMode) func(srvTool server.ServerTool, _
 	}
 }
 
-func WithHooksLogging() server.ServerOption {
-	hooks := &server.Hooks{}
+// WithPolicyEnforcement returns the server options enforcing the given policy: tools not granted to the caller are
+// hidden from the tools list and their invocation is rejected.
+func WithPolicyEnforcement(p *policy.Policy) []server.ServerOption {
+	return withPolicyEnforcement(func() *policy.Policy { return p }, func(string) string { return "" })
+}
 
-	hooks.AddOnRegisterSession(func(_ context.Context, session server.ClientSession) {
-		log.Logger.Info().
-			Str("session_id", session.SessionID()).
-			Msg("session created")
-	})
+// withPolicyEnforcement returns the server options enforcing the current policy, if any, on the arguments of the calls
+// given the default dataverse of the tool, if any, when omitted.
+func withPolicyEnforcement(
+	current func() *policy.Policy, defaultDataverse func(tool string) string,
+) []server.ServerOption {
+	return []server.ServerOption{
+		server.WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+			p := current()
+			if p == nil {
+				return tools
+			}
+			subject := subjectFromContext(ctx)
+			return lo.Filter(tools, func(tool mcp.Tool, _ int) bool {
+				return p.AllowsTool(subject, tool.Name)
+			})
+		}),
+		server.WithToolHandlerMiddleware(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				p := current()
+				if p == nil {
+					return next(ctx, request)
+				}
+				subject := subjectFromContext(ctx)
+				// resolved here too, so that the dataverse checked is the one queried whatever the order of the
+				// middlewares.
+				args := withDefaultArgument(request, policy.DataverseArgument, defaultDataverse(request.Params.Name)).
+					GetArguments()
+				if err := p.Authorize(subject, request.Params.Name, args); err != nil {
+					log.Logger.Warn().
+						Str("session_id", subject.SessionID).
+						Str("principal", subject.Principal).
+						Str("tool", request.Params.Name).
+						Err(err).
+						Msg("tool call denied by policy")
+					return toolResultError(err), nil
+				}
+				return next(ctx, request)
+			}
+		}),
+	}
+}
+
+func subjectFromContext(ctx context.Context) policy.Subject {
+	return policy.Subject{
+		Principal: PrincipalFromContext(ctx),
+		SessionID: SessionIDFromContext(ctx),
+	}
+}
+
+// HooksRegistrar registers callbacks on the server hooks.
+type HooksRegistrar func(hooks *server.Hooks)
+
+// WithHooks installs the server hooks, each registrar adding its own callbacks.
+func WithHooks(registrars ...HooksRegistrar) server.ServerOption {
+	hooks := &server.Hooks{}
+	for _, register := range registrars {
+		register(hooks)
+	}
 
 	return server.WithHooks(hooks)
 }
+
+func WithHooksLogging() HooksRegistrar {
+	return func(hooks *server.Hooks) {
+		hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+			log.Logger.Info().
+				Str("session_id", session.SessionID()).
+				Str("principal", PrincipalFromContext(ctx)).
+				Msg("session created")
+		})
+	}
+}
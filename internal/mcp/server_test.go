@@ -4,19 +4,27 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
 	goctx "context"
 
 	"github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
 	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/axone-protocol/axone-mcp/internal/policy"
+	"github.com/axone-protocol/axone-mcp/internal/ratelimit"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 	. "github.com/smartystreets/goconvey/convey"
 	"go.uber.org/mock/gomock"
+	"golang.org/x/time/rate"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestJSONRCPMessageHandling(t *testing.T) {
@@ -119,6 +127,7 @@ func TestJSONRCPMessageHandling(t *testing.T) {
 					So(ok, ShouldBeTrue)
 					So(content.Text, ShouldEqual, "The server is in read-only mode; tool read_write_foo cannot be invoked.")
 					So(content.Type, ShouldEqual, "text")
+					So(ctr.Meta["error"].(map[string]any)["code"], ShouldEqual, ErrorCodeAccessDenied)
 				},
 			},
 		}
@@ -133,7 +142,7 @@ func TestJSONRCPMessageHandling(t *testing.T) {
 				So(err, ShouldBeNil)
 
 				if tt.fixture != nil {
-					tt.fixture(s, cc)
+					tt.fixture(s.MCPServer, cc)
 				}
 
 				messageBytes, err := json.Marshal(tt.message)
@@ -151,6 +160,182 @@ func TestJSONRCPMessageHandling(t *testing.T) {
 	})
 }
 
+func TestPolicyEnforcement(t *testing.T) {
+	Convey("Given a server enforcing a policy", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		p, err := policy.Parse([]byte(`
+rules:
+  - principals: ["CN=auditor"]
+    tools: ["get_resource_governance_code"]
+`))
+		So(err, ShouldBeNil)
+
+		s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), ReadWrite, WithPolicy(p))
+		So(err, ShouldBeNil)
+
+		tests := []struct {
+			principal string
+			expected  []string
+		}{
+			{principal: "CN=auditor", expected: []string{"get_resource_governance_code"}},
+			{principal: "CN=guest", expected: []string{}},
+			{principal: "", expected: []string{}},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("When principal %q lists the tools", tt.principal), func() {
+				ctx := WithPrincipal(goctx.Background(), tt.principal)
+				got := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":"42","method":"tools/list"}`))
+
+				Convey("Then only the granted tools should be listed", func() {
+					resp, ok := got.(mcp.JSONRPCResponse)
+					So(ok, ShouldBeTrue)
+					ctr, ok := resp.Result.(mcp.ListToolsResult)
+					So(ok, ShouldBeTrue)
+					So(lo.Map(ctr.Tools, func(t mcp.Tool, _ int) string { return t.Name }), ShouldResemble, tt.expected)
+				})
+			})
+		}
+
+		Convey("When a principal calls a tool not granted to it", func() {
+			ctx := WithPrincipal(goctx.Background(), "CN=auditor")
+			got := s.HandleMessage(ctx,
+				[]byte(`{"jsonrpc":"2.0","id":"42","method":"tools/call","params":{"name":"get_dataverse_info"}}`))
+
+			Convey("Then the call should be denied", func() {
+				So(got, ShouldBeJSONRPCResponseErrorWithText, "access denied: tool get_dataverse_info is not allowed")
+			})
+		})
+	})
+}
+
+func TestReload(t *testing.T) {
+	Convey("Given a server with a connected client", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		s, err := NewServer(mocks.NewMockClientConnInterface(ctrl), ReadWrite)
+		So(err, ShouldBeNil)
+
+		session := fakeSession{
+			sessionID:           "1234",
+			notificationChannel: make(chan mcp.JSONRPCNotification, 1),
+			initialized:         true,
+		}
+		So(s.RegisterSession(goctx.Background(), session), ShouldBeNil)
+		Reset(func() { s.UnregisterSession(goctx.Background(), session.SessionID()) })
+
+		listTools := func(principal string) []string {
+			got := s.HandleMessage(WithPrincipal(goctx.Background(), principal),
+				[]byte(`{"jsonrpc":"2.0","id":"42","method":"tools/list"}`))
+			resp, ok := got.(mcp.JSONRPCResponse)
+			So(ok, ShouldBeTrue)
+			ctr, ok := resp.Result.(mcp.ListToolsResult)
+			So(ok, ShouldBeTrue)
+			return lo.Map(ctr.Tools, func(t mcp.Tool, _ int) string { return t.Name })
+		}
+		So(listTools(""), ShouldContain, "get_dataverse_info")
+
+		Convey("When the server is reloaded with a policy and a default dataverse", func() {
+			p, err := policy.Parse([]byte(`
+rules:
+  - principals: ["CN=auditor"]
+    tools: ["get_dataverse_info"]
+`))
+			So(err, ShouldBeNil)
+			s.Reload(Settings{
+				Mode:      ReadOnly,
+				Policy:    p,
+				Dataverse: "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+			})
+
+			Convey("Then the client should be notified that the tools changed", func() {
+				select {
+				case notification := <-session.notificationChannel:
+					So(notification.Method, ShouldEqual, string(mcp.MethodNotificationToolsListChanged))
+				case <-time.After(time.Second):
+					t.Fatal("no notification")
+				}
+			})
+
+			Convey("Then the new settings should apply to the tools", func() {
+				So(s.Settings().Mode, ShouldEqual, ReadOnly)
+				So(listTools("CN=auditor"), ShouldResemble, []string{"get_dataverse_info"})
+				So(listTools("CN=guest"), ShouldBeEmpty)
+			})
+
+			Convey("And reloaded again without them", func() {
+				<-session.notificationChannel
+				s.Reload(Settings{Mode: ReadWrite})
+
+				Convey("Then every tool should be available again", func() {
+					So(listTools("CN=guest"), ShouldContain, "get_dataverse_info")
+					So(listTools("CN=guest"), ShouldContain, "build_credential")
+				})
+			})
+		})
+
+		Convey("When the server is reloaded while its tools are listed", func() {
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for n := range 2000 {
+					s.Reload(Settings{Mode: AccessMode(n%2 == 0)})
+				}
+			}()
+
+			var missing int
+			for running := true; running; {
+				select {
+				case <-done:
+					running = false
+				default:
+				}
+				if !slices.Contains(listTools(""), "get_dataverse_info") {
+					missing++
+				}
+			}
+
+			Convey("Then the tools should never be missing in between", func() {
+				So(missing, ShouldEqual, 0)
+			})
+		})
+	})
+}
+
+func TestRateLimitEnforcement(t *testing.T) {
+	Convey("Given a server limiting each principal to one call per minute", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		cc := mocks.NewMockClientConnInterface(ctrl)
+		expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+			`{"dataverse":{}}`, `{"name":"dataverse-42"}`, nil)
+
+		limiter := ratelimit.New(ratelimit.Config{Principal: ratelimit.Limit{Rate: rate.Every(time.Minute), Burst: 1}},
+			ratelimit.NewMemoryStore())
+		s, err := NewServer(cc, ReadWrite, WithRateLimiter(limiter))
+		So(err, ShouldBeNil)
+
+		message := []byte(`{"jsonrpc":"2.0","id":"42","method":"tools/call","params":{"name":"get_dataverse_info",` +
+			`"arguments":{"dataverse":"axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"}}}`)
+		ctx := WithPrincipal(goctx.Background(), "alice")
+
+		Convey("When the principal calls a tool twice", func() {
+			first := s.HandleMessage(ctx, message)
+			second := s.HandleMessage(ctx, message)
+
+			Convey("Then the second call should be rejected with a retry-after delay", func() {
+				So(first, ShouldBeJSONRPCResponseSuccessWithText, `{"name":"dataverse-42","triplestore_address":""}`)
+				So(second, ShouldBeJSONRPCResponseErrorWithText, "rate limit exceeded for principal alice; retry after 1m0s")
+				So(second.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult).Meta["retryAfter"], ShouldAlmostEqual, 60, 1)
+			})
+		})
+	})
+}
+
 func TestOnRegisterSessionLog(t *testing.T) {
 	Convey("Given a new MCP server", t, func() {
 		ctrl := gomock.NewController(t)
@@ -200,6 +385,38 @@ func expectClientConn(cc *mocks.MockClientConnInterface,
 		}).Times(1)
 }
 
+// expectClientConnAtHeight is like expectClientConn for a query which must target the requested block height (none
+// if 0), and whose response reports the answered one.
+func expectClientConnAtHeight(cc *mocks.MockClientConnInterface,
+	address string,
+	queryData string,
+	respData string,
+	requested int64,
+	answered int64,
+) {
+	cc.EXPECT().
+		Invoke(gomock.Any(), "/cosmwasm.wasm.v1.Query/SmartContractState",
+			&types.QuerySmartContractStateRequest{
+				Address:   address,
+				QueryData: []byte(queryData),
+			},
+			&types.QuerySmartContractStateResponse{},
+			gomock.Any()).
+		DoAndReturn(func(ctx goctx.Context, method string, req, reply any, opts ...grpc.CallOption) error {
+			h, ok := height.FromOutgoingContext(ctx)
+			So(ok, ShouldEqual, requested != 0)
+			So(h, ShouldEqual, requested)
+
+			reply.(*types.QuerySmartContractStateResponse).Data = []byte(respData)
+			for _, opt := range opts {
+				if o, ok := opt.(grpc.HeaderCallOption); ok {
+					*o.HeaderAddr = metadata.Pairs(height.MetadataKey, strconv.FormatInt(answered, 10))
+				}
+			}
+			return nil
+		}).Times(1)
+}
+
 func captureLogOutput(f func() error) (string, error) {
 	var logBuffer bytes.Buffer
 	originalLogger := log.Logger
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/axone-protocol/axone-mcp/internal/tracing"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes of the spans of the MCP requests and tool calls.
+const (
+	attrMethod    = attribute.Key("mcp.method")
+	attrRequestID = attribute.Key("mcp.request.id")
+	attrSessionID = attribute.Key("mcp.session.id")
+	attrTool      = attribute.Key("mcp.tool.name")
+	attrErrorCode = attribute.Key("axone.error.code")
+)
+
+// WithHooksTracing traces each MCP request in a span, continuing the trace of the incoming HTTP request or of the
+// trace context given in the _meta of the tool calls, if any.
+func WithHooksTracing() HooksRegistrar {
+	// spans holds the spans of the ongoing requests, by session and request ID.
+	var spans sync.Map
+	requestKey := func(ctx context.Context, id any) string {
+		return subjectFromContext(ctx).SessionID + "/" + fmt.Sprint(id)
+	}
+	end := func(ctx context.Context, id any, err error) {
+		s, ok := spans.LoadAndDelete(requestKey(ctx, id))
+		if !ok {
+			return
+		}
+		span := s.(trace.Span) //nolint:forcetypeassert
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+
+	return func(hooks *server.Hooks) {
+		hooks.AddBeforeAny(func(ctx context.Context, id any, method mcp.MCPMethod, message any) {
+			if request, ok := message.(*mcp.CallToolRequest); ok {
+				ctx = otel.GetTextMapPropagator().Extract(ctx, metaCarrier(request.Params.Meta))
+			}
+			_, span := tracing.Tracer().Start(ctx, "mcp "+string(method), trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					attrMethod.String(string(method)),
+					attrRequestID.String(fmt.Sprint(id)),
+					attrSessionID.String(subjectFromContext(ctx).SessionID),
+				))
+			spans.Store(requestKey(ctx, id), span)
+		})
+		// The hooks cannot alter the context of the handlers, so the span of the request is handed over to the tool
+		// handler through the _meta of the request.
+		hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+			s, ok := spans.Load(requestKey(ctx, id))
+			if !ok {
+				return
+			}
+			if message.Params.Meta == nil {
+				message.Params.Meta = &mcp.Meta{}
+			}
+			if message.Params.Meta.AdditionalFields == nil {
+				message.Params.Meta.AdditionalFields = map[string]any{}
+			}
+			carrier := propagation.MapCarrier{}
+			otel.GetTextMapPropagator().Inject(trace.ContextWithSpan(ctx, s.(trace.Span)), carrier) //nolint:forcetypeassert
+			for k, v := range carrier {
+				message.Params.Meta.AdditionalFields[k] = v
+			}
+		})
+		hooks.AddOnSuccess(func(ctx context.Context, id any, _ mcp.MCPMethod, _ any, _ any) {
+			end(ctx, id, nil)
+		})
+		hooks.AddOnError(func(ctx context.Context, id any, _ mcp.MCPMethod, _ any, err error) {
+			end(ctx, id, err)
+		})
+	}
+}
+
+// WithToolTracing returns the server option tracing each tool handler in a span, child of the span of its request.
+func WithToolTracing() server.ServerOption {
+	return server.WithToolHandlerMiddleware(func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, metaCarrier(request.Params.Meta))
+			ctx, span := tracing.Tracer().Start(ctx, "tool "+request.Params.Name,
+				trace.WithAttributes(attrTool.String(request.Params.Name)))
+			defer span.End()
+
+			result, err := next(ctx, request)
+			switch {
+			case err != nil:
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case result != nil && result.IsError:
+				code := resultErrorCode(result)
+				span.SetAttributes(attrErrorCode.String(string(code)))
+				span.SetStatus(codes.Error, string(code))
+			}
+
+			return result, err
+		}
+	})
+}
+
+// metaCarrier returns the string fields of the _meta of a request, which may carry a trace context.
+func metaCarrier(meta *mcp.Meta) propagation.MapCarrier {
+	carrier := propagation.MapCarrier{}
+	if meta == nil {
+		return carrier
+	}
+	for k, v := range meta.AdditionalFields {
+		if s, ok := v.(string); ok {
+			carrier[k] = s
+		}
+	}
+
+	return carrier
+}
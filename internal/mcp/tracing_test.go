@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	goctx "context"
+	"testing"
+
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	"github.com/axone-protocol/axone-mcp/internal/tracing"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/mock/gomock"
+)
+
+func TestTracing(t *testing.T) {
+	Convey("Given a server traced by a recording tracer provider", t, func() {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		Reset(func() {
+			otel.SetTracerProvider(noop.NewTracerProvider())
+			otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+		})
+
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		cc := mocks.NewMockClientConnInterface(ctrl)
+		s, err := NewServer(tracing.InstrumentClientConn(cc), ReadWrite)
+		So(err, ShouldBeNil)
+
+		spansByName := func() map[string]sdktrace.ReadOnlySpan {
+			out := map[string]sdktrace.ReadOnlySpan{}
+			for _, span := range recorder.Ended() {
+				out[span.Name()] = span
+			}
+			return out
+		}
+
+		Convey("When a tool querying the node is called with a trace context in its _meta", func() {
+			expectClientConn(cc, "axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w",
+				`{"dataverse":{}}`, `{"name":"dataverse-42"}`, nil)
+
+			s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{`+
+				`"_meta":{"traceparent":"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},`+
+				`"name":"get_dataverse_info",`+
+				`"arguments":{"dataverse":"axone1xt4ahzz2x8hpkc0tk6ekte9x6crw4w6u0r67cyt3kz9syh24pd7scvlt2w"}}}`))
+
+			Convey("Then the request, the tool handler and the query should be traced in nested spans", func() {
+				spans := spansByName()
+				So(spans, ShouldHaveLength, 3)
+				request, tool := spans["mcp tools/call"], spans["tool get_dataverse_info"]
+				query := spans["/cosmwasm.wasm.v1.Query/SmartContractState"]
+				So(request, ShouldNotBeNil)
+				So(tool, ShouldNotBeNil)
+				So(query, ShouldNotBeNil)
+
+				So(request.SpanContext().TraceID().String(), ShouldEqual, "4bf92f3577b34da6a3ce929d0e0e4736")
+				So(request.Parent().SpanID().String(), ShouldEqual, "00f067aa0ba902b7")
+				So(tool.Parent().SpanID(), ShouldEqual, request.SpanContext().SpanID())
+				So(query.Parent().SpanID(), ShouldEqual, tool.SpanContext().SpanID())
+				So(tool.Status().Code, ShouldEqual, codes.Unset)
+			})
+		})
+
+		Convey("When a tool call fails", func() {
+			s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{`+
+				`"name":"get_dataverse_info","arguments":{"dataverse":"axone1foo"}}}`))
+
+			Convey("Then the span of the tool handler should hold the error code", func() {
+				tool := spansByName()["tool get_dataverse_info"]
+				So(tool, ShouldNotBeNil)
+				So(tool.Status().Code, ShouldEqual, codes.Error)
+				So(tool.Status().Description, ShouldEqual, string(ErrorCodeInvalidAddress))
+			})
+		})
+
+		Convey("When a request fails", func() {
+			s.HandleMessage(goctx.Background(), []byte(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{`+
+				`"name":"unknown","arguments":{}}}`))
+
+			Convey("Then the span of the request should record the error", func() {
+				request := spansByName()["mcp tools/call"]
+				So(request, ShouldNotBeNil)
+				So(request.Status().Code, ShouldEqual, codes.Error)
+			})
+		})
+	})
+}
@@ -97,6 +97,37 @@ func ShouldBeJSONRPCErrorWithText(actual any, expected ...any) string {
 	return success
 }
 
+// ShouldHaveErrorCode validates the error code held by the tool result of a JSON-RPC response.
+func ShouldHaveErrorCode(actual any, expected ...any) string {
+	if fail := need(1, expected); fail != success {
+		return fail
+	}
+
+	if fail := ShouldHaveSameTypeAs(actual, mcp.JSONRPCResponse{}); fail != "" {
+		return fail
+	}
+
+	ctr, ok := actual.(mcp.JSONRPCResponse).Result.(mcp.CallToolResult)
+	if !ok {
+		return "Result: expected a tool result"
+	}
+
+	meta, ok := ctr.Meta[errorMetaKey].(map[string]any)
+	if !ok {
+		return "Meta: no error metadata"
+	}
+
+	if fail := ShouldEqual(meta["code"], expected[0]); fail != "" {
+		return fmt.Sprintf("Code: %s", fail)
+	}
+
+	if fail := ShouldNotBeBlank(meta["hint"]); fail != "" {
+		return fmt.Sprintf("Hint: %s", fail)
+	}
+
+	return success
+}
+
 func shouldBeToolResultText(response mcp.JSONRPCResponse, isError bool, expectedContentText string) string {
 	if fail := ShouldResemble(response.ID, requestId); fail != "" {
 		return fmt.Sprintf("ID: %s", fail)
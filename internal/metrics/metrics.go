@@ -0,0 +1,206 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Namespace prefixes the names of all the metrics of the server.
+const Namespace = "axone_mcp"
+
+// OtherContract is the contract label of the calls to the contracts not tracked, so that callers querying arbitrary
+// contracts cannot grow the number of series without bound.
+const OtherContract = "other"
+
+// MaxContracts is the number of contracts found resolving dataverses beyond which the calls to the new ones are labeled
+// as OtherContract.
+const MaxContracts = 32
+
+// Metrics holds the Prometheus metrics of the server, in a registry of its own.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	toolCalls      *prometheus.CounterVec
+	toolDuration   *prometheus.HistogramVec
+	toolErrors     *prometheus.CounterVec
+	activeSessions prometheus.Gauge
+	grpcDuration   *prometheus.HistogramVec
+
+	mu sync.Mutex
+	// contracts are the addresses of the contracts labeled as such.
+	contracts map[string]struct{}
+}
+
+// New creates the metrics of the server, along with the Go runtime and process ones.
+func New() *Metrics {
+	m := &Metrics{
+		registry:  prometheus.NewRegistry(),
+		contracts: make(map[string]struct{}),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "tool_calls_total",
+			Help:      "Number of tool calls, by tool.",
+		}, []string{"tool"}),
+		toolDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Duration of the tool calls, by tool.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"tool"}),
+		toolErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "tool_errors_total",
+			Help:      "Number of failed tool calls, by tool and error code.",
+		}, []string{"tool", "code"}),
+		activeSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "active_sessions",
+			Help:      "Number of active client sessions.",
+		}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "grpc_call_duration_seconds",
+			Help: "Duration of the gRPC calls to the axone node, by method, contract address (of the dataverses " +
+				"resolved and their triplestore, \"other\" for the other contracts) and status code.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "contract", "code"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.toolCalls, m.toolDuration, m.toolErrors, m.activeSessions, m.grpcDuration,
+	)
+
+	return m
+}
+
+// Handler returns the HTTP handler exposing the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry returns the registry of the metrics, to register other collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveToolCall records a call of the tool lasting the given duration, failed with the given error code if not
+// empty.
+func (m *Metrics) ObserveToolCall(tool string, duration time.Duration, code string) {
+	m.toolCalls.WithLabelValues(tool).Inc()
+	m.toolDuration.WithLabelValues(tool).Observe(duration.Seconds())
+	if code != "" {
+		m.toolErrors.WithLabelValues(tool, code).Inc()
+	}
+}
+
+// SessionOpened records the registration of a client session.
+func (m *Metrics) SessionOpened() {
+	m.activeSessions.Inc()
+}
+
+// SessionClosed records the unregistration of a client session.
+func (m *Metrics) SessionClosed() {
+	m.activeSessions.Dec()
+}
+
+// InstrumentClientConn returns a connection recording the duration of the calls made through the given one.
+func (m *Metrics) InstrumentClientConn(next grpc.ClientConnInterface) grpc.ClientConnInterface {
+	return &clientConn{next: next, metrics: m}
+}
+
+// TrackDataverse labels the gRPC calls to the given dataverse with its address, whatever the number of contracts
+// tracked already.
+func (m *Metrics) TrackDataverse(address string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.contracts[address] = struct{}{}
+}
+
+// RegisterCache exposes the statistics of the given query cache.
+func (m *Metrics) RegisterCache(c *cache.ClientConn) {
+	counter := func(name, help string, value func(cache.Stats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Namespace: Namespace, Name: name, Help: help},
+			func() float64 { return float64(value(c.Stats())) })
+	}
+	m.registry.MustRegister(
+		counter("cache_hits_total", "Number of queries answered from the cache.",
+			func(s cache.Stats) uint64 { return s.Hits }),
+		counter("cache_misses_total", "Number of queries forwarded to the node.",
+			func(s cache.Stats) uint64 { return s.Misses }),
+		counter("cache_evictions_total", "Number of cached responses evicted to make room for new ones.",
+			func(s cache.Stats) uint64 { return s.Evictions }),
+		counter("cache_invalidations_total", "Number of invalidations of the whole cache on new blocks.",
+			func(s cache.Stats) uint64 { return s.Invalidations }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "cache_entries",
+			Help:      "Number of cached responses.",
+		}, func() float64 { return float64(c.Stats().Entries) }),
+	)
+}
+
+type clientConn struct {
+	next    grpc.ClientConnInterface
+	metrics *Metrics
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *clientConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := c.next.Invoke(ctx, method, args, reply, opts...)
+
+	var contract string
+	if req, ok := args.(*wasmtypes.QuerySmartContractStateRequest); ok {
+		contract = c.metrics.contractLabel(req.Address, reply, err)
+	}
+	c.metrics.grpcDuration.WithLabelValues(method, contract, status.Code(err).String()).
+		Observe(time.Since(start).Seconds())
+
+	return err
+}
+
+// contractLabel returns the label of a call to the given contract, tracking a dataverse and its triplestore once told
+// by the answer resolving it, up to MaxContracts.
+func (m *Metrics) contractLabel(address string, reply any, err error) string {
+	var info struct {
+		TriplestoreAddress string `json:"triplestore_address"`
+	}
+	res, isState := reply.(*wasmtypes.QuerySmartContractStateResponse)
+	resolved := isState && err == nil && json.Unmarshal(res.Data, &info) == nil && info.TriplestoreAddress != ""
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if resolved {
+		for _, contract := range []string{address, info.TriplestoreAddress} {
+			if _, ok := m.contracts[contract]; !ok && len(m.contracts) < MaxContracts {
+				m.contracts[contract] = struct{}{}
+			}
+		}
+	}
+	if _, ok := m.contracts[address]; !ok {
+		return OtherContract
+	}
+
+	return address
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *clientConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return c.next.NewStream(ctx, desc, method, opts...)
+}
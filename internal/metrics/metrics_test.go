@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/cache"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scrape(m *Metrics) string {
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	Convey("Given the metrics of a server", t, func() {
+		m := New()
+
+		Convey("When tool calls and sessions are recorded", func() {
+			m.ObserveToolCall("get_dataverse_info", 10*time.Millisecond, "")
+			m.ObserveToolCall("get_dataverse_info", 20*time.Millisecond, "NODE_UNAVAILABLE")
+			m.SessionOpened()
+			m.SessionOpened()
+			m.SessionClosed()
+
+			Convey("Then they should be exposed", func() {
+				body := scrape(m)
+				So(body, ShouldContainSubstring, `axone_mcp_tool_calls_total{tool="get_dataverse_info"} 2`)
+				So(body, ShouldContainSubstring, `axone_mcp_tool_call_duration_seconds_count{tool="get_dataverse_info"} 2`)
+				So(body, ShouldContainSubstring,
+					`axone_mcp_tool_errors_total{code="NODE_UNAVAILABLE",tool="get_dataverse_info"} 1`)
+				So(body, ShouldContainSubstring, "axone_mcp_active_sessions 1")
+				So(body, ShouldContainSubstring, "go_goroutines")
+			})
+		})
+
+		Convey("When gRPC calls are made through an instrumented connection", func() {
+			ctrl := gomock.NewController(t)
+			Reset(ctrl.Finish)
+
+			next := mocks.NewMockClientConnInterface(ctrl)
+			next.EXPECT().Invoke(gomock.Any(), cache.SmartContractStateMethod, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _, reply any, _ ...any) error {
+					reply.(*wasmtypes.QuerySmartContractStateResponse).Data = []byte(
+						`{"name":"dv","triplestore_address":"axone1triplestore"}`)
+					return nil
+				})
+			next.EXPECT().Invoke(gomock.Any(), cache.SmartContractStateMethod, gomock.Any(), gomock.Any()).Return(nil)
+			next.EXPECT().Invoke(gomock.Any(), cache.SmartContractStateMethod, gomock.Any(), gomock.Any()).
+				Return(status.Error(codes.NotFound, "no such contract"))
+
+			cc := m.InstrumentClientConn(next)
+			invoke := func(address string) error {
+				return cc.Invoke(context.Background(), cache.SmartContractStateMethod,
+					&wasmtypes.QuerySmartContractStateRequest{Address: address, QueryData: []byte(`{}`)},
+					&wasmtypes.QuerySmartContractStateResponse{})
+			}
+			So(invoke("axone1dataverse"), ShouldBeNil)
+			So(invoke("axone1triplestore"), ShouldBeNil)
+			err := invoke("axone1contract")
+
+			Convey("Then their duration should be exposed by method, resolved contract and status code", func() {
+				So(status.Code(err), ShouldEqual, codes.NotFound)
+				body := scrape(m)
+				So(body, ShouldContainSubstring, `axone_mcp_grpc_call_duration_seconds_count{code="OK",`+
+					`contract="axone1dataverse",method="/cosmwasm.wasm.v1.Query/SmartContractState"} 1`)
+				So(body, ShouldContainSubstring, `axone_mcp_grpc_call_duration_seconds_count{code="OK",`+
+					`contract="axone1triplestore",method="/cosmwasm.wasm.v1.Query/SmartContractState"} 1`)
+				So(body, ShouldContainSubstring, `axone_mcp_grpc_call_duration_seconds_count{code="NotFound",`+
+					`contract="other",method="/cosmwasm.wasm.v1.Query/SmartContractState"} 1`)
+				So(body, ShouldNotContainSubstring, "axone1contract")
+			})
+		})
+
+		Convey("When more dataverses are resolved than the contracts tracked", func() {
+			ctrl := gomock.NewController(t)
+			Reset(ctrl.Finish)
+
+			next := mocks.NewMockClientConnInterface(ctrl)
+			next.EXPECT().Invoke(gomock.Any(), cache.SmartContractStateMethod, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, args, reply any, _ ...any) error {
+					address := args.(*wasmtypes.QuerySmartContractStateRequest).Address
+					reply.(*wasmtypes.QuerySmartContractStateResponse).Data = []byte(
+						fmt.Sprintf(`{"triplestore_address":"%s-triplestore"}`, address))
+					return nil
+				}).Times(MaxContracts/2 + 1)
+
+			m.TrackDataverse("axone1configured")
+			cc := m.InstrumentClientConn(next)
+			for i := range MaxContracts/2 + 1 {
+				So(cc.Invoke(context.Background(), cache.SmartContractStateMethod,
+					&wasmtypes.QuerySmartContractStateRequest{Address: fmt.Sprintf("axone1dataverse%d", i)},
+					&wasmtypes.QuerySmartContractStateResponse{}), ShouldBeNil)
+			}
+
+			Convey("Then the calls to the contracts beyond the limit should be labeled as other", func() {
+				body := scrape(m)
+				So(body, ShouldContainSubstring, `contract="axone1dataverse14"`)
+				So(body, ShouldNotContainSubstring, "axone1dataverse15-triplestore")
+				So(body, ShouldNotContainSubstring, `contract="axone1dataverse16"`)
+				So(body, ShouldContainSubstring, `contract="other"`)
+				So(m.contracts, ShouldHaveLength, MaxContracts)
+			})
+		})
+
+		Convey("When a cache is registered", func() {
+			ctrl := gomock.NewController(t)
+			Reset(ctrl.Finish)
+
+			next := mocks.NewMockClientConnInterface(ctrl)
+			next.EXPECT().Invoke(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(errors.New("unavailable"))
+			c := cache.New(next, cache.Config{TTL: time.Minute, Size: 2})
+			m.RegisterCache(c)
+			_ = c.Invoke(context.Background(), cache.SmartContractStateMethod,
+				&wasmtypes.QuerySmartContractStateRequest{Address: "axone1contract"}, &wasmtypes.QuerySmartContractStateResponse{})
+
+			Convey("Then its statistics should be exposed", func() {
+				body := scrape(m)
+				So(body, ShouldContainSubstring, "axone_mcp_cache_misses_total 1")
+				So(body, ShouldContainSubstring, "axone_mcp_cache_hits_total 0")
+				So(body, ShouldContainSubstring, "axone_mcp_cache_entries 0")
+			})
+		})
+	})
+}
@@ -0,0 +1,258 @@
+package ontology
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/axone-protocol/axone-mcp/internal/jsonld"
+	"github.com/samber/lo"
+)
+
+// Kind is the kind of an entry of the ontology.
+type Kind string
+
+const (
+	KindClass    Kind = "class"
+	KindProperty Kind = "property"
+	KindConcept  Kind = "concept"
+)
+
+const (
+	rdf  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	rdfs = "http://www.w3.org/2000/01/rdf-schema#"
+	owl  = "http://www.w3.org/2002/07/owl#"
+	skos = "http://www.w3.org/2004/02/skos/core#"
+)
+
+// MIMEType is the media type of the documents of the modules.
+const MIMEType = "application/ld+json"
+
+// files hold a hand-written excerpt of the Axone ontology v4, limited to the terms the credential templates rely on;
+// see v4/README.md for its provenance.
+//
+//go:embed v4
+var files embed.FS
+
+// Module is an ontology module, a schema or a thesaurus, defined by a JSON-LD document.
+type Module struct {
+	IRI         string `json:"iri"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// Document is the JSON-LD document defining the module.
+	Document []byte `json:"-"`
+}
+
+// Entry is a class, a property or a thesaurus concept defined by the ontology.
+type Entry struct {
+	IRI        string   `json:"iri"`
+	Kind       Kind     `json:"kind"`
+	Label      string   `json:"label,omitempty"`
+	Comment    string   `json:"comment,omitempty"`
+	Module     string   `json:"module"`
+	SubClassOf []string `json:"subClassOf,omitempty"`
+	Domain     []string `json:"domain,omitempty"`
+	Range      []string `json:"range,omitempty"`
+	InScheme   []string `json:"inScheme,omitempty"`
+}
+
+// Ontology is the excerpt of the Axone ontology embedded in the binary.
+type Ontology struct {
+	Modules []Module
+	Entries []Entry
+}
+
+var load = sync.OnceValues(func() (*Ontology, error) {
+	return parse(files)
+})
+
+// Load returns the excerpt of the ontology embedded in the binary.
+func Load() (*Ontology, error) {
+	return load()
+}
+
+// Module returns the module of the given IRI, if any.
+func (o *Ontology) Module(iri string) (Module, bool) {
+	i := slices.IndexFunc(o.Modules, func(m Module) bool { return m.IRI == iri })
+	if i < 0 {
+		return Module{}, false
+	}
+
+	return o.Modules[i], true
+}
+
+// Search returns the entries of the given kind, or of any kind if empty, matching all the words of the given keyword
+// in their IRI, label or comment, best matches first. An empty keyword matches all the entries.
+func (o *Ontology) Search(keyword string, kind Kind) []Entry {
+	words := strings.Fields(strings.ToLower(keyword))
+
+	type match struct {
+		entry Entry
+		score int
+	}
+	var matches []match
+	for _, e := range o.Entries {
+		if kind != "" && e.Kind != kind {
+			continue
+		}
+		if score := e.score(words); score > 0 {
+			matches = append(matches, match{e, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.entry.IRI, b.entry.IRI))
+	})
+
+	entries := make([]Entry, 0, len(matches))
+	for _, m := range matches {
+		entries = append(entries, m.entry)
+	}
+
+	return entries
+}
+
+// score rates how well the entry matches the given lower-cased words, 0 meaning a word is not matched.
+func (e *Entry) score(words []string) int {
+	name := strings.ToLower(LocalName(e.IRI))
+	total := 1
+	for _, w := range words {
+		var s int
+		switch {
+		case name == w:
+			s = 8
+		case strings.Contains(name, w):
+			s = 4
+		case strings.Contains(strings.ToLower(e.Label), w):
+			s = 2
+		case strings.Contains(strings.ToLower(e.Comment), w), strings.Contains(strings.ToLower(e.IRI), w):
+			s = 1
+		default:
+			return 0
+		}
+		total += s
+	}
+
+	return total
+}
+
+// LocalName returns the part of the IRI following its last slash or hash.
+func LocalName(iri string) string {
+	iri = strings.TrimSuffix(iri, "/")
+	return iri[strings.LastIndexAny(iri, "/#")+1:]
+}
+
+// parse parses the ontology modules of the given file system.
+func parse(fsys fs.FS) (*Ontology, error) {
+	o := &Ontology{}
+	err := fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".jsonld") {
+			return err
+		}
+
+		bz, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return err
+		}
+		module, entries, err := parseModule(bz)
+		if err != nil {
+			return fmt.Errorf("ontology module %s: %w", path, err)
+		}
+		o.Modules = append(o.Modules, module)
+		o.Entries = append(o.Entries, entries...)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(o.Modules, func(a, b Module) int { return cmp.Compare(a.IRI, b.IRI) })
+	slices.SortFunc(o.Entries, func(a, b Entry) int { return cmp.Compare(a.IRI, b.IRI) })
+
+	return o, nil
+}
+
+// parseModule parses the JSON-LD document of a module, along with the entries it defines.
+func parseModule(bz []byte) (Module, []Entry, error) {
+	var doc any
+	if err := json.Unmarshal(bz, &doc); err != nil {
+		return Module{}, nil, err
+	}
+	// The modules are self-contained, so no remote context is ever loaded.
+	dataset, err := jsonld.ToRDF(context.Background(), doc, &jsonld.EmbeddedLoader{})
+	if err != nil {
+		return Module{}, nil, err
+	}
+
+	subjects := map[string]map[string][]string{}
+	for _, q := range dataset {
+		if q.Subject.Kind != jsonld.IRI {
+			continue
+		}
+		props, ok := subjects[q.Subject.Value]
+		if !ok {
+			props = map[string][]string{}
+			subjects[q.Subject.Value] = props
+		}
+		props[q.Predicate.Value] = append(props[q.Predicate.Value], q.Object.Value)
+	}
+
+	module := Module{Document: bz}
+	var entries []Entry
+	for _, iri := range slices.Sorted(maps.Keys(subjects)) {
+		props := subjects[iri]
+		types := props[jsonld.RDFType]
+		if slices.Contains(types, owl+"Ontology") {
+			module.IRI = iri
+			module.Title = lo.FirstOrEmpty(props["http://purl.org/dc/terms/title"])
+			module.Description = lo.FirstOrEmpty(props[rdfs+"comment"])
+			continue
+		}
+
+		kind, ok := kindOf(types)
+		if !ok {
+			continue
+		}
+		entries = append(entries, Entry{
+			IRI:        iri,
+			Kind:       kind,
+			Label:      lo.FirstOrEmpty(slices.Concat(props[rdfs+"label"], props[skos+"prefLabel"])),
+			Comment:    lo.FirstOrEmpty(slices.Concat(props[rdfs+"comment"], props[skos+"definition"])),
+			SubClassOf: props[rdfs+"subClassOf"],
+			Domain:     props[rdfs+"domain"],
+			Range:      props[rdfs+"range"],
+			InScheme:   props[skos+"inScheme"],
+		})
+	}
+	if module.IRI == "" {
+		return Module{}, nil, errors.New("no owl:Ontology declared")
+	}
+	for i := range entries {
+		entries[i].Module = module.IRI
+	}
+
+	return module, entries, nil
+}
+
+// kindOf returns the kind of the entry of the given types, if it is an entry at all.
+func kindOf(types []string) (Kind, bool) {
+	switch {
+	case slices.Contains(types, skos+"Concept"):
+		return KindConcept, true
+	case slices.Contains(types, owl+"Class"), slices.Contains(types, rdfs+"Class"):
+		return KindClass, true
+	case slices.Contains(types, owl+"ObjectProperty"), slices.Contains(types, owl+"DatatypeProperty"),
+		slices.Contains(types, rdf+"Property"):
+		return KindProperty, true
+	default:
+		return "", false
+	}
+}
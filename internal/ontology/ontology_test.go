@@ -0,0 +1,167 @@
+package ontology
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLoad(t *testing.T) {
+	Convey("Given the embedded ontology", t, func() {
+		o, err := Load()
+		So(err, ShouldBeNil)
+
+		Convey("Then it should hold the credential schemas and thesauri queried by the server", func() {
+			module, ok := o.Module("https://w3id.org/axone/ontology/v4/schema/credential/governance/text/")
+			So(ok, ShouldBeTrue)
+			So(module.Title, ShouldEqual, "Governance text")
+			So(module.Document, ShouldNotBeEmpty)
+
+			iris := make([]string, 0, len(o.Entries))
+			for _, e := range o.Entries {
+				iris = append(iris, e.IRI)
+			}
+			for _, iri := range []string{
+				"https://w3id.org/axone/ontology/v4/schema/credential/governance/text/GovernanceTextCredential",
+				"https://w3id.org/axone/ontology/v4/schema/credential/governance/text/isGovernedBy",
+				"https://w3id.org/axone/ontology/v4/schema/credential/governance/text/fromGovernance",
+				"https://w3id.org/axone/ontology/v4/thesaurus/media-type/text_csv",
+			} {
+				So(iris, ShouldContain, iri)
+			}
+		})
+	})
+}
+
+func TestEmbeddedFiles(t *testing.T) {
+	Convey("Given the files of the embedded ontology", t, func() {
+		release, err := fs.ReadFile(files, "v4/RELEASE")
+		So(err, ShouldBeNil)
+		sums, err := fs.ReadFile(files, "v4/SHA256SUMS")
+		So(err, ShouldBeNil)
+		var modules []string
+		So(fs.WalkDir(files, "v4", func(path string, _ fs.DirEntry, err error) error {
+			if strings.HasSuffix(path, ".jsonld") {
+				modules = append(modules, path)
+			}
+			return err
+		}), ShouldBeNil)
+
+		Convey("Then they should be the ones of the recorded release, as checksummed when vendored", func() {
+			So(strings.TrimSpace(string(release)), ShouldNotBeEmpty)
+
+			checksummed := make([]string, 0, len(modules))
+			for _, line := range strings.Split(strings.TrimSpace(string(sums)), "\n") {
+				sum, path, ok := strings.Cut(line, "  ")
+				So(ok, ShouldBeTrue)
+				bz, err := fs.ReadFile(files, "v4/"+path)
+				So(err, ShouldBeNil)
+				actual := sha256.Sum256(bz)
+				So(hex.EncodeToString(actual[:]), ShouldEqual, sum)
+				checksummed = append(checksummed, "v4/"+path)
+			}
+			So(checksummed, ShouldHaveLength, len(modules))
+			for _, module := range modules {
+				So(checksummed, ShouldContain, module)
+			}
+		})
+	})
+}
+
+func TestSearch(t *testing.T) {
+	Convey("Given the embedded ontology", t, func() {
+		o, err := Load()
+		So(err, ShouldBeNil)
+
+		cases := []struct {
+			keyword  string
+			kind     Kind
+			expected []string
+		}{
+			{
+				keyword: "fromgovernance",
+				expected: []string{
+					"https://w3id.org/axone/ontology/v4/schema/credential/governance/text/fromGovernance",
+				},
+			},
+			{
+				keyword: "governance text",
+				kind:    KindClass,
+				expected: []string{
+					"https://w3id.org/axone/ontology/v4/schema/credential/governance/text/GovernanceText",
+					"https://w3id.org/axone/ontology/v4/schema/credential/governance/text/GovernanceTextCredential",
+				},
+			},
+			{
+				keyword:  "csv",
+				kind:     KindConcept,
+				expected: []string{"https://w3id.org/axone/ontology/v4/thesaurus/media-type/text_csv"},
+			},
+			{
+				keyword: "diploma",
+			},
+		}
+
+		for _, tc := range cases {
+			Convey("When searching for "+tc.keyword+" entries of kind "+string(tc.kind), func() {
+				entries := o.Search(tc.keyword, tc.kind)
+
+				Convey("Then the best matches should come first", func() {
+					So(len(entries), ShouldBeGreaterThanOrEqualTo, len(tc.expected))
+					for i, iri := range tc.expected {
+						So(entries[i].IRI, ShouldEqual, iri)
+					}
+					if tc.expected == nil {
+						So(entries, ShouldBeEmpty)
+					}
+				})
+			})
+		}
+
+		Convey("When searching with an empty keyword", func() {
+			entries := o.Search("", KindProperty)
+
+			Convey("Then all the entries of the kind should be returned", func() {
+				So(entries, ShouldNotBeEmpty)
+				for _, e := range entries {
+					So(e.Kind, ShouldEqual, KindProperty)
+				}
+			})
+		})
+	})
+}
+
+func TestParse(t *testing.T) {
+	Convey("Given a module not declaring itself as an ontology", t, func() {
+		fsys := fstest.MapFS{"v4/schema/foo.jsonld": {Data: []byte(`{"@id": "https://example.org/Foo"}`)}}
+
+		Convey("When parsing it", func() {
+			_, err := parse(fsys)
+
+			Convey("Then it should be rejected", func() {
+				So(err, ShouldBeError, "ontology module v4/schema/foo.jsonld: no owl:Ontology declared")
+			})
+		})
+	})
+}
+
+func TestLocalName(t *testing.T) {
+	Convey("Given IRIs", t, func() {
+		for iri, expected := range map[string]string{
+			"https://w3id.org/axone/ontology/v4/thesaurus/topic/":       "topic",
+			"https://w3id.org/axone/ontology/v4/thesaurus/topic/energy": "energy",
+			"http://www.w3.org/2002/07/owl#Class":                       "Class",
+		} {
+			Convey("When getting the local name of "+iri, func() {
+				Convey("Then it should be "+expected, func() {
+					So(LocalName(iri), ShouldEqual, expected)
+				})
+			})
+		}
+	})
+}
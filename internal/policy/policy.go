@@ -0,0 +1,192 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Wildcard matches any principal or session.
+const Wildcard = "*"
+
+// DataverseArgument is the name of the tool argument holding the dataverse address,
+// constrained by the dataverses listed in a rule.
+const DataverseArgument = "dataverse"
+
+var ErrDenied = errors.New("access denied")
+
+// Subject identifies the caller a policy is evaluated for.
+type Subject struct {
+	// Principal is the authenticated identity of the caller, if any.
+	Principal string
+	// SessionID is the identifier of the MCP session the call belongs to.
+	SessionID string
+}
+
+// Policy maps subjects to the tools and arguments they are allowed to use.
+// A call is authorized as soon as one rule matching the subject allows it; anything else is denied.
+type Policy struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule grants access to a set of tools to a set of principals and/or sessions.
+type Rule struct {
+	// Principals lists the principals the rule applies to ("*" for any, including anonymous callers).
+	Principals []string `yaml:"principals"`
+	// Sessions lists the session ids the rule applies to ("*" for any).
+	Sessions []string `yaml:"sessions"`
+	// Tools lists the tools names, or shell patterns (e.g. "get_*"), the rule allows.
+	Tools []string `yaml:"tools"`
+	// Dataverses restricts the dataverse addresses the tools may be invoked with (no restriction if empty).
+	Dataverses []string `yaml:"dataverses"`
+	// Arguments constrains the values of the named tool arguments.
+	Arguments map[string]Constraint `yaml:"arguments"`
+}
+
+// Constraint restricts the value of a tool argument.
+type Constraint struct {
+	// Pattern is a regular expression the whole argument value must match.
+	Pattern string `yaml:"pattern"`
+	// Enum lists the values the argument may take.
+	Enum []string `yaml:"enum"`
+
+	re *regexp.Regexp
+}
+
+// Load reads and compiles the policy stored in the given YAML file.
+func Load(filename string) (*Policy, error) {
+	bz, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("read policy file: %w", err)
+	}
+
+	return Parse(bz)
+}
+
+// Parse decodes and compiles a YAML policy.
+func Parse(bz []byte) (*Policy, error) {
+	var p Policy
+	if err := yaml.UnmarshalStrict(bz, &p); err != nil {
+		return nil, fmt.Errorf("decode policy: %w", err)
+	}
+
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (p *Policy) compile() error {
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if len(rule.Principals) == 0 && len(rule.Sessions) == 0 {
+			return fmt.Errorf("rule #%d: at least one principal or session is required", i)
+		}
+		for _, tool := range rule.Tools {
+			if _, err := path.Match(tool, ""); err != nil {
+				return fmt.Errorf("rule #%d: invalid tool pattern %q: %w", i, tool, err)
+			}
+		}
+		for name, constraint := range rule.Arguments {
+			if constraint.Pattern == "" {
+				continue
+			}
+			re, err := regexp.Compile("^(?:" + constraint.Pattern + ")$")
+			if err != nil {
+				return fmt.Errorf("rule #%d: invalid pattern for argument %q: %w", i, name, err)
+			}
+			constraint.re = re
+			rule.Arguments[name] = constraint
+		}
+	}
+
+	return nil
+}
+
+// AllowsTool tells whether the subject may use the given tool, regardless of its arguments.
+func (p *Policy) AllowsTool(subject Subject, tool string) bool {
+	return slices.ContainsFunc(p.Rules, func(rule Rule) bool {
+		return rule.appliesTo(subject) && rule.allowsTool(tool)
+	})
+}
+
+// Authorize checks the subject may invoke the given tool with the given arguments.
+// It returns an error wrapping ErrDenied explaining why the call is rejected otherwise.
+func (p *Policy) Authorize(subject Subject, tool string, args map[string]any) error {
+	var reason error
+	for _, rule := range p.Rules {
+		if !rule.appliesTo(subject) || !rule.allowsTool(tool) {
+			continue
+		}
+		err := rule.checkArguments(args)
+		if err == nil {
+			return nil
+		}
+		if reason == nil {
+			reason = err
+		}
+	}
+
+	if reason == nil {
+		return fmt.Errorf("%w: tool %s is not allowed", ErrDenied, tool)
+	}
+	return fmt.Errorf("%w: %w", ErrDenied, reason)
+}
+
+func (r Rule) appliesTo(subject Subject) bool {
+	return matchesAny(r.Principals, subject.Principal) || matchesAny(r.Sessions, subject.SessionID)
+}
+
+func (r Rule) allowsTool(tool string) bool {
+	return slices.ContainsFunc(r.Tools, func(pattern string) bool {
+		ok, _ := path.Match(pattern, tool)
+		return ok
+	})
+}
+
+func (r Rule) checkArguments(args map[string]any) error {
+	if value, ok := args[DataverseArgument]; ok && len(r.Dataverses) > 0 {
+		dataverse, isString := value.(string)
+		if !isString {
+			return fmt.Errorf("dataverse %v is not a string", value)
+		}
+		if !slices.Contains(r.Dataverses, dataverse) {
+			return fmt.Errorf("dataverse %s is not allowed", dataverse)
+		}
+	}
+
+	for name, constraint := range r.Arguments {
+		value, ok := args[name]
+		if !ok {
+			continue
+		}
+		if err := constraint.check(fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("argument %q %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (c Constraint) check(value string) error {
+	if len(c.Enum) > 0 && !slices.Contains(c.Enum, value) {
+		return fmt.Errorf("must be one of %v", c.Enum)
+	}
+	if c.re != nil && !c.re.MatchString(value) {
+		return fmt.Errorf("must match %q", c.Pattern)
+	}
+
+	return nil
+}
+
+func matchesAny(candidates []string, value string) bool {
+	return slices.ContainsFunc(candidates, func(candidate string) bool {
+		return candidate == Wildcard || (value != "" && candidate == value)
+	})
+}
@@ -0,0 +1,152 @@
+package policy
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testPolicy = `
+rules:
+  - principals: ["CN=auditor"]
+    tools: ["get_*"]
+    dataverses: ["axone1dataverse"]
+  - sessions: ["session-42"]
+    tools: ["get_resource_governance_code"]
+    arguments:
+      resource:
+        pattern: "did:key:.+"
+  - principals: ["*"]
+    tools: ["get_dataverse_info"]
+    arguments:
+      dataverse:
+        enum: ["axone1public"]
+`
+
+func TestParse(t *testing.T) {
+	Convey("Given invalid policies", t, func() {
+		tests := []struct {
+			name     string
+			policy   string
+			expected string
+		}{
+			{
+				name:     "unknown field",
+				policy:   "rules:\n  - principals: [\"*\"]\n    foo: bar\n",
+				expected: "decode policy",
+			},
+			{
+				name:     "rule without subject",
+				policy:   "rules:\n  - tools: [\"*\"]\n",
+				expected: "rule #0: at least one principal or session is required",
+			},
+			{
+				name:     "invalid tool pattern",
+				policy:   "rules:\n  - principals: [\"*\"]\n    tools: [\"[\"]\n",
+				expected: `rule #0: invalid tool pattern "["`,
+			},
+			{
+				name:     "invalid argument pattern",
+				policy:   "rules:\n  - principals: [\"*\"]\n    arguments:\n      foo:\n        pattern: \"(\"\n",
+				expected: `rule #0: invalid pattern for argument "foo"`,
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("When parsing a policy with %s", tt.name), func() {
+				_, err := Parse([]byte(tt.policy))
+
+				Convey("Then an error should be returned", func() {
+					So(err, ShouldNotBeNil)
+					So(err.Error(), ShouldContainSubstring, tt.expected)
+				})
+			})
+		}
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	Convey("Given a policy", t, func() {
+		p, err := Parse([]byte(testPolicy))
+		So(err, ShouldBeNil)
+
+		tests := []struct {
+			subject  Subject
+			tool     string
+			args     map[string]any
+			visible  bool
+			expected string
+		}{
+			{
+				subject: Subject{Principal: "CN=auditor"},
+				tool:    "get_resource_governance_code",
+				args:    map[string]any{"dataverse": "axone1dataverse"},
+				visible: true,
+			},
+			{
+				subject:  Subject{Principal: "CN=auditor"},
+				tool:     "get_resource_governance_code",
+				args:     map[string]any{"dataverse": "axone1other"},
+				visible:  true,
+				expected: "access denied: dataverse axone1other is not allowed",
+			},
+			{
+				subject:  Subject{Principal: "CN=auditor"},
+				tool:     "get_resource_governance_code",
+				args:     map[string]any{"dataverse": []any{"axone1dataverse"}},
+				visible:  true,
+				expected: "access denied: dataverse [axone1dataverse] is not a string",
+			},
+			{
+				subject: Subject{SessionID: "session-42"},
+				tool:    "get_resource_governance_code",
+				args:    map[string]any{"resource": "did:key:zQ3sh"},
+				visible: true,
+			},
+			{
+				subject:  Subject{SessionID: "session-42"},
+				tool:     "get_resource_governance_code",
+				args:     map[string]any{"resource": "did:web:example.com"},
+				visible:  true,
+				expected: `access denied: argument "resource" must match "did:key:.+"`,
+			},
+			{
+				subject:  Subject{SessionID: "session-43"},
+				tool:     "get_resource_governance_code",
+				visible:  false,
+				expected: "access denied: tool get_resource_governance_code is not allowed",
+			},
+			{
+				subject: Subject{},
+				tool:    "get_dataverse_info",
+				args:    map[string]any{"dataverse": "axone1public"},
+				visible: true,
+			},
+			{
+				subject:  Subject{},
+				tool:     "get_dataverse_info",
+				args:     map[string]any{"dataverse": "axone1private"},
+				visible:  true,
+				expected: `access denied: argument "dataverse" must be one of [axone1public]`,
+			},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("When %+v calls %s with %v", tt.subject, tt.tool, tt.args), func() {
+				visible := p.AllowsTool(tt.subject, tt.tool)
+				err := p.Authorize(tt.subject, tt.tool, tt.args)
+
+				Convey("Then the decision should be the expected one", func() {
+					So(visible, ShouldEqual, tt.visible)
+					if tt.expected == "" {
+						So(err, ShouldBeNil)
+					} else {
+						So(err, ShouldBeError, tt.expected)
+						So(err, ShouldWrap, ErrDenied)
+					}
+				})
+			})
+		}
+	})
+}
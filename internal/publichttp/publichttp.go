@@ -0,0 +1,56 @@
+package publichttp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenAddress = errors.New("forbidden address")
+
+// sharedAddressSpace is the range of the carrier-grade NAT addresses (RFC 6598), not reachable from the internet.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// NewClient returns an http.Client to fetch the URLs given by callers: it has a sensible timeout, ignores the proxy
+// settings, does not follow redirects and only connects to public addresses.
+func NewClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: dialPublicOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:       10 * time.Second,
+		Transport:     transport,
+		CheckRedirect: NoRedirect,
+	}
+}
+
+// NoRedirect makes an http.Client return the redirect responses instead of following them.
+func NoRedirect(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// dialPublicOnly rejects the connections to loopback, private, link-local and other addresses not reachable from the
+// internet. It is checked once the host resolved, so that a public name resolving to a private address is rejected too.
+func dialPublicOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrForbiddenAddress, ip)
+	}
+
+	return nil
+}
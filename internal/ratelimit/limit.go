@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+var ErrInvalidLimit = errors.New("invalid rate limit")
+
+var units = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+}
+
+// Limit describes a token bucket: Rate tokens are added per second, up to Burst tokens.
+// The zero value means unlimited.
+type Limit struct {
+	Rate  rate.Limit
+	Burst int
+}
+
+// Unlimited tells whether the limit does not restrict anything.
+func (l Limit) Unlimited() bool {
+	return l.Rate == 0
+}
+
+// ParseLimit parses a limit expressed as "<events>/<unit>[:<burst>]", where unit is one of "s", "m" or "h"
+// (e.g. "10/s", "100/m:20"). The burst defaults to the number of events. An empty string means unlimited.
+func ParseLimit(s string) (Limit, error) {
+	if s == "" {
+		return Limit{}, nil
+	}
+
+	spec, burstSpec, hasBurst := strings.Cut(s, ":")
+	eventsSpec, unitSpec, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("%w %q: expected <events>/<unit>[:<burst>]", ErrInvalidLimit, s)
+	}
+
+	events, err := strconv.Atoi(eventsSpec)
+	if err != nil || events <= 0 {
+		return Limit{}, fmt.Errorf("%w %q: events must be a positive integer", ErrInvalidLimit, s)
+	}
+	unit, ok := units[unitSpec]
+	if !ok {
+		return Limit{}, fmt.Errorf("%w %q: unit must be one of s, m, h", ErrInvalidLimit, s)
+	}
+
+	burst := events
+	if hasBurst {
+		burst, err = strconv.Atoi(burstSpec)
+		if err != nil || burst <= 0 {
+			return Limit{}, fmt.Errorf("%w %q: burst must be a positive integer", ErrInvalidLimit, s)
+		}
+	}
+
+	return Limit{Rate: rate.Every(unit / time.Duration(events)), Burst: burst}, nil
+}
@@ -0,0 +1,45 @@
+package ratelimit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/time/rate"
+)
+
+func TestParseLimit(t *testing.T) {
+	Convey("Given limit specifications", t, func() {
+		tests := []struct {
+			spec     string
+			expected Limit
+			err      string
+		}{
+			{spec: "", expected: Limit{}},
+			{spec: "10/s", expected: Limit{Rate: rate.Every(100 * time.Millisecond), Burst: 10}},
+			{spec: "60/m:5", expected: Limit{Rate: rate.Every(time.Second), Burst: 5}},
+			{spec: "1/h", expected: Limit{Rate: rate.Every(time.Hour), Burst: 1}},
+			{spec: "10", err: `invalid rate limit "10": expected <events>/<unit>[:<burst>]`},
+			{spec: "0/s", err: `invalid rate limit "0/s": events must be a positive integer`},
+			{spec: "10/d", err: `invalid rate limit "10/d": unit must be one of s, m, h`},
+			{spec: "10/s:x", err: `invalid rate limit "10/s:x": burst must be a positive integer`},
+		}
+
+		for _, tt := range tests {
+			Convey(fmt.Sprintf("When parsing %q", tt.spec), func() {
+				got, err := ParseLimit(tt.spec)
+
+				Convey("Then the result should be the expected one", func() {
+					if tt.err != "" {
+						So(err, ShouldBeError, tt.err)
+						So(err, ShouldWrap, ErrInvalidLimit)
+					} else {
+						So(err, ShouldBeNil)
+						So(got, ShouldResemble, tt.expected)
+					}
+				})
+			})
+		}
+	})
+}
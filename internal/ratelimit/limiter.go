@@ -0,0 +1,195 @@
+package ratelimit
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Scope tells what a limit applies to.
+type Scope string
+
+const (
+	ScopeSession   Scope = "session"
+	ScopePrincipal Scope = "principal"
+	ScopeTool      Scope = "tool"
+	ScopeQuota     Scope = "quota"
+)
+
+// Config holds the limits enforced by a Limiter.
+type Config struct {
+	// Session limits the calls made within a single session.
+	Session Limit
+	// Principal limits the calls made by a single principal, across its sessions.
+	Principal Limit
+	// Tool limits the calls made to a single tool, across all callers.
+	Tool Limit
+	// DailyQuota is the maximum number of calls per principal (or per session for anonymous callers) per UTC day;
+	// 0 means unlimited.
+	DailyQuota int
+}
+
+// ExceededError is returned when a call exceeds one of the limits.
+type ExceededError struct {
+	Scope      Scope
+	Key        string
+	RetryAfter time.Duration
+}
+
+func (e *ExceededError) Error() string {
+	// Round up so that retrying after the advertised delay is always enough.
+	retryAfter := (e.RetryAfter + time.Second - 1).Truncate(time.Second)
+	if e.Scope == ScopeQuota {
+		return fmt.Sprintf("daily quota exceeded for %s; retry after %s", e.Key, retryAfter)
+	}
+	return fmt.Sprintf("rate limit exceeded for %s %s; retry after %s", e.Scope, e.Key, retryAfter)
+}
+
+// evictInterval is the minimum time between two evictions of the idle buckets.
+const evictInterval = time.Minute
+
+// Limiter enforces token bucket limits per session, principal and tool, as well as daily quotas.
+//
+// The buckets left idle long enough to be full again are evicted, as they are then no different from new ones.
+type Limiter struct {
+	config Config
+	store  QuotaStore
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*rate.Limiter
+	lastEvict time.Time
+}
+
+// New creates a limiter enforcing the given configuration, counting daily quotas in the given store.
+func New(config Config, store QuotaStore) *Limiter {
+	return &Limiter{
+		config:  config,
+		store:   store,
+		now:     time.Now,
+		buckets: make(map[string]*rate.Limiter),
+	}
+}
+
+// Allow records a call to the given tool and tells whether it is allowed, returning an *ExceededError if not.
+func (l *Limiter) Allow(sessionID, principal, tool string) error {
+	now := l.now()
+	l.evict(now)
+
+	type bucket struct {
+		scope Scope
+		key   string
+		limit Limit
+	}
+	candidates := []bucket{
+		{scope: ScopeSession, key: sessionID, limit: l.config.Session},
+		{scope: ScopePrincipal, key: principal, limit: l.config.Principal},
+		{scope: ScopeTool, key: tool, limit: l.config.Tool},
+	}
+
+	reservations := make([]*rate.Reservation, 0, len(candidates))
+	cancelAll := func() {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+
+	for _, c := range candidates {
+		if c.key == "" || c.limit.Unlimited() {
+			continue
+		}
+		r := l.bucket(c.scope, c.key, c.limit).ReserveN(now, 1)
+		reservations = append(reservations, r)
+		if delay := r.DelayFrom(now); delay > 0 {
+			cancelAll()
+			return &ExceededError{Scope: c.scope, Key: c.key, RetryAfter: delay}
+		}
+	}
+
+	if err := l.consumeQuota(now, sessionID, principal); err != nil {
+		cancelAll()
+		return err
+	}
+
+	return nil
+}
+
+// Forget drops the state kept for the given session.
+func (l *Limiter) Forget(sessionID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.buckets, bucketKey(ScopeSession, sessionID))
+}
+
+// Close releases the quota store, if it holds any resource.
+func (l *Limiter) Close() error {
+	if closer, ok := l.store.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// evict drops the buckets full at the given time, at most once per evictInterval.
+func (l *Limiter) evict(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastEvict) < evictInterval {
+		return
+	}
+	l.lastEvict = now
+
+	for k, b := range l.buckets {
+		if b.TokensAt(now) >= float64(b.Burst()) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+func (l *Limiter) consumeQuota(now time.Time, sessionID, principal string) error {
+	if l.config.DailyQuota <= 0 {
+		return nil
+	}
+
+	key := principal
+	if key == "" {
+		key = sessionID
+	}
+	if key == "" {
+		return nil
+	}
+
+	day := now.UTC().Truncate(24 * time.Hour)
+	count, err := l.store.Increment(key, day)
+	if err != nil {
+		return fmt.Errorf("count daily quota: %w", err)
+	}
+	if count > l.config.DailyQuota {
+		return &ExceededError{Scope: ScopeQuota, Key: key, RetryAfter: day.Add(24 * time.Hour).Sub(now)}
+	}
+
+	return nil
+}
+
+func (l *Limiter) bucket(scope Scope, key string, limit Limit) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	k := bucketKey(scope, key)
+	b, ok := l.buckets[k]
+	if !ok {
+		b = rate.NewLimiter(limit.Rate, limit.Burst)
+		l.buckets[k] = b
+	}
+
+	return b
+}
+
+func bucketKey(scope Scope, key string) string {
+	return string(scope) + ":" + key
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestLimiter(t *testing.T) {
+	Convey("Given a limiter", t, func() {
+		now := time.Date(2025, 6, 1, 23, 59, 0, 0, time.UTC)
+		newLimiter := func(config Config) *Limiter {
+			l := New(config, NewMemoryStore())
+			l.now = func() time.Time { return now }
+			return l
+		}
+
+		Convey("When a session exceeds its limit", func() {
+			l := newLimiter(Config{Session: Limit{Rate: 1, Burst: 2}})
+			So(l.Allow("s1", "", "tool"), ShouldBeNil)
+			So(l.Allow("s1", "", "tool"), ShouldBeNil)
+			err := l.Allow("s1", "", "tool")
+
+			Convey("Then the call should be rejected with the delay to wait", func() {
+				var exceeded *ExceededError
+				So(errors.As(err, &exceeded), ShouldBeTrue)
+				So(exceeded.Scope, ShouldEqual, ScopeSession)
+				So(exceeded.RetryAfter, ShouldEqual, time.Second)
+				So(err, ShouldBeError, "rate limit exceeded for session s1; retry after 1s")
+			})
+
+			Convey("Then other sessions should not be affected", func() {
+				So(l.Allow("s2", "", "tool"), ShouldBeNil)
+			})
+
+			Convey("Then the session should be allowed again once the delay elapsed", func() {
+				now = now.Add(time.Second)
+				So(l.Allow("s1", "", "tool"), ShouldBeNil)
+			})
+		})
+
+		Convey("When a tool exceeds its limit", func() {
+			l := newLimiter(Config{Session: Limit{Rate: 10, Burst: 10}, Tool: Limit{Rate: 1, Burst: 1}})
+			So(l.Allow("s1", "", "tool"), ShouldBeNil)
+			err := l.Allow("s2", "", "tool")
+
+			Convey("Then the call should be rejected for every caller", func() {
+				So(err, ShouldBeError, "rate limit exceeded for tool tool; retry after 1s")
+			})
+
+			Convey("Then the tokens of the other buckets should be given back", func() {
+				So(l.bucket(ScopeSession, "s2", l.config.Session).TokensAt(now), ShouldEqual, 10)
+			})
+		})
+
+		Convey("When the buckets of former sessions are left idle until full again", func() {
+			l := newLimiter(Config{Session: Limit{Rate: 1, Burst: 2}})
+			So(l.Allow("s1", "", "tool"), ShouldBeNil)
+			now = now.Add(evictInterval)
+			So(l.Allow("s2", "", "tool"), ShouldBeNil)
+			So(l.Allow("s2", "", "tool"), ShouldBeNil)
+
+			Convey("Then they should be evicted, the others being kept", func() {
+				So(l.buckets, ShouldHaveLength, 1)
+				So(l.buckets, ShouldContainKey, bucketKey(ScopeSession, "s2"))
+			})
+
+			Convey("Then the evicted sessions should be allowed as new ones", func() {
+				So(l.Allow("s1", "", "tool"), ShouldBeNil)
+				So(l.Allow("s1", "", "tool"), ShouldBeNil)
+			})
+		})
+
+		Convey("When a principal exceeds its daily quota", func() {
+			l := newLimiter(Config{DailyQuota: 2})
+			So(l.Allow("s1", "alice", "tool"), ShouldBeNil)
+			So(l.Allow("s2", "alice", "tool"), ShouldBeNil)
+			err := l.Allow("s3", "alice", "tool")
+
+			Convey("Then the call should be rejected until the next day", func() {
+				So(err, ShouldBeError, "daily quota exceeded for alice; retry after 1m0s")
+			})
+
+			Convey("Then the quota should be reset the next day", func() {
+				now = now.Add(time.Minute)
+				So(l.Allow("s3", "alice", "tool"), ShouldBeNil)
+			})
+		})
+	})
+}
+
+func TestBoltStore(t *testing.T) {
+	Convey("Given a bbolt quota store", t, func() {
+		path := filepath.Join(t.TempDir(), "quotas.db")
+		store, err := OpenBoltStore(path)
+		So(err, ShouldBeNil)
+		Reset(func() { _ = store.Close() })
+
+		day := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		Convey("When incrementing counters", func() {
+			for range 2 {
+				_, err := store.Increment("alice", day)
+				So(err, ShouldBeNil)
+			}
+			count, err := store.Increment("alice", day)
+			So(err, ShouldBeNil)
+
+			Convey("Then the counts should accumulate", func() {
+				So(count, ShouldEqual, 3)
+			})
+
+			Convey("Then the counts should survive a restart", func() {
+				So(store.Close(), ShouldBeNil)
+				store, err = OpenBoltStore(path)
+				So(err, ShouldBeNil)
+
+				count, err := store.Increment("alice", day)
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 4)
+			})
+
+			Convey("Then the counts should start over the next day", func() {
+				count, err := store.Increment("alice", day.AddDate(0, 0, 1))
+				So(err, ShouldBeNil)
+				So(count, ShouldEqual, 1)
+			})
+		})
+	})
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// QuotaStore counts the calls made by a key over a day.
+type QuotaStore interface {
+	// Increment adds one call to the counter of the key for the given day and returns the new count.
+	Increment(key string, day time.Time) (int, error)
+}
+
+// MemoryStore is a QuotaStore keeping counters in memory, only for the current day.
+type MemoryStore struct {
+	mu     sync.Mutex
+	day    time.Time
+	counts map[string]int
+}
+
+// NewMemoryStore creates an empty in-memory quota store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{counts: make(map[string]int)}
+}
+
+// Increment implements QuotaStore.
+func (s *MemoryStore) Increment(key string, day time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !day.Equal(s.day) {
+		s.day = day
+		clear(s.counts)
+	}
+	s.counts[key]++
+
+	return s.counts[key], nil
+}
+
+var quotasBucket = []byte("quotas")
+
+// BoltStore is a QuotaStore persisting counters in a bbolt file, so that quotas survive restarts.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens, or creates, the bbolt file at the given path.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(quotasBucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Increment implements QuotaStore. Counters of previous days are pruned along the way.
+func (s *BoltStore) Increment(key string, day time.Time) (int, error) {
+	var count uint64
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(quotasBucket)
+		prefix := []byte(day.Format(time.DateOnly) + "/")
+
+		c := b.Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, prefix) < 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+
+		k := append(prefix, key...)
+		if v := b.Get(k); len(v) == 8 {
+			count = binary.BigEndian.Uint64(v)
+		}
+		count++
+
+		return b.Put(k, binary.BigEndian.AppendUint64(nil, count))
+	})
+
+	return int(count), err //nolint:gosec
+}
+
+// Close releases the bbolt file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,272 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/axone-protocol/axone-mcp/internal/axone/wasm"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const SmartContractStateMethod = wasm.SmartContractStateMethod
+
+// Interaction is a smart contract query made to the node, along with its outcome.
+type Interaction struct {
+	// Address is the address of the queried contract.
+	Address string `json:"address"`
+	// Query is the JSON query sent to the contract.
+	Query json.RawMessage `json:"query"`
+	// Height is the block height the query targeted, zero for the latest one.
+	Height int64 `json:"height,omitempty"`
+	// Response is the JSON response of the contract, if the query succeeded.
+	Response json.RawMessage `json:"response,omitempty"`
+	// ResponseHeight is the block height the query was answered at, if reported by the node.
+	ResponseHeight int64 `json:"responseHeight,omitempty"`
+	// Error is the status of the query, if it failed.
+	Error *Status `json:"error,omitempty"`
+}
+
+// Status is the gRPC status of a failed query.
+type Status struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+// Fixture is the content of a fixture file: the interactions recorded, in the order they were first made.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// key identifies an interaction by its query, the JSON of which is compacted to ignore its formatting.
+func (i Interaction) key() string {
+	return i.Address + "\x00" + string(compact(i.Query)) + "\x00" + strconv.FormatInt(i.Height, 10)
+}
+
+// compact returns the given JSON without insignificant spaces, as is if invalid.
+func compact(raw json.RawMessage) json.RawMessage {
+	var buf bytes.Buffer
+	if raw == nil || json.Compact(&buf, raw) != nil {
+		return raw
+	}
+
+	return buf.Bytes()
+}
+
+// Load reads the fixture file at the given path.
+func Load(path string) (*Fixture, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(bz, &fixture); err != nil {
+		return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+	}
+	for n, interaction := range fixture.Interactions {
+		fixture.Interactions[n].Query = compact(interaction.Query)
+		fixture.Interactions[n].Response = compact(interaction.Response)
+	}
+
+	return &fixture, nil
+}
+
+// Save writes the fixture to the file at the given path, atomically.
+func (f *Fixture) Save(path string) error {
+	bz, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(bz, '\n')); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Recorder is a grpc.ClientConnInterface recording the smart contract queries made through it, and their outcome, to
+// a fixture file written once closed. A query made again replaces its previous recording. Any other call is passed
+// through without being recorded.
+type Recorder struct {
+	next grpc.ClientConnInterface
+	path string
+
+	mu      sync.Mutex
+	fixture Fixture
+	index   map[string]int
+}
+
+var (
+	_ grpc.ClientConnInterface = (*Recorder)(nil)
+	_ io.Closer                = (*Recorder)(nil)
+)
+
+// NewRecorder wraps the given connection with a recorder, adding to the interactions of the fixture file at the given
+// path if it exists.
+func NewRecorder(next grpc.ClientConnInterface, path string) (*Recorder, error) {
+	r := &Recorder{next: next, path: path, index: make(map[string]int)}
+
+	fixture, err := Load(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		for _, interaction := range fixture.Interactions {
+			r.add(interaction)
+		}
+	}
+
+	return r, nil
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (r *Recorder) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	in, okIn := args.(*wasmtypes.QuerySmartContractStateRequest)
+	out, okOut := reply.(*wasmtypes.QuerySmartContractStateResponse)
+	if method != SmartContractStateMethod || !okIn || !okOut {
+		return r.next.Invoke(ctx, method, args, reply, opts...)
+	}
+
+	var header metadata.MD
+	err := r.next.Invoke(ctx, method, args, reply, append(opts, grpc.Header(&header))...)
+
+	interaction := Interaction{Address: in.Address, Query: append(json.RawMessage(nil), in.QueryData...)}
+	interaction.Height, _ = height.FromOutgoingContext(ctx)
+	if err != nil {
+		s := status.Convert(err)
+		interaction.Error = &Status{Code: s.Code(), Message: s.Message()}
+	} else {
+		interaction.Response = append(json.RawMessage(nil), out.Data...)
+		interaction.ResponseHeight, _ = height.FromMetadata(header)
+	}
+	if recordErr := r.record(interaction); recordErr != nil {
+		log.Logger.Warn().Err(recordErr).Str("contract", in.Address).Str("file", r.path).Msg("failed to record the query")
+	}
+
+	return err
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (r *Recorder) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return r.next.NewStream(ctx, desc, method, opts...)
+}
+
+func (r *Recorder) record(interaction Interaction) error {
+	if !json.Valid(interaction.Query) || (interaction.Response != nil && !json.Valid(interaction.Response)) {
+		return errors.New("query or response is not JSON")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.add(interaction)
+
+	return nil
+}
+
+// Close writes the interactions recorded to the fixture file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.fixture.Save(r.path)
+}
+
+func (r *Recorder) add(interaction Interaction) {
+	key := interaction.key()
+	if n, ok := r.index[key]; ok {
+		r.fixture.Interactions[n] = interaction
+		return
+	}
+
+	r.index[key] = len(r.fixture.Interactions)
+	r.fixture.Interactions = append(r.fixture.Interactions, interaction)
+}
+
+// Replayer is a grpc.ClientConnInterface answering the smart contract queries with the outcome recorded in a fixture,
+// without any node. A query not recorded fails as if the node was unavailable, and any other call as unimplemented.
+type Replayer struct {
+	interactions map[string]Interaction
+}
+
+var _ grpc.ClientConnInterface = (*Replayer)(nil)
+
+// NewReplayer returns a connection replaying the interactions of the given fixture.
+func NewReplayer(fixture *Fixture) *Replayer {
+	r := &Replayer{interactions: make(map[string]Interaction, len(fixture.Interactions))}
+	for _, interaction := range fixture.Interactions {
+		r.interactions[interaction.key()] = interaction
+	}
+
+	return r
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (r *Replayer) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	in, okIn := args.(*wasmtypes.QuerySmartContractStateRequest)
+	out, okOut := reply.(*wasmtypes.QuerySmartContractStateResponse)
+	if method != SmartContractStateMethod || !okIn || !okOut {
+		return status.Errorf(codes.Unimplemented, "%s is not replayed", method)
+	}
+
+	query := Interaction{Address: in.Address, Query: json.RawMessage(in.QueryData)}
+	query.Height, _ = height.FromOutgoingContext(ctx)
+	interaction, ok := r.interactions[query.key()]
+	if !ok {
+		return status.Errorf(codes.Unavailable, "no recorded response to the query %s of %s", in.QueryData, in.Address)
+	}
+
+	if interaction.ResponseHeight != 0 {
+		setHeader(opts, metadata.Pairs(height.MetadataKey, strconv.FormatInt(interaction.ResponseHeight, 10)))
+	}
+	if interaction.Error != nil {
+		return status.Error(interaction.Error.Code, interaction.Error.Message)
+	}
+	out.Data = append([]byte(nil), interaction.Response...)
+
+	return nil
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (r *Replayer) NewStream(
+	_ context.Context, _ *grpc.StreamDesc, method string, _ ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, status.Errorf(codes.Unimplemented, "%s is not replayed", method)
+}
+
+// setHeader fills the header requested through the call options with the given one.
+func setHeader(opts []grpc.CallOption, header metadata.MD) {
+	for _, opt := range opts {
+		if o, ok := opt.(grpc.HeaderCallOption); ok {
+			*o.HeaderAddr = header.Copy()
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package recording
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/height"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	Convey("Given a recorder writing to a fixture file", t, func() {
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		path := filepath.Join(t.TempDir(), "fixture.json")
+		next := mocks.NewMockClientConnInterface(ctrl)
+		recorder, err := NewRecorder(next, path)
+		So(err, ShouldBeNil)
+
+		query := func(cc grpc.ClientConnInterface, ctx context.Context, address, data string) (string, int64, error) {
+			var header metadata.MD
+			out := &wasmtypes.QuerySmartContractStateResponse{}
+			err := cc.Invoke(ctx, SmartContractStateMethod,
+				&wasmtypes.QuerySmartContractStateRequest{Address: address, QueryData: []byte(data)}, out,
+				grpc.Header(&header))
+			h, _ := height.FromMetadata(header)
+			return string(out.Data), h, err
+		}
+
+		Convey("When querying contracts through it", func() {
+			expectQuery(next, "addr1", `{"q":1}`, `{"r":1}`, nil)
+			expectQuery(next, "addr1", `{"q":1}`, `{"r":0}`, nil)
+			expectQuery(next, "addr2", `{"q":2}`, "", status.Error(codes.NotFound, "no such contract"))
+
+			_, _, err1 := query(recorder, context.Background(), "addr1", `{"q":1}`)
+			_, _, err2 := query(recorder, height.WithHeight(context.Background(), 10), "addr1", `{"q":1}`)
+			_, _, err3 := query(recorder, context.Background(), "addr2", `{"q":2}`)
+
+			Convey("Then nothing should be written until the recorder is closed", func() {
+				_, err := os.Stat(path)
+				So(err, ShouldWrap, os.ErrNotExist)
+			})
+
+			So(recorder.Close(), ShouldBeNil)
+
+			Convey("Then the outcome of the queries should be passed through, and recorded", func() {
+				So(err1, ShouldBeNil)
+				So(err2, ShouldBeNil)
+				So(err3, ShouldBeError, "rpc error: code = NotFound desc = no such contract")
+
+				bz, err := os.ReadFile(path)
+				So(err, ShouldBeNil)
+				So(string(bz), ShouldEqual, `{
+  "interactions": [
+    {
+      "address": "addr1",
+      "query": {
+        "q": 1
+      },
+      "response": {
+        "r": 1
+      },
+      "responseHeight": 42
+    },
+    {
+      "address": "addr1",
+      "query": {
+        "q": 1
+      },
+      "height": 10,
+      "response": {
+        "r": 0
+      },
+      "responseHeight": 10
+    },
+    {
+      "address": "addr2",
+      "query": {
+        "q": 2
+      },
+      "error": {
+        "code": 5,
+        "message": "no such contract"
+      }
+    }
+  ]
+}
+`)
+			})
+
+			Convey("And when replaying the fixture file", func() {
+				fixture, err := Load(path)
+				So(err, ShouldBeNil)
+				replayer := NewReplayer(fixture)
+
+				Convey("Then the recorded queries should be answered as recorded, whatever their formatting", func() {
+					data, h, err := query(replayer, context.Background(), "addr1", `{ "q": 1 }`)
+					So(err, ShouldBeNil)
+					So(data, ShouldEqual, `{"r":1}`)
+					So(h, ShouldEqual, 42)
+
+					data, h, err = query(replayer, height.WithHeight(context.Background(), 10), "addr1", `{"q":1}`)
+					So(err, ShouldBeNil)
+					So(data, ShouldEqual, `{"r":0}`)
+					So(h, ShouldEqual, 10)
+
+					_, _, err = query(replayer, context.Background(), "addr2", `{"q":2}`)
+					So(err, ShouldBeError, "rpc error: code = NotFound desc = no such contract")
+				})
+
+				Convey("Then the other queries should fail as if the node was unavailable", func() {
+					_, _, err := query(replayer, height.WithHeight(context.Background(), 11), "addr1", `{"q":1}`)
+					So(status.Code(err), ShouldEqual, codes.Unavailable)
+					So(err, ShouldBeError,
+						`rpc error: code = Unavailable desc = no recorded response to the query {"q":1} of addr1`)
+				})
+
+				Convey("Then the other methods should be unimplemented", func() {
+					err := replayer.Invoke(context.Background(), "/cosmos.base.tendermint.v1beta1.Service/GetLatestBlock",
+						nil, nil)
+					So(status.Code(err), ShouldEqual, codes.Unimplemented)
+				})
+			})
+
+			Convey("And when recording again to the same file", func() {
+				next := mocks.NewMockClientConnInterface(ctrl)
+				expectQuery(next, "addr2", `{"q":2}`, `{"r":2}`, nil)
+				recorder, err := NewRecorder(next, path)
+				So(err, ShouldBeNil)
+				_, _, err = query(recorder, context.Background(), "addr2", `{"q":2}`)
+				So(err, ShouldBeNil)
+				So(recorder.Close(), ShouldBeNil)
+
+				Convey("Then the query made again should replace its previous recording", func() {
+					fixture, err := Load(path)
+					So(err, ShouldBeNil)
+					So(fixture.Interactions, ShouldHaveLength, 3)
+					So(string(fixture.Interactions[2].Response), ShouldEqual, `{"r":2}`)
+					So(fixture.Interactions[2].Error, ShouldBeNil)
+				})
+			})
+		})
+	})
+
+	Convey("Given an invalid fixture file", t, func() {
+		path := filepath.Join(t.TempDir(), "fixture.json")
+		So(os.WriteFile(path, []byte("interactions: []"), 0o600), ShouldBeNil)
+
+		Convey("When recording to it", func() {
+			_, err := NewRecorder(nil, path)
+
+			Convey("Then it should fail", func() {
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldStartWith, "invalid fixture "+path)
+			})
+		})
+	})
+}
+
+func expectQuery(next *mocks.MockClientConnInterface, address, queryData, respData string, err error) {
+	next.EXPECT().
+		Invoke(gomock.Any(), SmartContractStateMethod,
+			&wasmtypes.QuerySmartContractStateRequest{Address: address, QueryData: []byte(queryData)},
+			gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _, reply any, opts ...grpc.CallOption) error {
+			if err != nil {
+				return err
+			}
+			reply.(*wasmtypes.QuerySmartContractStateResponse).Data = []byte(respData)
+			h, ok := height.FromOutgoingContext(ctx)
+			if !ok {
+				h = 42
+			}
+			setHeader(opts, metadata.Pairs(height.MetadataKey, strconv.FormatInt(h, 10)))
+			return nil
+		}).Times(1)
+}
@@ -0,0 +1,333 @@
+package search
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/axone-protocol/axone-mcp/internal/axone/dataverse"
+	"github.com/axone-protocol/axone-mcp/internal/ontology"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+)
+
+// ErrTooManyDataverses is returned when indexing a new dataverse while the index holds as many as it can.
+var ErrTooManyDataverses = errors.New("too many indexed dataverses")
+
+// DefaultMaxDataverses is the default maximum number of dataverses an index holds.
+const DefaultMaxDataverses = 16
+
+// fieldWeights are the weights of the terms occurring in the claims of the given property, 1 if not listed.
+var fieldWeights = map[string]float64{
+	"hasTitle": 3,
+	"hasTag":   2,
+}
+
+// snippetLength is the maximum length, in runes, of the snippets of the hits.
+const snippetLength = 160
+
+// Hit is a resource matching a search.
+type Hit struct {
+	Resource string  `json:"resource"`
+	Score    float64 `json:"score"`
+	Title    string  `json:"title,omitempty"`
+	Snippet  string  `json:"snippet,omitempty"`
+}
+
+// Result is the answer to a search, best hits first.
+type Result struct {
+	Total       int       `json:"total"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+	Hits        []Hit     `json:"hits"`
+}
+
+// Index is a full-text index of the literal claims made about the resources of dataverses, pulled from their
+// triplestore.
+type Index struct {
+	cc            grpc.ClientConnInterface
+	store         Store
+	pageSize      int
+	maxDataverses int
+	now           func() time.Time
+
+	// mu serializes the refreshes.
+	mu sync.Mutex
+}
+
+// Option configures an Index.
+type Option func(*Index)
+
+// WithPageSize sets the number of solutions asked per query to the triplestores.
+func WithPageSize(n int) Option {
+	return func(i *Index) {
+		i.pageSize = n
+	}
+}
+
+// WithMaxDataverses sets the maximum number of dataverses indexed, so that callers cannot have the index grow without
+// bound by searching arbitrary dataverses.
+func WithMaxDataverses(n int) Option {
+	return func(i *Index) {
+		i.maxDataverses = n
+	}
+}
+
+// WithClock sets the clock giving the refresh time of the indexes.
+func WithClock(now func() time.Time) Option {
+	return func(i *Index) {
+		i.now = now
+	}
+}
+
+// New creates an index pulling the claims through the given connection, and storing them in the given store.
+func New(cc grpc.ClientConnInterface, store Store, opts ...Option) *Index {
+	i := &Index{
+		cc:            cc,
+		store:         store,
+		pageSize:      cognitarium.DefaultPageSize,
+		maxDataverses: DefaultMaxDataverses,
+		now:           time.Now,
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Refresh indexes again all the claims of the triplestore of the dataverse, failing with ErrTooManyDataverses if it
+// is not indexed yet and the index is full.
+func (i *Index) Refresh(ctx context.Context, address string) (*Stats, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	dataverses, err := i.store.Dataverses()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(dataverses, address) && len(dataverses) >= i.maxDataverses {
+		return nil, fmt.Errorf("%w: %d dataverses are indexed already", ErrTooManyDataverses, len(dataverses))
+	}
+
+	documents, err := Harvest(ctx, i.cc, address, i.pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := buildSnapshot(documents)
+	snapshot.Stats.RefreshedAt = i.now().UTC()
+	if err := i.store.Replace(address, snapshot); err != nil {
+		return nil, err
+	}
+	log.Logger.Info().Str("dataverse", address).Int("documents", snapshot.Stats.Documents).Msg("search index refreshed")
+
+	return &snapshot.Stats, nil
+}
+
+// Close releases the store of the index, if it holds any resource.
+func (i *Index) Close() error {
+	if closer, ok := i.store.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Run refreshes the indexed dataverses at the given interval, until the context is done.
+func (i *Index) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			dataverses, err := i.store.Dataverses()
+			if err != nil {
+				log.Logger.Warn().Err(err).Msg("failed to list the indexed dataverses")
+				continue
+			}
+			for _, address := range dataverses {
+				if _, err := i.Refresh(ctx, address); err != nil {
+					log.Logger.Warn().Str("dataverse", address).Err(err).Msg("failed to refresh search index")
+				}
+			}
+		}
+	}
+}
+
+// Search returns the resources of the dataverse whose claims best match the words of the query, at most limit of them.
+// The dataverse is indexed first if it has never been.
+func (i *Index) Search(ctx context.Context, address, query string, limit int) (*Result, error) {
+	stats, err := i.store.Stats(address)
+	if err != nil {
+		return nil, err
+	}
+	if stats == nil {
+		if stats, err = i.Refresh(ctx, address); err != nil {
+			return nil, err
+		}
+	}
+
+	terms := slices.Compact(slices.Sorted(slices.Values(Tokenize(query))))
+	scores := map[string]float64{}
+	for _, term := range terms {
+		postings, err := i.store.Postings(address, term)
+		if err != nil {
+			return nil, err
+		}
+		n, df := float64(stats.Documents), float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for _, p := range postings {
+			scores[p.Doc] += idf * p.Weight / (p.Weight + 1)
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{Resource: id, Score: math.Round(score*1000) / 1000})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Resource, b.Resource))
+	})
+
+	result := &Result{Total: len(hits), RefreshedAt: stats.RefreshedAt, Hits: hits[:min(limit, len(hits))]}
+	for n := range result.Hits {
+		doc, err := i.store.Document(address, result.Hits[n].Resource)
+		if err != nil {
+			return nil, err
+		}
+		if doc != nil {
+			result.Hits[n].Title = lo.FirstOrEmpty(doc.Claims["hasTitle"])
+			result.Hits[n].Snippet = snippet(doc, terms)
+		}
+	}
+
+	return result, nil
+}
+
+// Harvest returns the resources of the dataverse, each with the literal values claimed about it in the triplestore of the
+// dataverse, sorted by ID. It fails with dataverse.ErrNoTriplestore if the dataverse has no triplestore.
+func Harvest(ctx context.Context, cc grpc.ClientConnInterface, address string, pageSize int) ([]Document, error) {
+	triplestore, err := dataverse.Triplestore(ctx, cc, address)
+	if err != nil {
+		return nil, err
+	}
+
+	literals, err := cognitarium.GetClaimLiterals(ctx, cc, triplestore, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := map[string]*Document{}
+	for _, l := range literals {
+		if l.Subject == "" || strings.TrimSpace(l.Value) == "" {
+			continue
+		}
+		doc, ok := documents[l.Subject]
+		if !ok {
+			doc = &Document{ID: l.Subject, Claims: map[string][]string{}}
+			documents[l.Subject] = doc
+		}
+		property := ontology.LocalName(l.Property)
+		if !slices.Contains(doc.Claims[property], l.Value) {
+			doc.Claims[property] = append(doc.Claims[property], l.Value)
+		}
+	}
+
+	out := make([]Document, 0, len(documents))
+	for _, id := range slices.Sorted(maps.Keys(documents)) {
+		out = append(out, *documents[id])
+	}
+
+	return out, nil
+}
+
+// buildSnapshot builds the index of the given documents.
+func buildSnapshot(documents []Document) *Snapshot {
+	snapshot := &Snapshot{Documents: documents, Postings: map[string][]Posting{}}
+	for _, doc := range documents {
+		weights := map[string]float64{}
+		for property, values := range doc.Claims {
+			weight := cmp.Or(fieldWeights[property], 1)
+			for _, v := range values {
+				for _, term := range Tokenize(v) {
+					weights[term] += weight
+				}
+			}
+		}
+		for term, w := range weights {
+			snapshot.Postings[term] = append(snapshot.Postings[term], Posting{Doc: doc.ID, Weight: w})
+		}
+	}
+	snapshot.Stats.Documents = len(documents)
+
+	return snapshot
+}
+
+// snippet returns the excerpt of the claims of the document matching the most terms, around the first match.
+func snippet(doc *Document, terms []string) string {
+	var best string
+	bestMatches := 0
+	for _, property := range slices.Sorted(maps.Keys(doc.Claims)) {
+		if property == "hasTitle" {
+			continue
+		}
+		for _, v := range doc.Claims[property] {
+			tokens := Tokenize(v)
+			matches := 0
+			for _, t := range terms {
+				if slices.Contains(tokens, t) {
+					matches++
+				}
+			}
+			if matches > bestMatches || best == "" {
+				best, bestMatches = v, matches
+			}
+		}
+	}
+
+	return excerpt(best, terms)
+}
+
+// excerpt returns at most snippetLength runes of the text, starting shortly before the first word matching a term.
+func excerpt(text string, terms []string) string {
+	runes := []rune(text)
+	if len(runes) <= snippetLength {
+		return text
+	}
+
+	start := 0
+	words := strings.Fields(text)
+	offset := 0
+	for _, w := range words {
+		pos := strings.Index(text[offset:], w) + offset
+		offset = pos + len(w)
+		if tokens := Tokenize(w); len(tokens) > 0 && slices.Contains(terms, tokens[0]) {
+			start = max(0, len([]rune(text[:pos]))-snippetLength/4)
+			break
+		}
+	}
+	end := min(len(runes), start+snippetLength)
+
+	out := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		out = "…" + out
+	}
+	if end < len(runes) {
+		out += "…"
+	}
+
+	return out
+}
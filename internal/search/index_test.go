@@ -0,0 +1,203 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/axone/dataverse"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+)
+
+const datasetNS = "https://w3id.org/axone/ontology/v4/schema/credential/dataset/description/"
+
+type row struct {
+	credential, subject, property, value string
+}
+
+// node is a fake axone node holding a dataverse and its triplestore, answering the select queries in credential order,
+// and recording the windows of credentials they asked for.
+type node struct {
+	triplestore string
+	rows        []row
+	queries     []string
+}
+
+func (n *node) Invoke(_ context.Context, _ string, args, reply any, _ ...grpc.CallOption) error {
+	req := args.(*wasmtypes.QuerySmartContractStateRequest)
+	res := reply.(*wasmtypes.QuerySmartContractStateResponse)
+
+	var query struct {
+		Dataverse *struct{} `json:"dataverse"`
+		Select    *struct {
+			Query struct {
+				Limit int `json:"limit"`
+				Where struct {
+					Filter *struct {
+						Expr struct {
+							And []map[string][]struct {
+								NamedNode struct {
+									Full string `json:"full"`
+								} `json:"named_node"`
+							} `json:"and"`
+						} `json:"expr"`
+					} `json:"filter"`
+				} `json:"where"`
+			} `json:"query"`
+		} `json:"select"`
+	}
+	if err := json.Unmarshal(req.QueryData, &query); err != nil {
+		return err
+	}
+
+	switch {
+	case req.Address == "dv" && query.Dataverse != nil:
+		res.Data = fmt.Appendf(nil, `{"name":"dv","triplestore_address":%q}`, n.triplestore)
+	case req.Address == "ts" && query.Select != nil:
+		var after, upTo string
+		if f := query.Select.Query.Where.Filter; f != nil {
+			for _, bound := range f.Expr.And {
+				if operands, ok := bound["greater"]; ok {
+					after = operands[1].NamedNode.Full
+				}
+				if operands, ok := bound["less_or_equal"]; ok {
+					upTo = operands[1].NamedNode.Full
+				}
+			}
+		}
+		n.queries = append(n.queries, fmt.Sprintf("(%s, %s]", after, upTo))
+
+		bindings := make([]string, 0)
+		for _, r := range n.rows {
+			inWindow := r.credential > after && (upTo == "" || r.credential <= upTo)
+			if inWindow && len(bindings) < query.Select.Query.Limit {
+				bindings = append(bindings, fmt.Sprintf(
+					`{"credential":{"type":"uri","value":{"full":%q}},"subject":{"type":"uri","value":{"full":%q}},`+
+						`"property":{"type":"uri","value":{"full":%q}},"value":{"type":"literal","value":%q}}`,
+					r.credential, r.subject, r.property, r.value))
+			}
+		}
+		res.Data = fmt.Appendf(nil, `{"head":{"vars":["credential","subject","property","value"]},"results":{"bindings":[%s]}}`,
+			strings.Join(bindings, ","))
+	default:
+		return errors.New("unexpected query")
+	}
+
+	return nil
+}
+
+func (n *node) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestIndex(t *testing.T) {
+	Convey("Given an index of a dataverse whose triplestore holds dataset descriptions", t, func() {
+		n := &node{
+			triplestore: "ts",
+			rows: []row{
+				{"cred:1", "did:key:a", datasetNS + "hasTitle", "Air quality in France"},
+				{"cred:1", "did:key:a", datasetNS + "hasDescription", "Hourly measurements of the pollutants in the air."},
+				{"cred:1", "did:key:a", datasetNS + "hasTag", "pollution"},
+				{"cred:2", "did:key:b", datasetNS + "hasTitle", "Water quality"},
+				{"cred:2", "did:key:b", datasetNS + "hasDescription", "Samples of the rivers of France."},
+				{"cred:3", "did:key:c", datasetNS + "hasTitle", "Road traffic"},
+				{"cred:3", "did:key:c", datasetNS + "hasDescription", "Counts of the vehicles, including the air traffic."},
+			},
+		}
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		idx := New(n, NewMemoryStore(), WithPageSize(4), WithClock(func() time.Time { return now }))
+
+		Convey("When searching it for the first time", func() {
+			result, err := idx.Search(context.Background(), "dv", "air quality", 10)
+
+			Convey("Then the claims should be pulled by windows of credentials, narrowed until answered in full", func() {
+				So(err, ShouldBeNil)
+				So(n.queries, ShouldResemble, []string{
+					"(, ]", "(, cred:1]", "(cred:1, ]", "(cred:1, cred:2]", "(cred:2, ]",
+				})
+			})
+
+			Convey("Then the resources should be ranked by relevance", func() {
+				So(err, ShouldBeNil)
+				So(result.Total, ShouldEqual, 3)
+				So(result.RefreshedAt, ShouldEqual, now)
+				resources := make([]string, 0, len(result.Hits))
+				for _, h := range result.Hits {
+					resources = append(resources, h.Resource)
+				}
+				So(resources, ShouldResemble, []string{"did:key:a", "did:key:b", "did:key:c"})
+				So(result.Hits[0].Title, ShouldEqual, "Air quality in France")
+				So(result.Hits[0].Snippet, ShouldEqual, "Hourly measurements of the pollutants in the air.")
+				So(result.Hits[0].Score, ShouldBeGreaterThan, result.Hits[1].Score)
+			})
+		})
+
+		Convey("When searching it again", func() {
+			_, err := idx.Search(context.Background(), "dv", "air", 10)
+			So(err, ShouldBeNil)
+			result, err := idx.Search(context.Background(), "dv", "rivers", 1)
+
+			Convey("Then the index should be reused", func() {
+				So(err, ShouldBeNil)
+				So(n.queries, ShouldHaveLength, 5)
+				So(result.Total, ShouldEqual, 1)
+				So(result.Hits[0].Resource, ShouldEqual, "did:key:b")
+			})
+		})
+
+		Convey("When searching with a limit", func() {
+			result, err := idx.Search(context.Background(), "dv", "france", 1)
+
+			Convey("Then only the best hits should be returned, along with their total", func() {
+				So(err, ShouldBeNil)
+				So(result.Total, ShouldEqual, 2)
+				So(result.Hits, ShouldHaveLength, 1)
+			})
+		})
+
+		Convey("When searching more dataverses than the index can hold", func() {
+			idx := New(n, NewMemoryStore(), WithPageSize(4), WithMaxDataverses(1))
+			_, errFirst := idx.Search(context.Background(), "dv", "air", 10)
+			_, errOther := idx.Search(context.Background(), "other", "air", 10)
+			_, errAgain := idx.Refresh(context.Background(), "dv")
+
+			Convey("Then the dataverses beyond the limit should be refused, the indexed ones being kept refreshed", func() {
+				So(errFirst, ShouldBeNil)
+				So(errors.Is(errOther, ErrTooManyDataverses), ShouldBeTrue)
+				So(errAgain, ShouldBeNil)
+			})
+		})
+
+		Convey("When the dataverse has no triplestore", func() {
+			n.triplestore = ""
+			_, err := idx.Search(context.Background(), "dv", "air", 10)
+
+			Convey("Then an error should be returned", func() {
+				So(errors.Is(err, dataverse.ErrNoTriplestore), ShouldBeTrue)
+			})
+		})
+	})
+}
+
+func TestExcerpt(t *testing.T) {
+	Convey("Given a long text", t, func() {
+		text := strings.Repeat("lorem ipsum ", 20) + "the air quality " + strings.Repeat("dolor sit amet ", 20)
+
+		Convey("When excerpting it around a term", func() {
+			out := excerpt(text, []string{"air"})
+
+			Convey("Then the excerpt should surround the first match", func() {
+				So(out, ShouldStartWith, "…")
+				So(out, ShouldEndWith, "…")
+				So(out, ShouldContainSubstring, "the air quality")
+				So(len([]rune(out)), ShouldBeLessThanOrEqualTo, snippetLength+2)
+			})
+		})
+	})
+}
@@ -0,0 +1,266 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+)
+
+// Document is a resource of a dataverse, along with the literal values claimed about it, by property local name.
+type Document struct {
+	ID     string              `json:"id"`
+	Claims map[string][]string `json:"claims"`
+}
+
+// Posting is the occurrence of a term in a document, weighted by the fields it occurs in.
+type Posting struct {
+	Doc    string  `json:"d"`
+	Weight float64 `json:"w"`
+}
+
+// Stats describes the index of a dataverse.
+type Stats struct {
+	Documents   int       `json:"documents"`
+	RefreshedAt time.Time `json:"refreshedAt"`
+}
+
+// Snapshot is the whole index of a dataverse.
+type Snapshot struct {
+	Stats     Stats
+	Documents []Document
+	// Postings are the postings of each term.
+	Postings map[string][]Posting
+}
+
+// Store holds the inverted indexes of the dataverses.
+type Store interface {
+	// Replace replaces the index of the dataverse with the given snapshot.
+	Replace(dataverse string, snapshot *Snapshot) error
+	// Stats returns the stats of the index of the dataverse, nil if it has never been indexed.
+	Stats(dataverse string) (*Stats, error)
+	// Postings returns the postings of the term in the index of the dataverse.
+	Postings(dataverse, term string) ([]Posting, error)
+	// Document returns the indexed document of the given ID, nil if unknown.
+	Document(dataverse, id string) (*Document, error)
+	// Dataverses returns the addresses of the indexed dataverses.
+	Dataverses() ([]string, error)
+}
+
+// MemoryStore is a Store keeping the indexes in memory.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	snapshots map[string]*memorySnapshot
+}
+
+type memorySnapshot struct {
+	*Snapshot
+	documents map[string]*Document
+}
+
+// NewMemoryStore creates an empty in-memory index store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{snapshots: make(map[string]*memorySnapshot)}
+}
+
+// Replace implements Store.
+func (s *MemoryStore) Replace(dataverse string, snapshot *Snapshot) error {
+	documents := make(map[string]*Document, len(snapshot.Documents))
+	for i := range snapshot.Documents {
+		documents[snapshot.Documents[i].ID] = &snapshot.Documents[i]
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.snapshots[dataverse] = &memorySnapshot{Snapshot: snapshot, documents: documents}
+
+	return nil
+}
+
+// Stats implements Store.
+func (s *MemoryStore) Stats(dataverse string) (*Stats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if snapshot, ok := s.snapshots[dataverse]; ok {
+		stats := snapshot.Stats
+		return &stats, nil
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// Postings implements Store.
+func (s *MemoryStore) Postings(dataverse, term string) ([]Posting, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if snapshot, ok := s.snapshots[dataverse]; ok {
+		return snapshot.Postings[term], nil
+	}
+
+	return nil, nil
+}
+
+// Document implements Store.
+func (s *MemoryStore) Document(dataverse, id string) (*Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if snapshot, ok := s.snapshots[dataverse]; ok {
+		return snapshot.documents[id], nil
+	}
+
+	return nil, nil
+}
+
+// Dataverses implements Store.
+func (s *MemoryStore) Dataverses() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dataverses := make([]string, 0, len(s.snapshots))
+	for dataverse := range s.snapshots {
+		dataverses = append(dataverses, dataverse)
+	}
+	slices.Sort(dataverses)
+
+	return dataverses, nil
+}
+
+var (
+	statsKey        = []byte("stats")
+	postingsBucket  = []byte("postings")
+	documentsBucket = []byte("documents")
+)
+
+// BoltStore is a Store persisting the indexes in a bbolt file, one bucket per dataverse, so that they survive restarts.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// OpenBoltStore opens, or creates, the bbolt file at the given path.
+func OpenBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// Replace implements Store.
+func (s *BoltStore) Replace(dataverse string, snapshot *Snapshot) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket([]byte(dataverse)); err != nil && !errors.Is(err, bolterrors.ErrBucketNotFound) {
+			return err
+		}
+		b, err := tx.CreateBucket([]byte(dataverse))
+		if err != nil {
+			return err
+		}
+
+		if err := putJSON(b, statsKey, snapshot.Stats); err != nil {
+			return err
+		}
+		postings, err := b.CreateBucket(postingsBucket)
+		if err != nil {
+			return err
+		}
+		for term, p := range snapshot.Postings {
+			if err := putJSON(postings, []byte(term), p); err != nil {
+				return err
+			}
+		}
+		documents, err := b.CreateBucket(documentsBucket)
+		if err != nil {
+			return err
+		}
+		for _, d := range snapshot.Documents {
+			if err := putJSON(documents, []byte(d.ID), d); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Stats implements Store.
+func (s *BoltStore) Stats(dataverse string) (*Stats, error) {
+	var stats *Stats
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if b := tx.Bucket([]byte(dataverse)); b != nil {
+			stats = &Stats{}
+			return json.Unmarshal(b.Get(statsKey), stats)
+		}
+		return nil
+	})
+
+	return stats, err
+}
+
+// Postings implements Store.
+func (s *BoltStore) Postings(dataverse, term string) ([]Posting, error) {
+	var postings []Posting
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx, dataverse, postingsBucket, term, &postings)
+	})
+
+	return postings, err
+}
+
+// Document implements Store.
+func (s *BoltStore) Document(dataverse, id string) (*Document, error) {
+	var doc *Document
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx, dataverse, documentsBucket, id, &doc)
+	})
+
+	return doc, err
+}
+
+// Dataverses implements Store.
+func (s *BoltStore) Dataverses() ([]string, error) {
+	var dataverses []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, _ *bolt.Bucket) error {
+			dataverses = append(dataverses, string(name))
+			return nil
+		})
+	})
+
+	return dataverses, err
+}
+
+// Close releases the bbolt file.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+func putJSON(b *bolt.Bucket, key []byte, v any) error {
+	bz, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return b.Put(key, bz)
+}
+
+// getJSON decodes into v the value of the key in the given bucket of the dataverse, leaving v untouched if absent.
+func getJSON(tx *bolt.Tx, dataverse string, bucket []byte, key string, v any) error {
+	b := tx.Bucket([]byte(dataverse))
+	if b == nil {
+		return nil
+	}
+	bz := b.Bucket(bucket).Get([]byte(key))
+	if bz == nil {
+		return nil
+	}
+
+	return json.Unmarshal(bz, v)
+}
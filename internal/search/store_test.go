@@ -0,0 +1,95 @@
+package search
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestStores(t *testing.T) {
+	refreshedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshot := &Snapshot{
+		Stats: Stats{Documents: 2, RefreshedAt: refreshedAt},
+		Documents: []Document{
+			{ID: "did:key:a", Claims: map[string][]string{"hasTitle": {"Air quality"}}},
+			{ID: "did:key:b", Claims: map[string][]string{"hasTitle": {"Water quality"}}},
+		},
+		Postings: map[string][]Posting{
+			"air":     {{Doc: "did:key:a", Weight: 3}},
+			"quality": {{Doc: "did:key:a", Weight: 3}, {Doc: "did:key:b", Weight: 3}},
+			"water":   {{Doc: "did:key:b", Weight: 3}},
+		},
+	}
+
+	for name, open := range map[string]func(t *testing.T) Store{
+		"memory": func(_ *testing.T) Store { return NewMemoryStore() },
+		"bbolt": func(t *testing.T) Store {
+			s, err := OpenBoltStore(filepath.Join(t.TempDir(), "search.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { _ = s.Close() })
+			return s
+		},
+	} {
+		Convey("Given an empty "+name+" store", t, func() {
+			s := open(t)
+
+			Convey("Then no dataverse should be indexed", func() {
+				stats, err := s.Stats("dv1")
+				So(err, ShouldBeNil)
+				So(stats, ShouldBeNil)
+				postings, err := s.Postings("dv1", "air")
+				So(err, ShouldBeNil)
+				So(postings, ShouldBeEmpty)
+				doc, err := s.Document("dv1", "did:key:a")
+				So(err, ShouldBeNil)
+				So(doc, ShouldBeNil)
+			})
+
+			Convey("When a snapshot is stored", func() {
+				So(s.Replace("dv1", snapshot), ShouldBeNil)
+
+				Convey("Then its content should be returned", func() {
+					stats, err := s.Stats("dv1")
+					So(err, ShouldBeNil)
+					So(*stats, ShouldResemble, Stats{Documents: 2, RefreshedAt: refreshedAt})
+
+					postings, err := s.Postings("dv1", "quality")
+					So(err, ShouldBeNil)
+					So(postings, ShouldResemble, snapshot.Postings["quality"])
+
+					doc, err := s.Document("dv1", "did:key:b")
+					So(err, ShouldBeNil)
+					So(*doc, ShouldResemble, snapshot.Documents[1])
+
+					dataverses, err := s.Dataverses()
+					So(err, ShouldBeNil)
+					So(dataverses, ShouldResemble, []string{"dv1"})
+				})
+
+				Convey("And replaced by another one", func() {
+					So(s.Replace("dv1", &Snapshot{
+						Stats:     Stats{Documents: 1, RefreshedAt: refreshedAt.Add(time.Hour)},
+						Documents: []Document{{ID: "did:key:c", Claims: map[string][]string{"hasTitle": {"Traffic"}}}},
+						Postings:  map[string][]Posting{"traffic": {{Doc: "did:key:c", Weight: 3}}},
+					}), ShouldBeNil)
+
+					Convey("Then the previous content should be gone", func() {
+						postings, err := s.Postings("dv1", "quality")
+						So(err, ShouldBeNil)
+						So(postings, ShouldBeEmpty)
+						doc, err := s.Document("dv1", "did:key:a")
+						So(err, ShouldBeNil)
+						So(doc, ShouldBeNil)
+						stats, err := s.Stats("dv1")
+						So(err, ShouldBeNil)
+						So(stats.Documents, ShouldEqual, 1)
+					})
+				})
+			})
+		})
+	}
+}
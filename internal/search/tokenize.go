@@ -0,0 +1,54 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// stopwords are the English and French words too common to be meaningful in a query.
+var stopwords = map[string]struct{}{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		a about an and are as at be by for from has have in into is it its of on or that the their this to was were
+		which with
+		au aux avec ce ces dans de des du en est et il la le les leur mais ou par pour que qui sa se ses son sur un une
+	`) {
+		stopwords[w] = struct{}{}
+	}
+}
+
+// Tokenize splits the text into its index terms: lower-cased words without diacritics nor plural mark, stop words
+// excluded.
+func Tokenize(text string) []string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), text)
+	if err != nil {
+		folded = text
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(words))
+	for _, w := range words {
+		if _, ok := stopwords[w]; ok || len(w) < 2 {
+			continue
+		}
+		tokens = append(tokens, stem(w))
+	}
+
+	return tokens
+}
+
+// stem strips the plural mark of the word, so that singular and plural forms match.
+func stem(w string) string {
+	if len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+		return w[:len(w)-1]
+	}
+
+	return w
+}
@@ -0,0 +1,34 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTokenize(t *testing.T) {
+	Convey("Given texts to tokenize", t, func() {
+		cases := []struct {
+			text string
+			want []string
+		}{
+			{text: "", want: []string{}},
+			{text: "Air Quality", want: []string{"air", "quality"}},
+			{text: "The measurements of the rivers", want: []string{"measurement", "river"}},
+			{text: "Les données de la qualité de l'air", want: []string{"donnee", "qualite", "air"}},
+			{text: "CO2 emissions, 2024-2025", want: []string{"co2", "emission", "2024", "2025"}},
+			{text: "access class", want: []string{"access", "class"}},
+		}
+
+		for _, tc := range cases {
+			Convey(fmt.Sprintf("When tokenizing %q", tc.text), func() {
+				tokens := Tokenize(tc.text)
+
+				Convey("Then the expected terms should be returned", func() {
+					So(tokens, ShouldResemble, tc.want)
+				})
+			})
+		}
+	})
+}
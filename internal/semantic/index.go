@@ -0,0 +1,268 @@
+package semantic
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/axone-protocol/axone-mcp/internal/axone/cognitarium"
+	"github.com/axone-protocol/axone-mcp/internal/embedding"
+	"github.com/axone-protocol/axone-mcp/internal/search"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+	"google.golang.org/grpc"
+)
+
+const (
+	// DefaultBatchSize is the default number of texts embedded per request to the embedder.
+	DefaultBatchSize = 64
+	// DefaultMinRefreshInterval is the default minimum time between two refreshes of a dataverse.
+	DefaultMinRefreshInterval = time.Minute
+)
+
+// describedProperties are the claims making up the description of a resource, in order, before the other ones.
+var describedProperties = []string{"hasTitle", "hasDescription", "hasTag", "hasTopic"}
+
+// Hit is a resource close to a query.
+type Hit struct {
+	Resource    string  `json:"resource"`
+	Score       float64 `json:"score"`
+	Title       string  `json:"title,omitempty"`
+	Description string  `json:"description,omitempty"`
+}
+
+// Result is the answer to a search, closest resources first.
+type Result struct {
+	RefreshedAt time.Time `json:"refreshedAt"`
+	Hits        []Hit     `json:"hits"`
+}
+
+type entry struct {
+	hit    Hit
+	vector []float32
+}
+
+type snapshot struct {
+	refreshedAt time.Time
+	entries     []entry
+}
+
+// Index is an in-memory vector index of the descriptions of the resources of dataverses, pulled from their
+// triplestore.
+type Index struct {
+	cc        grpc.ClientConnInterface
+	embedder  embedding.Embedder
+	pageSize  int
+	batchSize int
+	// maxDataverses is the maximum number of dataverses indexed.
+	maxDataverses int
+	// minRefreshInterval is the minimum time between two refreshes of a dataverse.
+	minRefreshInterval time.Duration
+	now                func() time.Time
+
+	// refreshMu serializes the refreshes.
+	refreshMu sync.Mutex
+	mu        sync.RWMutex
+	snapshots map[string]*snapshot
+}
+
+// Option configures an Index.
+type Option func(*Index)
+
+// WithPageSize sets the number of solutions asked per query to the triplestores.
+func WithPageSize(n int) Option {
+	return func(i *Index) {
+		i.pageSize = n
+	}
+}
+
+// WithBatchSize sets the number of texts embedded per request to the embedder.
+func WithBatchSize(n int) Option {
+	return func(i *Index) {
+		i.batchSize = n
+	}
+}
+
+// WithMaxDataverses sets the maximum number of dataverses indexed, so that callers cannot have the descriptions of
+// arbitrary dataverses embedded without bound.
+func WithMaxDataverses(n int) Option {
+	return func(i *Index) {
+		i.maxDataverses = n
+	}
+}
+
+// WithMinRefreshInterval sets the minimum time between two refreshes of a dataverse, the ones asked sooner being
+// skipped so that callers cannot have its descriptions embedded again and again.
+func WithMinRefreshInterval(d time.Duration) Option {
+	return func(i *Index) {
+		i.minRefreshInterval = d
+	}
+}
+
+// WithClock sets the clock giving the refresh time of the indexes.
+func WithClock(now func() time.Time) Option {
+	return func(i *Index) {
+		i.now = now
+	}
+}
+
+// New creates an index pulling the descriptions through the given connection, and embedding them with the given
+// embedder.
+func New(cc grpc.ClientConnInterface, embedder embedding.Embedder, opts ...Option) *Index {
+	i := &Index{
+		cc:                 cc,
+		embedder:           embedder,
+		pageSize:           cognitarium.DefaultPageSize,
+		batchSize:          DefaultBatchSize,
+		maxDataverses:      search.DefaultMaxDataverses,
+		minRefreshInterval: DefaultMinRefreshInterval,
+		now:                time.Now,
+		snapshots:          make(map[string]*snapshot),
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Refresh embeds again the descriptions of all the resources of the dataverse, unless refreshed less than the minimum
+// refresh interval ago. It fails with search.ErrTooManyDataverses if the dataverse is not indexed yet and the index is
+// full.
+func (i *Index) Refresh(ctx context.Context, address string) error {
+	i.refreshMu.Lock()
+	defer i.refreshMu.Unlock()
+
+	i.mu.RLock()
+	s, indexed := i.snapshots[address]
+	dataverses := len(i.snapshots)
+	i.mu.RUnlock()
+	switch {
+	case indexed && i.now().Sub(s.refreshedAt) < i.minRefreshInterval:
+		return nil
+	case !indexed && dataverses >= i.maxDataverses:
+		return fmt.Errorf("%w: %d dataverses are indexed already", search.ErrTooManyDataverses, dataverses)
+	}
+
+	documents, err := search.Harvest(ctx, i.cc, address, i.pageSize)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]entry, 0, len(documents))
+	texts := make([]string, 0, len(documents))
+	for _, doc := range documents {
+		if text := describe(doc); text != "" {
+			entries = append(entries, entry{hit: Hit{
+				Resource:    doc.ID,
+				Title:       lo.FirstOrEmpty(doc.Claims["hasTitle"]),
+				Description: lo.FirstOrEmpty(doc.Claims["hasDescription"]),
+			}})
+			texts = append(texts, text)
+		}
+	}
+	for start := 0; start < len(texts); start += i.batchSize {
+		end := min(start+i.batchSize, len(texts))
+		vectors, err := i.embedder.Embed(ctx, texts[start:end])
+		if err != nil {
+			return err
+		}
+		if len(vectors) != end-start {
+			return fmt.Errorf("%w: %d embeddings returned for %d texts", embedding.ErrUnavailable, len(vectors), end-start)
+		}
+		for n, v := range vectors {
+			entries[start+n].vector = v
+		}
+	}
+
+	i.mu.Lock()
+	i.snapshots[address] = &snapshot{refreshedAt: i.now().UTC(), entries: entries}
+	i.mu.Unlock()
+	log.Logger.Info().Str("dataverse", address).Int("documents", len(entries)).Msg("semantic index refreshed")
+
+	return nil
+}
+
+// Run refreshes the indexed dataverses at the given interval, until the context is done.
+func (i *Index) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			i.mu.RLock()
+			dataverses := make([]string, 0, len(i.snapshots))
+			for address := range i.snapshots {
+				dataverses = append(dataverses, address)
+			}
+			i.mu.RUnlock()
+
+			for _, address := range dataverses {
+				if err := i.Refresh(ctx, address); err != nil {
+					log.Logger.Warn().Str("dataverse", address).Err(err).Msg("failed to refresh semantic index")
+				}
+			}
+		}
+	}
+}
+
+// Search returns the k resources of the dataverse whose description is the closest to the query, by cosine
+// similarity. The dataverse is indexed first if it has never been.
+func (i *Index) Search(ctx context.Context, address, query string, k int) (*Result, error) {
+	i.mu.RLock()
+	s, ok := i.snapshots[address]
+	i.mu.RUnlock()
+	if !ok {
+		if err := i.Refresh(ctx, address); err != nil {
+			return nil, err
+		}
+		i.mu.RLock()
+		s = i.snapshots[address]
+		i.mu.RUnlock()
+	}
+
+	vectors, err := i.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("%w: %d embeddings returned for the query", embedding.ErrUnavailable, len(vectors))
+	}
+
+	hits := make([]Hit, 0, len(s.entries))
+	for _, e := range s.entries {
+		hit := e.hit
+		hit.Score = math.Round(embedding.Cosine(vectors[0], e.vector)*1000) / 1000
+		hits = append(hits, hit)
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		return cmp.Or(cmp.Compare(b.Score, a.Score), cmp.Compare(a.Resource, b.Resource))
+	})
+
+	return &Result{RefreshedAt: s.refreshedAt, Hits: hits[:min(k, len(hits))]}, nil
+}
+
+// describe returns the text describing the document: its title, description, tags and topics, then its other claims.
+func describe(doc search.Document) string {
+	var parts []string
+	for _, property := range describedProperties {
+		parts = append(parts, doc.Claims[property]...)
+	}
+	others := slices.DeleteFunc(slices.Sorted(maps.Keys(doc.Claims)), func(property string) bool {
+		return slices.Contains(describedProperties, property)
+	})
+	for _, property := range others {
+		parts = append(parts, doc.Claims[property]...)
+	}
+
+	return strings.Join(parts, ". ")
+}
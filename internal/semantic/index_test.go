@@ -0,0 +1,169 @@
+package semantic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/embedding"
+	"github.com/axone-protocol/axone-mcp/internal/search"
+	. "github.com/smartystreets/goconvey/convey"
+	"google.golang.org/grpc"
+)
+
+const datasetNS = "https://w3id.org/axone/ontology/v4/schema/credential/dataset/description/"
+
+// node is a fake axone node holding a dataverse and its triplestore, answering all the claims in a single page.
+type node struct {
+	claims  [][3]string
+	selects int
+}
+
+func (n *node) Invoke(_ context.Context, _ string, args, reply any, _ ...grpc.CallOption) error {
+	req := args.(*wasmtypes.QuerySmartContractStateRequest)
+	res := reply.(*wasmtypes.QuerySmartContractStateResponse)
+
+	switch req.Address {
+	case "dv":
+		res.Data = []byte(`{"name":"dv","triplestore_address":"ts"}`)
+	case "ts":
+		n.selects++
+		bindings := make([]string, 0, len(n.claims))
+		for i, c := range n.claims {
+			bindings = append(bindings, fmt.Sprintf(
+				`{"credential":{"type":"uri","value":{"full":"cred:%d"}},"subject":{"type":"uri","value":{"full":%q}},`+
+					`"property":{"type":"uri","value":{"full":%q}},"value":{"type":"literal","value":%q}}`,
+				i, c[0], datasetNS+c[1], c[2]))
+		}
+		res.Data = fmt.Appendf(nil, `{"head":{"vars":[]},"results":{"bindings":[%s]}}`, strings.Join(bindings, ","))
+	default:
+		return errors.New("unexpected query")
+	}
+
+	return nil
+}
+
+func (n *node) NewStream(context.Context, *grpc.StreamDesc, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+	return nil, errors.New("not supported")
+}
+
+// countingEmbedder is a hash embedder recording the size of the batches it is asked to embed.
+type countingEmbedder struct {
+	embedding.HashEmbedder
+	batches []int
+	err     error
+	// drop is the number of embeddings left out of each answer.
+	drop int
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	e.batches = append(e.batches, len(texts))
+	vectors, err := e.HashEmbedder.Embed(ctx, texts)
+	return vectors[:max(len(vectors)-e.drop, 0)], err
+}
+
+func TestIndex(t *testing.T) {
+	Convey("Given a vector index of a dataverse holding dataset descriptions", t, func() {
+		n := &node{claims: [][3]string{
+			{"did:key:a", "hasTitle", "Air quality in France"},
+			{"did:key:a", "hasDescription", "Hourly measurements of the pollutants in the air of French cities."},
+			{"did:key:b", "hasTitle", "Water quality"},
+			{"did:key:b", "hasDescription", "Samples of the rivers."},
+			{"did:key:c", "hasTitle", "Road traffic"},
+			{"did:key:c", "hasDescription", "Counts of the vehicles on the roads."},
+		}}
+		embedder := &countingEmbedder{HashEmbedder: embedding.HashEmbedder{Dimensions: 64}}
+		now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+		idx := New(n, embedder, WithBatchSize(2), WithClock(func() time.Time { return now }))
+
+		Convey("When searching it for the first time", func() {
+			result, err := idx.Search(context.Background(), "dv", "pollution measurements in cities", 2)
+
+			Convey("Then the descriptions should be embedded by batches", func() {
+				So(err, ShouldBeNil)
+				So(n.selects, ShouldEqual, 1)
+				So(embedder.batches, ShouldResemble, []int{2, 1, 1})
+			})
+
+			Convey("Then the k closest resources should be returned", func() {
+				So(err, ShouldBeNil)
+				So(result.RefreshedAt, ShouldEqual, now)
+				So(result.Hits, ShouldHaveLength, 2)
+				So(result.Hits[0].Resource, ShouldEqual, "did:key:a")
+				So(result.Hits[0].Title, ShouldEqual, "Air quality in France")
+				So(result.Hits[0].Description, ShouldEqual,
+					"Hourly measurements of the pollutants in the air of French cities.")
+				So(result.Hits[0].Score, ShouldBeGreaterThan, result.Hits[1].Score)
+			})
+		})
+
+		Convey("When searching it again", func() {
+			_, err := idx.Search(context.Background(), "dv", "air", 1)
+			So(err, ShouldBeNil)
+			_, err = idx.Search(context.Background(), "dv", "water", 1)
+
+			Convey("Then the index should be reused", func() {
+				So(err, ShouldBeNil)
+				So(n.selects, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When refreshing it again and again", func() {
+			_, err := idx.Search(context.Background(), "dv", "air", 1)
+			So(err, ShouldBeNil)
+			So(idx.Refresh(context.Background(), "dv"), ShouldBeNil)
+			So(idx.Refresh(context.Background(), "dv"), ShouldBeNil)
+
+			Convey("Then the refreshes asked too soon should be skipped", func() {
+				So(n.selects, ShouldEqual, 1)
+
+				now = now.Add(DefaultMinRefreshInterval)
+				So(idx.Refresh(context.Background(), "dv"), ShouldBeNil)
+				So(n.selects, ShouldEqual, 2)
+			})
+		})
+
+		Convey("When searching more dataverses than it can hold", func() {
+			idx := New(n, embedder, WithMaxDataverses(1))
+			_, err := idx.Search(context.Background(), "dv", "air", 1)
+			So(err, ShouldBeNil)
+			_, err = idx.Search(context.Background(), "dv2", "air", 1)
+
+			Convey("Then the new dataverses should be refused, without embedding their descriptions", func() {
+				So(err, ShouldWrap, search.ErrTooManyDataverses)
+				So(err, ShouldBeError, "too many indexed dataverses: 1 dataverses are indexed already")
+				So(n.selects, ShouldEqual, 1)
+			})
+		})
+
+		Convey("When the embedder fails", func() {
+			embedder.err = embedding.ErrUnavailable
+			_, err := idx.Search(context.Background(), "dv", "air", 1)
+
+			Convey("Then the error should be returned", func() {
+				So(errors.Is(err, embedding.ErrUnavailable), ShouldBeTrue)
+			})
+		})
+
+		Convey("When the embedder answers fewer embeddings than asked", func() {
+			embedder.drop = 1
+			_, errRefresh := idx.Search(context.Background(), "dv", "air", 1)
+			embedder.drop = 0
+			So(idx.Refresh(context.Background(), "dv"), ShouldBeNil)
+			embedder.drop = 1
+			_, errSearch := idx.Search(context.Background(), "dv", "air", 1)
+
+			Convey("Then the refresh and the search should fail rather than misplace the embeddings", func() {
+				So(errRefresh, ShouldBeError, "embedding backend unavailable: 1 embeddings returned for 2 texts")
+				So(errSearch, ShouldBeError, "embedding backend unavailable: 0 embeddings returned for the query")
+			})
+		})
+	})
+}
@@ -0,0 +1,167 @@
+package tracing
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// InstrumentationName is the name of the tracer of the server.
+const InstrumentationName = "github.com/axone-protocol/axone-mcp"
+
+// DefaultServiceName is the name of the service emitting the spans, when not configured.
+const DefaultServiceName = "axone-mcp"
+
+// Exporters of the spans.
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Attributes of the spans specific to the server.
+const (
+	AttrContractAddress = attribute.Key("axone.contract.address")
+	AttrQueryKind       = attribute.Key("axone.query.kind")
+	AttrResultSize      = attribute.Key("axone.result.size")
+)
+
+// Config configures the export of the spans.
+type Config struct {
+	// Exporter is one of ExporterNone, ExporterOTLP or ExporterStdout.
+	Exporter string
+	// Endpoint is the <host>:<port> of the OTLP gRPC collector, the OTEL_EXPORTER_OTLP_ENDPOINT variable or
+	// localhost:4317 if empty.
+	Endpoint string
+	// Insecure disables TLS when connecting to the OTLP collector.
+	Insecure bool
+	// ServiceName is the name of the service emitting the spans, DefaultServiceName if empty.
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Setup installs the global tracer provider exporting the spans as configured, along with the W3C trace context
+// propagator. It returns the function flushing the pending spans and releasing the exporter.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+	switch config.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		opts := []otlptracegrpc.Option{}
+		if config.Endpoint != "" {
+			opts = append(opts, otlptracegrpc.WithEndpoint(config.Endpoint))
+		}
+		if config.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		// The spans are written to stderr, stdout carrying the messages of the stdio transport.
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(cmp.Or(config.ServiceName, DefaultServiceName)),
+			semconv.ServiceVersion(config.ServiceVersion),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the server, from the global tracer provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(InstrumentationName)
+}
+
+// HTTPHandler continues the trace whose context is carried by the headers of the incoming requests, if any.
+func HTTPHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// InstrumentClientConn returns a connection tracing the calls made through the given one, with the address of the
+// queried contract, the kind of the query and the size of its result for the smart contract queries.
+func InstrumentClientConn(next grpc.ClientConnInterface) grpc.ClientConnInterface {
+	return &clientConn{next: next}
+}
+
+type clientConn struct {
+	next grpc.ClientConnInterface
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (c *clientConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	ctx, span := Tracer().Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.RPCSystemGRPC, semconv.RPCMethod(method)))
+	defer span.End()
+
+	if req, ok := args.(*wasmtypes.QuerySmartContractStateRequest); ok {
+		span.SetAttributes(AttrContractAddress.String(req.Address), AttrQueryKind.String(queryKind(req.QueryData)))
+	}
+
+	err := c.next.Invoke(ctx, method, args, reply, opts...)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(status.Code(err))))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+	if resp, ok := reply.(*wasmtypes.QuerySmartContractStateResponse); ok {
+		span.SetAttributes(AttrResultSize.Int(len(resp.Data)))
+	}
+
+	return nil
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (c *clientConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return c.next.NewStream(ctx, desc, method, opts...)
+}
+
+// queryKind returns the name of the query message of a contract, its single top-level key.
+func queryKind(data []byte) string {
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(data, &msg); err != nil || len(msg) == 0 {
+		return ""
+	}
+
+	return slices.Sorted(maps.Keys(msg))[0]
+}
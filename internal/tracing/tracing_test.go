@@ -0,0 +1,196 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"github.com/axone-protocol/axone-mcp/internal/cache"
+	"github.com/axone-protocol/axone-mcp/internal/mocks"
+	. "github.com/smartystreets/goconvey/convey"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/status"
+
+	grpccodes "google.golang.org/grpc/codes"
+)
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	out := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		out[kv.Key] = kv.Value
+	}
+	return out
+}
+
+func TestSetup(t *testing.T) {
+	Convey("Given the tracing configurations", t, func() {
+		cases := []struct {
+			exporter string
+			wantErr  bool
+		}{
+			{exporter: ""},
+			{exporter: ExporterNone},
+			{exporter: ExporterStdout},
+			{exporter: "zipkin", wantErr: true},
+		}
+		for _, tc := range cases {
+			Convey("When setting up the tracing with the exporter "+tc.exporter, func() {
+				shutdown, err := Setup(context.Background(), Config{Exporter: tc.exporter})
+
+				Convey("Then it should succeed, unless the exporter is unknown", func() {
+					if tc.wantErr {
+						So(err, ShouldBeError, `unknown tracing exporter "zipkin"`)
+						return
+					}
+					So(err, ShouldBeNil)
+					So(shutdown(context.Background()), ShouldBeNil)
+				})
+			})
+		}
+		Reset(func() {
+			otel.SetTracerProvider(noop.NewTracerProvider())
+		})
+	})
+}
+
+func TestInstrumentClientConn(t *testing.T) {
+	Convey("Given a connection traced by a recording tracer provider", t, func() {
+		recorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		Reset(func() {
+			otel.SetTracerProvider(noop.NewTracerProvider())
+		})
+
+		ctrl := gomock.NewController(t)
+		Reset(ctrl.Finish)
+
+		next := mocks.NewMockClientConnInterface(ctrl)
+		cc := InstrumentClientConn(next)
+		req := &wasmtypes.QuerySmartContractStateRequest{
+			Address:   "axone1contract",
+			QueryData: []byte(`{"dataverse":{}}`),
+		}
+
+		Convey("When a smart contract query succeeds", func() {
+			next.EXPECT().Invoke(gomock.Any(), cache.SmartContractStateMethod, req, gomock.Any()).
+				DoAndReturn(func(_ context.Context, _ string, _, reply any, _ ...any) error {
+					reply.(*wasmtypes.QuerySmartContractStateResponse).Data = []byte(`{"name":"dataverse-42"}`)
+					return nil
+				})
+
+			err := cc.Invoke(context.Background(), cache.SmartContractStateMethod, req,
+				&wasmtypes.QuerySmartContractStateResponse{})
+
+			Convey("Then a client span should describe the query and the size of its result", func() {
+				So(err, ShouldBeNil)
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 1)
+				So(spans[0].Name(), ShouldEqual, cache.SmartContractStateMethod)
+				So(spans[0].SpanKind(), ShouldEqual, trace.SpanKindClient)
+				attrs := attributes(spans[0])
+				So(attrs[AttrContractAddress].AsString(), ShouldEqual, "axone1contract")
+				So(attrs[AttrQueryKind].AsString(), ShouldEqual, "dataverse")
+				So(attrs[AttrResultSize].AsInt64(), ShouldEqual, 23)
+				So(attrs["rpc.grpc.status_code"].AsInt64(), ShouldEqual, int64(grpccodes.OK))
+				So(spans[0].Status().Code, ShouldEqual, codes.Unset)
+			})
+		})
+
+		Convey("When a smart contract query fails", func() {
+			next.EXPECT().Invoke(gomock.Any(), cache.SmartContractStateMethod, req, gomock.Any()).
+				Return(status.Error(grpccodes.NotFound, "no such contract"))
+
+			err := cc.Invoke(context.Background(), cache.SmartContractStateMethod, req,
+				&wasmtypes.QuerySmartContractStateResponse{})
+
+			Convey("Then the span should record the error", func() {
+				So(status.Code(err), ShouldEqual, grpccodes.NotFound)
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 1)
+				So(spans[0].Status().Code, ShouldEqual, codes.Error)
+				So(attributes(spans[0])["rpc.grpc.status_code"].AsInt64(), ShouldEqual, int64(grpccodes.NotFound))
+			})
+		})
+
+		Convey("When the call is made within a span", func() {
+			next.EXPECT().Invoke(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+			err := cc.Invoke(ctx, cache.SmartContractStateMethod, req, &wasmtypes.QuerySmartContractStateResponse{})
+			parent.End()
+
+			Convey("Then the span of the call should be its child", func() {
+				So(err, ShouldBeNil)
+				spans := recorder.Ended()
+				So(spans, ShouldHaveLength, 2)
+				So(spans[0].Parent().SpanID(), ShouldEqual, parent.SpanContext().SpanID())
+				So(spans[0].SpanContext().TraceID(), ShouldEqual, parent.SpanContext().TraceID())
+			})
+		})
+	})
+}
+
+func TestQueryKind(t *testing.T) {
+	Convey("Given query messages", t, func() {
+		cases := []struct {
+			data string
+			want string
+		}{
+			{data: `{"dataverse":{}}`, want: "dataverse"},
+			{data: `{"select":{"query":{}}}`, want: "select"},
+			{data: `{}`, want: ""},
+			{data: `not json`, want: ""},
+		}
+		for _, tc := range cases {
+			Convey("When getting the kind of "+tc.data, func() {
+				Convey("Then it should be "+tc.want, func() {
+					So(queryKind([]byte(tc.data)), ShouldEqual, tc.want)
+				})
+			})
+		}
+	})
+}
+
+func TestHTTPHandler(t *testing.T) {
+	Convey("Given a handler continuing the traces of the incoming requests", t, func() {
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		Reset(func() {
+			otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+		})
+
+		var got trace.SpanContext
+		handler := HTTPHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = trace.SpanContextFromContext(r.Context())
+		}))
+
+		Convey("When a request carries a traceparent header", func() {
+			req := httptest.NewRequest(http.MethodPost, "/message", nil)
+			req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			Convey("Then the handler should see the remote span context", func() {
+				So(got.IsRemote(), ShouldBeTrue)
+				So(got.TraceID().String(), ShouldEqual, "4bf92f3577b34da6a3ce929d0e0e4736")
+				So(got.SpanID().String(), ShouldEqual, "00f067aa0ba902b7")
+			})
+		})
+
+		Convey("When a request carries no trace context", func() {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/message", nil))
+
+			Convey("Then the handler should see no span context", func() {
+				So(got.IsValid(), ShouldBeFalse)
+			})
+		})
+	})
+}